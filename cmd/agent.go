@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"github.com/weastur/maf/internal/agent"
 	"github.com/weastur/maf/internal/config"
 
 	"github.com/weastur/maf/internal/agent/worker/fiber"
+	"github.com/weastur/maf/internal/agent/worker/grpc"
+	httpUtils "github.com/weastur/maf/internal/utils/http"
 )
 
 var agentCmd = &cobra.Command{
@@ -22,6 +26,7 @@ It is designed to run on the same host as the MySQL instance.`,
 			LogLevel:  viper.GetString("agent.log.level"),
 			LogPretty: viper.GetBool("agent.log.pretty"),
 			SentryDSN: viper.GetString("agent.sentry.dsn"),
+			Reload:    reloadAgent,
 		}
 
 		fiberConfig := &fiber.Config{
@@ -29,39 +34,108 @@ It is designed to run on the same host as the MySQL instance.`,
 			CertFile:        viper.GetString("agent.http.cert_file"),
 			KeyFile:         viper.GetString("agent.http.key_file"),
 			ClientCertFile:  viper.GetString("agent.http.client_cert_file"),
+			Socket:          viper.GetString("agent.http.socket"),
+			SocketMode:      socketMode("agent.http.socket_mode"),
 			ReadTimeout:     viper.GetDuration("agent.http.read_timeout"),
 			WriteTimeout:    viper.GetDuration("agent.http.write_timeout"),
 			IdleTimeout:     viper.GetDuration("agent.http.idle_timeout"),
 			ShutdownTimeout: viper.GetDuration("agent.http.graceful_shutdown_timeout"),
+			Auth:            newAuthenticator("agent.http.auth", ""),
+			RateLimiter: httpUtils.NewRateLimiter(
+				viper.GetInt("agent.http.rate_limit"),
+				viper.GetDuration("agent.http.rate_limit_window"),
+			),
+		}
+
+		// gRPC API shares the TLS and auth settings with the HTTP API
+		grpcConfig := &grpc.Config{
+			Addr:            viper.GetString("agent.grpc.addr"),
+			CertFile:        fiberConfig.CertFile,
+			KeyFile:         fiberConfig.KeyFile,
+			ClientCertFile:  fiberConfig.ClientCertFile,
+			ShutdownTimeout: viper.GetDuration("agent.grpc.graceful_shutdown_timeout"),
+			Auth:            fiberConfig.Auth,
 		}
 
-		agent := agent.Get(agentConfig, fiberConfig)
+		agent := agent.Get(agentConfig, fiberConfig, grpcConfig)
 		cobra.CheckErr(agent.Init())
 
 		agent.Run()
 	},
 }
 
-func init() {
+// reloadAgent re-reads the config on SIGHUP and applies it, the config is kept only if it's applied
+func reloadAgent(apply func(settings *agent.Reloadable) error) error {
+	var cfg Config = config.Get()
+
+	err := cfg.Reload("agent", func() error {
+		settings, err := reloadableAgentSettings()
+		if err != nil {
+			return err
+		}
+
+		return apply(settings)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+
+	return nil
+}
+
+// reloadableAgentSettings reads the settings the running agent applies on SIGHUP
+func reloadableAgentSettings() (*agent.Reloadable, error) {
 	var cfg Config = config.Get()
 
 	viper := cfg.Viper()
 
+	authConfig, err := readAuthConfig("agent.http.auth")
+	if err != nil {
+		return nil, err
+	}
+
+	return &agent.Reloadable{
+		LogLevel:        viper.GetString("agent.log.level"),
+		Auth:            authConfig,
+		RateLimit:       viper.GetInt("agent.http.rate_limit"),
+		RateLimitWindow: viper.GetDuration("agent.http.rate_limit_window"),
+	}, nil
+}
+
+func init() {
+	var cfg Config = config.Get()
+
 	rootCmd.AddCommand(agentCmd)
 
 	agentCmd.Flags().String("http-addr", ":7070", "Address to listen to")
 	agentCmd.Flags().String("http-cert-file", "", "Path to the cert file (required if key-file is set)")
 	agentCmd.Flags().String("http-key-file", "", "Path to the key file (required if cert-file is set)")
 	agentCmd.Flags().String("http-client-cert-file", "", "Path to the client cert file (for mTLS)")
+	agentCmd.Flags().String(
+		"http-socket", "", "Path to the Unix socket for the local administration without auth, disabled if empty",
+	)
+	agentCmd.Flags().String("http-socket-mode", defaultSocketMode, "File mode of the Unix socket, in octal")
+	agentCmd.Flags().Bool(
+		"http-auth-disabled", false, "Disable the API auth and grant the admin role to everyone, for development only",
+	)
 	agentCmd.Flags().Duration("http-read-timeout", defaultHTTPReadTimeout, "HTTP read timeout")
 	agentCmd.Flags().Duration("http-write-timeout", defaultHTTPWriteTimeout, "HTTP write timeout")
 	agentCmd.Flags().Duration("http-idle-timeout", defaultHTTPIdleTimeout, "HTTP idle timeout")
+	agentCmd.Flags().Int("http-rate-limit", httpUtils.DefaultRateLimit, "Number of requests per client IP in the window")
+	agentCmd.Flags().Duration("http-rate-limit-window", httpUtils.DefaultRateLimitWindow, "Rate limit window")
 	agentCmd.Flags().Duration(
 		"http-graceful-shutdown-timeout",
 		defaultHTTPGracefulShutdownTimeout,
 		"HTTP graceful shutdown timeout",
 	)
 
+	agentCmd.Flags().String("grpc-addr", "", "Address to listen to with the gRPC API, disabled if empty")
+	agentCmd.Flags().Duration(
+		"grpc-graceful-shutdown-timeout",
+		defaultHTTPGracefulShutdownTimeout,
+		"gRPC graceful shutdown timeout",
+	)
+
 	agentCmd.Flags().String("log-level", "info", "Log level (trace, debug, info, warn, error, fatal, panic)")
 	agentCmd.Flags().Bool("log-pretty", false, "Enable pretty logging")
 
@@ -71,17 +145,25 @@ func init() {
 	agentCmd.MarkFlagFilename("key-file")
 	agentCmd.MarkFlagFilename("client-cert-file")
 
-	viper.BindPFlag("agent.http.addr", agentCmd.Flags().Lookup("http-addr"))
-	viper.BindPFlag("agent.http.cert_file", agentCmd.Flags().Lookup("http-cert-file"))
-	viper.BindPFlag("agent.http.key_file", agentCmd.Flags().Lookup("http-key-file"))
-	viper.BindPFlag("agent.http.client_cert_file", agentCmd.Flags().Lookup("http-client-cert-file"))
-	viper.BindPFlag("agent.http.read_timeout", agentCmd.Flags().Lookup("http-read-timeout"))
-	viper.BindPFlag("agent.http.write_timeout", agentCmd.Flags().Lookup("http-write-timeout"))
-	viper.BindPFlag("agent.http.idle_timeout", agentCmd.Flags().Lookup("http-idle-timeout"))
-	viper.BindPFlag("agent.http.graceful_shutdown_timeout", agentCmd.Flags().Lookup("http-graceful-shutdown-timeout"))
-
-	viper.BindPFlag("agent.log.level", agentCmd.Flags().Lookup("log-level"))
-	viper.BindPFlag("agent.log.pretty", agentCmd.Flags().Lookup("log-pretty"))
-
-	viper.BindPFlag("agent.sentry.dsn", agentCmd.Flags().Lookup("sentry-dsn"))
+	bindFlag(cfg, "agent.http.addr", agentCmd.Flags().Lookup("http-addr"))
+	bindFlag(cfg, "agent.http.cert_file", agentCmd.Flags().Lookup("http-cert-file"))
+	bindFlag(cfg, "agent.http.key_file", agentCmd.Flags().Lookup("http-key-file"))
+	bindFlag(cfg, "agent.http.client_cert_file", agentCmd.Flags().Lookup("http-client-cert-file"))
+	bindFlag(cfg, "agent.http.socket", agentCmd.Flags().Lookup("http-socket"))
+	bindFlag(cfg, "agent.http.socket_mode", agentCmd.Flags().Lookup("http-socket-mode"))
+	bindFlag(cfg, "agent.http.auth.disabled", agentCmd.Flags().Lookup("http-auth-disabled"))
+	bindFlag(cfg, "agent.http.read_timeout", agentCmd.Flags().Lookup("http-read-timeout"))
+	bindFlag(cfg, "agent.http.write_timeout", agentCmd.Flags().Lookup("http-write-timeout"))
+	bindFlag(cfg, "agent.http.idle_timeout", agentCmd.Flags().Lookup("http-idle-timeout"))
+	bindFlag(cfg, "agent.http.rate_limit", agentCmd.Flags().Lookup("http-rate-limit"))
+	bindFlag(cfg, "agent.http.rate_limit_window", agentCmd.Flags().Lookup("http-rate-limit-window"))
+	bindFlag(cfg, "agent.http.graceful_shutdown_timeout", agentCmd.Flags().Lookup("http-graceful-shutdown-timeout"))
+
+	bindFlag(cfg, "agent.grpc.addr", agentCmd.Flags().Lookup("grpc-addr"))
+	bindFlag(cfg, "agent.grpc.graceful_shutdown_timeout", agentCmd.Flags().Lookup("grpc-graceful-shutdown-timeout"))
+
+	bindFlag(cfg, "agent.log.level", agentCmd.Flags().Lookup("log-level"))
+	bindFlag(cfg, "agent.log.pretty", agentCmd.Flags().Lookup("log-pretty"))
+
+	bindFlag(cfg, "agent.sentry.dsn", agentCmd.Flags().Lookup("sentry-dsn"))
 }
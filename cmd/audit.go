@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditSince string
+	auditAfter uint64
+	auditLimit int
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit log commands",
+	Long:  `Commands to inspect the audit log of the mutating API calls.`,
+}
+
+var auditListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List audit records",
+	Long: `Returns the page of the audit records of the mutating API calls: who, what, when, request ID and outcome.
+Records are hash-chained, so 'intact' is false if any record of the page is missing or modified.
+Pass 'next' of the response as --after to get the next page.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		since, err := parseSince(auditSince, time.Now())
+		cobra.CheckErr(err)
+
+		client := getServerAPIClient(false)
+		data, err := client.AuditList(since, auditAfter, auditLimit)
+		cobra.CheckErr(err)
+
+		prettyJSON, err := json.MarshalIndent(data, "", "  ")
+		cobra.CheckErr(err)
+
+		fmt.Println(string(prettyJSON))
+	},
+}
+
+// parseSince accepts either RFC 3339 time or a duration back from now, e.g. 24h
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if since, err := time.Parse(time.RFC3339, value); err == nil {
+		return since, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("since must be RFC 3339 time or duration: %w", err)
+	}
+
+	return now.Add(-duration), nil
+}
+
+func init() {
+	serverCmd.AddCommand(auditCmd)
+
+	auditCmd.AddCommand(auditListCmd)
+	auditListCmd.Flags().StringVar(
+		&auditSince, "since", "", "Show records made since RFC 3339 time or duration ago, e.g. 24h (default all)",
+	)
+	auditListCmd.Flags().Uint64Var(&auditAfter, "after", 0, "Show records after this sequence number")
+	auditListCmd.Flags().IntVar(&auditLimit, "limit", 0, "Maximum number of records (default 100)")
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/weastur/maf/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatYAML = "yaml"
+	formatJSON = "json"
+)
+
+var (
+	errUnknownFormat   = errors.New("format must be yaml or json")
+	errSectionRequired = errors.New("--server or --agent is required to pass the flags of the command")
+)
+
+var (
+	configShowServer bool
+	configShowAgent  bool
+	configShowFormat string
+)
+
+// settingsTree nests the settings by the key segments, the leaves are config.Setting
+type settingsTree map[string]any
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Configuration commands",
+	Long:  `Commands to inspect the configuration.`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show [-- server or agent flags]",
+	Short: "Show the effective configuration",
+	Long: `Shows the configuration the server and the agent run with: the defaults, the config file,
+the environment and the flags merged, and the source of every value. The secrets are redacted.
+The flags of the server or agent command go after --, e.g.
+
+  maf config show --server -- --raft-node-id maf-2`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(_ *cobra.Command, args []string) {
+		var cfg Config = config.Get()
+
+		sections := []string{"agent", "server"}
+
+		switch {
+		case configShowServer:
+			sections = []string{"server"}
+
+			cobra.CheckErr(serverCmd.Flags().Parse(args))
+		case configShowAgent:
+			sections = []string{"agent"}
+
+			cobra.CheckErr(agentCmd.Flags().Parse(args))
+		case len(args) > 0:
+			cobra.CheckErr(errSectionRequired)
+		}
+
+		tree := settingsTree{}
+
+		for _, section := range sections {
+			for key, setting := range cfg.Settings(section) {
+				tree.insert(strings.Split(key, "."), setting)
+			}
+		}
+
+		output, err := tree.marshal(configShowFormat)
+		cobra.CheckErr(err)
+
+		fmt.Print(string(output))
+	},
+}
+
+func (t settingsTree) insert(path []string, setting config.Setting) {
+	if len(path) == 1 {
+		t[path[0]] = setting
+
+		return
+	}
+
+	child, ok := t[path[0]].(settingsTree)
+	if !ok {
+		child = settingsTree{}
+		t[path[0]] = child
+	}
+
+	child.insert(path[1:], setting)
+}
+
+func (t settingsTree) marshal(format string) ([]byte, error) {
+	switch format {
+	case formatJSON:
+		output, err := json.MarshalIndent(t, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+
+		return append(output, '\n'), nil
+	case formatYAML:
+		node, err := t.yamlNode()
+		if err != nil {
+			return nil, err
+		}
+
+		var output bytes.Buffer
+
+		encoder := yaml.NewEncoder(&output)
+		encoder.SetIndent(2) //nolint:mnd
+
+		if err := encoder.Encode(node); err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+
+		return output.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownFormat, format)
+	}
+}
+
+// yamlNode keeps the YAML shaped as the config file, with the sources as the comments
+func (t settingsTree) yamlNode() (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: name}
+
+		var valueNode *yaml.Node
+
+		switch value := t[name].(type) {
+		case settingsTree:
+			child, err := value.yamlNode()
+			if err != nil {
+				return nil, err
+			}
+
+			valueNode = child
+		case config.Setting:
+			valueNode = &yaml.Node{}
+			if err := valueNode.Encode(value.Value); err != nil {
+				return nil, fmt.Errorf("failed to marshal %s: %w", name, err)
+			}
+
+			// The comment of a list or a map goes next to its key, it would end up below the first item otherwise
+			if valueNode.Kind == yaml.ScalarNode {
+				valueNode.LineComment = value.Source
+			} else {
+				keyNode.LineComment = value.Source
+			}
+		}
+
+		node.Content = append(node.Content, keyNode, valueNode)
+	}
+
+	return node, nil
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+
+	configCmd.AddCommand(configShowCmd)
+
+	configShowCmd.Flags().BoolVar(&configShowServer, "server", false, "Show the server configuration only")
+	configShowCmd.Flags().BoolVar(&configShowAgent, "agent", false, "Show the agent configuration only")
+	configShowCmd.Flags().StringVar(&configShowFormat, "format", formatYAML, "Output format: yaml or json")
+	configShowCmd.MarkFlagsMutuallyExclusive("server", "agent")
+}
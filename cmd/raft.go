@@ -1,13 +1,32 @@
 package cmd
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/weastur/maf/internal/config"
+	"github.com/weastur/maf/internal/server/worker/raft"
 )
 
-var includeStats bool
+var (
+	includeStats   bool
+	inspectDatadir string
+	inspectLogs    int
+	recoverDatadir string
+	recoverNodeID  string
+	recoverPeers   string
+	recoverDryRun  bool
+	recoverYes     bool
+	tokenTTL       time.Duration
+)
+
+var errRecoveryAborted = errors.New("recovery aborted")
 
 var raftCmd = &cobra.Command{
 	Use:   "raft",
@@ -69,6 +88,83 @@ Make sure you know what you are doing and will have enough servers to keep the q
 	},
 }
 
+var promoteCmd = &cobra.Command{
+	Use:   "promote [serverID]",
+	Short: "Promote server to voter",
+	Long: `Promote the non-voter server to voter, so it takes part in elections and counts towards the quorum.
+Make sure the server has caught up with the leader before promoting it.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := getServerAPIClient(true)
+		cobra.CheckErr(client.RaftPromote(args[0]))
+	},
+}
+
+var demoteCmd = &cobra.Command{
+	Use:   "demote [serverID]",
+	Short: "Demote server to non-voter",
+	Long: `Demote the voter server to non-voter. The server keeps replicating the log, but doesn't
+take part in elections. Make sure you will have enough voters to keep the quorum.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		client := getServerAPIClient(true)
+		cobra.CheckErr(client.RaftDemote(args[0]))
+	},
+}
+
+var leaveCmd = &cobra.Command{
+	Use:   "leave",
+	Short: "Leave the cluster",
+	Long: `Remove the local server from the cluster, so it no longer counts toward the quorum.
+The leader hands over the leadership first. The request is refused if the remaining voters
+can't keep the quorum. Stop the server afterwards, it won't rejoin the cluster by itself until restarted.
+Requires the admin role, and the server API token of the local server must have it too.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		client := getServerAPIClient(false)
+		cobra.CheckErr(client.RaftLeave())
+	},
+}
+
+var transferLeadershipCmd = &cobra.Command{
+	Use:   "transfer-leadership [serverID]",
+	Short: "Transfer leadership",
+	Long: `Move the leadership off the current leader, e.g. before patching it. If the server ID is given,
+the leadership goes to that voter, otherwise to the most up-to-date one.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		var serverID string
+		if len(args) > 0 {
+			serverID = args[0]
+		}
+
+		client := getServerAPIClient(true)
+		cobra.CheckErr(client.RaftTransferLeadership(serverID))
+	},
+}
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Join token commands",
+	Long:  `Commands to manage the tokens new servers must present to join the cluster.`,
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create join token",
+	Long: `Issue a short-lived join token. Pass it to the new servers with --raft-join-token.
+The token can be used by several servers until it expires. It's printed only once and can't be retrieved later.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		client := getServerAPIClient(true)
+		data, err := client.RaftTokenCreate(tokenTTL)
+		cobra.CheckErr(err)
+
+		prettyJSON, err := json.MarshalIndent(data, "", "  ")
+		cobra.CheckErr(err)
+
+		fmt.Println(string(prettyJSON))
+	},
+}
+
 var infoCmd = &cobra.Command{
 	Use:   "info",
 	Short: "Cluster info",
@@ -86,15 +182,127 @@ Information is from the current server, so it is exactly the like local node vie
 	},
 }
 
+var inspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Inspect raft data directory offline",
+	Long: `Open the raft data directory of a stopped server in read-only mode and print the
+last index and term, stored configuration, snapshots metadata and decoded log entries.
+The server must be stopped, otherwise the database is locked.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		datadir := inspectDatadir
+		if datadir == "" {
+			var cfg Config = config.Get()
+
+			datadir = cfg.Viper().GetString("server.raft.datadir")
+		}
+
+		data, err := raft.Inspect(datadir, inspectLogs)
+		cobra.CheckErr(err)
+
+		prettyJSON, err := json.MarshalIndent(data, "", "  ")
+		cobra.CheckErr(err)
+
+		fmt.Println(string(prettyJSON))
+	},
+}
+
+var recoverCmd = &cobra.Command{
+	Use:   "recover",
+	Short: "Recover cluster from lost quorum",
+	Long: `Rewrite the raft configuration of a stopped server to the servers listed in the peers file.
+Use it ONLY when the majority of servers is permanently lost and the cluster can't elect a leader.
+
+The peers file has the following format:
+  [{"id": "maf-1", "address": "10.1.2.3:7081", "non_voter": false}, ...]
+
+First, run the command with --dry-run on every surviving server and compare the printed last index
+and term. The server with the highest values has the most up-to-date data. Then stop all surviving
+servers, run the command with the same peers file on each of them and start them again.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		var cfg Config = config.Get()
+
+		viper := cfg.Viper()
+
+		datadir := recoverDatadir
+		if datadir == "" {
+			datadir = viper.GetString("server.raft.datadir")
+		}
+
+		nodeID := recoverNodeID
+		if nodeID == "" {
+			nodeID = viper.GetString("server.raft.node_id")
+		}
+
+		plan, err := raft.PlanRecovery(datadir, nodeID, recoverPeers)
+		cobra.CheckErr(err)
+
+		prettyJSON, err := json.MarshalIndent(plan, "", "  ")
+		cobra.CheckErr(err)
+
+		fmt.Println(string(prettyJSON))
+
+		if recoverDryRun {
+			return
+		}
+
+		if !recoverYes {
+			fmt.Printf(
+				"Make sure the server is stopped and %s has the most up-to-date data.\nType 'yes' to continue: ",
+				nodeID,
+			)
+
+			answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil || strings.TrimSpace(answer) != "yes" {
+				cobra.CheckErr(errRecoveryAborted)
+			}
+		}
+
+		cobra.CheckErr(raft.Recover(datadir, nodeID, recoverPeers))
+
+		fmt.Println("Cluster configuration recovered, start the server")
+	},
+}
+
 func init() {
 	serverCmd.AddCommand(raftCmd)
 
 	raftCmd.AddCommand(kvCmd)
 	raftCmd.AddCommand(forgetCmd)
+	raftCmd.AddCommand(promoteCmd)
+	raftCmd.AddCommand(demoteCmd)
+	raftCmd.AddCommand(leaveCmd)
+	raftCmd.AddCommand(transferLeadershipCmd)
+	raftCmd.AddCommand(tokenCmd)
 	raftCmd.AddCommand(infoCmd)
+	raftCmd.AddCommand(inspectCmd)
+	raftCmd.AddCommand(recoverCmd)
+
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCreateCmd.Flags().DurationVar(&tokenTTL, "ttl", time.Hour, "Lifetime of the token, at most 24h")
 
 	infoCmd.Flags().BoolVar(&includeStats, "include-stats", false, "Include extended stats")
 
+	inspectCmd.Flags().StringVar(
+		&inspectDatadir, "data-dir", "", "Raft data directory (default is server.raft.datadir from config)",
+	)
+	inspectCmd.Flags().IntVar(
+		&inspectLogs, "logs", 0, "Number of the latest log entries to decode (0 for all, -1 to skip)",
+	)
+	inspectCmd.MarkFlagDirname("data-dir")
+
+	recoverCmd.Flags().StringVar(
+		&recoverDatadir, "data-dir", "", "Raft data directory (default is server.raft.datadir from config)",
+	)
+	recoverCmd.Flags().StringVar(
+		&recoverNodeID, "node-id", "", "Raft node ID of this server (default is server.raft.node_id from config)",
+	)
+	recoverCmd.Flags().StringVar(&recoverPeers, "peers", "", "Path to the peers.json file with surviving servers")
+	recoverCmd.Flags().BoolVar(&recoverDryRun, "dry-run", false, "Only validate the peers file and print local state")
+	recoverCmd.Flags().BoolVar(&recoverYes, "yes", false, "Do not ask for confirmation")
+	recoverCmd.MarkFlagDirname("data-dir")
+	recoverCmd.MarkFlagFilename("peers", "json")
+	recoverCmd.MarkFlagRequired("peers")
+
 	kvCmd.AddCommand(getCmd)
 	kvCmd.AddCommand(setCmd)
 	kvCmd.AddCommand(delCmd)
@@ -4,12 +4,16 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/weastur/maf/internal/config"
 )
 
 type Config interface {
 	Init(cfgFileName string) error
+	Reload(section string, apply func() error) error
+	BindFlag(key string, flag *pflag.Flag) error
+	Settings(section string) map[string]config.Setting
 	Viper() *viper.Viper
 }
 
@@ -20,7 +24,14 @@ var rootCmd = &cobra.Command{
 	Short: "MySQL auto failover",
 	Long: `MySQL auto failover is a high-availability solution for MySQL.
 It is designed to rule out the need for manual intervention in case of a
-failure of the primary node.`,
+failure of the primary node.
+
+Every setting can be set in the config file, with the flag, or with the
+environment variable named after its key with the MAF_ prefix, e.g.
+MAF_SERVER_RAFT_NODE_ID for server.raft.node_id. The flags take precedence
+over the environment, and the environment over the config file.
+The variable with the _FILE suffix, e.g. MAF_SERVER_RAFT_JOIN_TOKEN_FILE,
+reads the value from the file, which suits the secrets mounted into containers.`,
 }
 
 func Execute() {
@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"github.com/weastur/maf/internal/config"
 	"github.com/weastur/maf/internal/server"
 	serverAPIClient "github.com/weastur/maf/internal/server/client"
 	"github.com/weastur/maf/internal/server/worker/fiber"
+	"github.com/weastur/maf/internal/server/worker/grpc"
 	"github.com/weastur/maf/internal/server/worker/raft"
+	"github.com/weastur/maf/internal/server/worker/raft/discovery"
+	httpUtils "github.com/weastur/maf/internal/utils/http"
 )
 
 var serverCmd = &cobra.Command{
@@ -23,6 +28,7 @@ It is designed to run on a separate host.`,
 			LogLevel:  viper.GetString("server.log.level"),
 			LogPretty: viper.GetBool("server.log.pretty"),
 			SentryDSN: viper.GetString("server.sentry.dsn"),
+			Reload:    reloadServer,
 		}
 
 		fiberConfig := &fiber.Config{
@@ -31,38 +37,162 @@ It is designed to run on a separate host.`,
 			CertFile:        viper.GetString("server.http.cert_file"),
 			KeyFile:         viper.GetString("server.http.key_file"),
 			ClientCertFile:  viper.GetString("server.http.client_cert_file"),
+			Socket:          viper.GetString("server.http.socket"),
+			SocketMode:      socketMode("server.http.socket_mode"),
 			ReadTimeout:     viper.GetDuration("server.http.read_timeout"),
 			WriteTimeout:    viper.GetDuration("server.http.write_timeout"),
 			IdleTimeout:     viper.GetDuration("server.http.idle_timeout"),
 			ShutdownTimeout: viper.GetDuration("server.http.graceful_shutdown_timeout"),
+			Auth:            newAuthenticator("server.http.auth", clientToken()),
+			RateLimiter: httpUtils.NewRateLimiter(
+				viper.GetInt("server.http.rate_limit"),
+				viper.GetDuration("server.http.rate_limit_window"),
+			),
+		}
+
+		// gRPC API shares the TLS and auth settings with the HTTP API
+		grpcConfig := &grpc.Config{
+			Addr:            viper.GetString("server.grpc.addr"),
+			CertFile:        fiberConfig.CertFile,
+			KeyFile:         fiberConfig.KeyFile,
+			ClientCertFile:  fiberConfig.ClientCertFile,
+			ShutdownTimeout: viper.GetDuration("server.grpc.graceful_shutdown_timeout"),
+			Auth:            fiberConfig.Auth,
 		}
 
+		peerDiscovery, err := newPeerDiscovery()
+		cobra.CheckErr(err)
+
 		raftConfig := &raft.Config{
-			Addr:      viper.GetString("server.raft.addr"),
-			NodeID:    viper.GetString("server.raft.node_id"),
-			Devmode:   viper.GetBool("server.raft.devmode"),
-			Peers:     viper.GetStringSlice("server.raft.peers"),
-			Datadir:   viper.GetString("server.raft.datadir"),
-			Bootstrap: viper.GetBool("server.raft.bootstrap"),
+			Addr:             viper.GetString("server.raft.addr"),
+			Advertise:        viper.GetString("server.raft.advertise"),
+			NodeID:           viper.GetString("server.raft.node_id"),
+			Devmode:          viper.GetBool("server.raft.devmode"),
+			Peers:            viper.GetStringSlice("server.raft.peers"),
+			Discovery:        peerDiscovery,
+			Datadir:          viper.GetString("server.raft.datadir"),
+			Bootstrap:        viper.GetBool("server.raft.bootstrap"),
+			Nonvoter:         viper.GetBool("server.raft.nonvoter"),
+			JoinToken:        viper.GetString("server.raft.join_token"),
+			LeaveOnTerminate: viper.GetBool("server.raft.leave_on_terminate"),
+			PreferredLeader:  viper.GetString("server.raft.preferred_leader"),
+			Autopilot: &raft.AutopilotConfig{
+				Enabled:                 viper.GetBool("server.raft.autopilot.enabled"),
+				CleanupDeadServers:      viper.GetBool("server.raft.autopilot.cleanup_dead_servers"),
+				DeadServerThreshold:     viper.GetDuration("server.raft.autopilot.dead_server_threshold"),
+				ServerStabilizationTime: viper.GetDuration("server.raft.autopilot.server_stabilization_time"),
+				MaxTrailingLogs:         viper.GetUint64("server.raft.autopilot.max_trailing_logs"),
+			},
+			Tuning: &raft.TuningConfig{
+				HeartbeatTimeout:   viper.GetDuration("server.raft.heartbeat_timeout"),
+				ElectionTimeout:    viper.GetDuration("server.raft.election_timeout"),
+				LeaderLeaseTimeout: viper.GetDuration("server.raft.leader_lease_timeout"),
+				SnapshotInterval:   viper.GetDuration("server.raft.snapshot_interval"),
+				SnapshotThreshold:  viper.GetUint64("server.raft.snapshot_threshold"),
+				TrailingLogs:       viper.GetUint64("server.raft.trailing_logs"),
+				SnapshotRetain:     viper.GetInt("server.raft.snapshot_retain"),
+				TransportMaxPool:   viper.GetInt("server.raft.transport.max_pool"),
+				TransportTimeout:   viper.GetDuration("server.raft.transport.timeout"),
+			},
+			TLS: &raft.TLSConfig{
+				CertFile: viper.GetString("server.raft.tls.cert_file"),
+				KeyFile:  viper.GetString("server.raft.tls.key_file"),
+				CAFile:   viper.GetString("server.raft.tls.ca_file"),
+			},
 			ServerAPITLSConfig: &serverAPIClient.TLSConfig{
 				CertFile:       viper.GetString("server.http.clients.server.cert_file"),
 				KeyFile:        viper.GetString("server.http.clients.server.key_file"),
 				ServerCertFile: viper.GetString("server.http.clients.server.server_cert_file"),
 			},
+			ServerAPIToken: clientToken(),
+			APIAdvertise:   viper.GetString("server.http.advertise"),
 		}
 
-		srv := server.Get(serverConfig, raftConfig, fiberConfig)
+		srv := server.Get(serverConfig, raftConfig, fiberConfig, grpcConfig)
 		cobra.CheckErr(srv.Init())
 
 		srv.Run()
 	},
 }
 
-func init() { //nolint:funlen
+func newPeerDiscovery() (discovery.Provider, error) {
 	var cfg Config = config.Get()
 
 	viper := cfg.Viper()
 
+	provider, err := discovery.New(&discovery.Config{
+		Provider: viper.GetString("server.raft.discovery.provider"),
+		Peers:    viper.GetStringSlice("server.raft.peers"),
+		Scheme:   viper.GetString("server.raft.discovery.scheme"),
+		DNS: &discovery.DNSConfig{
+			Name:   viper.GetString("server.raft.discovery.dns.name"),
+			Record: viper.GetString("server.raft.discovery.dns.record"),
+			Port:   viper.GetUint16("server.raft.discovery.dns.port"),
+		},
+		Consul: &discovery.ConsulConfig{
+			Addr:    viper.GetString("server.raft.discovery.consul.addr"),
+			Service: viper.GetString("server.raft.discovery.consul.service"),
+			Tag:     viper.GetString("server.raft.discovery.consul.tag"),
+			Token:   viper.GetString("server.raft.discovery.consul.token"),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure peer discovery: %w", err)
+	}
+
+	return provider, nil
+}
+
+// reloadServer re-reads the config on SIGHUP and applies it, the config is kept only if it's applied
+func reloadServer(apply func(settings *server.Reloadable) error) error {
+	var cfg Config = config.Get()
+
+	err := cfg.Reload("server", func() error {
+		settings, err := reloadableServerSettings()
+		if err != nil {
+			return err
+		}
+
+		return apply(settings)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+
+	return nil
+}
+
+// reloadableServerSettings reads the settings the running server applies on SIGHUP
+func reloadableServerSettings() (*server.Reloadable, error) {
+	var cfg Config = config.Get()
+
+	viper := cfg.Viper()
+
+	authConfig, err := readAuthConfig("server.http.auth")
+	if err != nil {
+		return nil, err
+	}
+
+	authConfig.PeerToken = clientToken()
+
+	peerDiscovery, err := newPeerDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	return &server.Reloadable{
+		LogLevel:        viper.GetString("server.log.level"),
+		Auth:            authConfig,
+		RateLimit:       viper.GetInt("server.http.rate_limit"),
+		RateLimitWindow: viper.GetDuration("server.http.rate_limit_window"),
+		Peers:           viper.GetStringSlice("server.raft.peers"),
+		Discovery:       peerDiscovery,
+	}, nil
+}
+
+func init() { //nolint:funlen
+	var cfg Config = config.Get()
+
 	rootCmd.AddCommand(serverCmd)
 
 	serverCmd.Flags().String("http-addr", ":7080", "Address to listen to")
@@ -70,6 +200,13 @@ func init() { //nolint:funlen
 	serverCmd.Flags().String("http-cert-file", "", "Path to the cert file (required if key-file is set)")
 	serverCmd.Flags().String("http-key-file", "", "Path to the key file (required if cert-file is set)")
 	serverCmd.Flags().String("http-client-cert-file", "", "Path to the client cert file (for mTLS)")
+	serverCmd.Flags().String(
+		"http-socket", "", "Path to the Unix socket for the local administration without auth, disabled if empty",
+	)
+	serverCmd.Flags().String("http-socket-mode", defaultSocketMode, "File mode of the Unix socket, in octal")
+	serverCmd.Flags().Bool(
+		"http-auth-disabled", false, "Disable the API auth and grant the admin role to everyone, for development only",
+	)
 	serverCmd.Flags().String(
 		"http-clients-server-cert-file",
 		"",
@@ -81,10 +218,15 @@ func init() { //nolint:funlen
 		"Path to the key file of the internal client that will connect to maf server (required if cert-file is set)",
 	)
 	serverCmd.Flags().String(
-		"http-clients-server-server_cert-file",
+		"http-clients-server-server-cert-file",
 		"",
 		"Path to the cert file of the internal client that will connect to maf server to verify it (for mTLS)",
 	)
+	serverCmd.Flags().String(
+		"http-clients-server-token-file",
+		"",
+		"Path to the file with the API token of the internal client that will connect to maf server",
+	)
 	serverCmd.Flags().String(
 		"http-clients-agent-cert-file",
 		"",
@@ -96,30 +238,155 @@ func init() { //nolint:funlen
 		"Path to the key file of the internal client that will connect to maf agent (required if cert-file is set)",
 	)
 	serverCmd.Flags().String(
-		"http-clients-agent-server_cert-file",
+		"http-clients-agent-server-cert-file",
 		"",
 		"Path to the cert file of the internal client that will connect to maf agent to verify it (for mTLS)",
 	)
 	serverCmd.Flags().Duration("http-read-timeout", defaultHTTPReadTimeout, "HTTP read timeout")
 	serverCmd.Flags().Duration("http-write-timeout", defaultHTTPWriteTimeout, "HTTP write timeout")
 	serverCmd.Flags().Duration("http-idle-timeout", defaultHTTPIdleTimeout, "HTTP idle timeout")
+	serverCmd.Flags().Int("http-rate-limit", httpUtils.DefaultRateLimit, "Number of requests per client IP in the window")
+	serverCmd.Flags().Duration("http-rate-limit-window", httpUtils.DefaultRateLimitWindow, "Rate limit window")
 	serverCmd.Flags().Duration(
 		"http-graceful-shutdown-timeout",
 		defaultHTTPGracefulShutdownTimeout,
 		"HTTP graceful shutdown timeout",
 	)
 
+	serverCmd.Flags().String("grpc-addr", "", "Address to listen to with the gRPC API, disabled if empty")
+	serverCmd.Flags().Duration(
+		"grpc-graceful-shutdown-timeout",
+		defaultHTTPGracefulShutdownTimeout,
+		"gRPC graceful shutdown timeout",
+	)
+
 	serverCmd.Flags().String("log-level", "info", "Log level (trace, debug, info, warn, error, fatal, panic)")
 	serverCmd.Flags().Bool("log-pretty", false, "Enable pretty logging")
 
 	serverCmd.Flags().String("sentry-dsn", "", "Sentry DSN")
 
-	serverCmd.Flags().String("raft-addr", "127.0.0.1:7081", "Raft address to listen to. Address should be advertisable.")
+	serverCmd.Flags().String("raft-addr", "127.0.0.1:7081", "Raft address to listen to")
+	serverCmd.Flags().String(
+		"raft-advertise",
+		"",
+		"Raft address to advertise to other servers (default is raft-addr). Must not be a wildcard",
+	)
 	serverCmd.Flags().String("raft-node-id", "", "Raft node ID")
 	serverCmd.Flags().String("raft-data-dir", "/var/lib/maf", "Raft data directory")
 	serverCmd.Flags().Bool("raft-devmode", false, "Store Raft data in memory")
 	serverCmd.Flags().StringArray("raft-peers", []string{}, "Raft peers")
+	serverCmd.Flags().String(
+		"raft-discovery-provider",
+		discovery.ProviderStatic,
+		"Provider to discover Raft peers with: static (raft-peers), dns or consul",
+	)
+	serverCmd.Flags().String("raft-discovery-scheme", "http", "Scheme of the discovered peers API addresses")
+	serverCmd.Flags().String("raft-discovery-dns-name", "", "DNS name to resolve peers from")
+	serverCmd.Flags().String("raft-discovery-dns-record", discovery.RecordA, "DNS record type to look up: a or srv")
+	serverCmd.Flags().Uint16(
+		"raft-discovery-dns-port",
+		defaultServerHTTPPort,
+		"API port of the peers resolved from A records",
+	)
+	serverCmd.Flags().String("raft-discovery-consul-addr", "http://127.0.0.1:8500", "Consul HTTP API address")
+	serverCmd.Flags().String("raft-discovery-consul-service", "maf-server", "Consul service of the maf servers")
+	serverCmd.Flags().String("raft-discovery-consul-tag", "", "Consul service tag to filter the maf servers by")
+	serverCmd.Flags().String("raft-discovery-consul-token", "", "Consul ACL token")
 	serverCmd.Flags().Bool("raft-bootstrap", false, "Bootstrap the Raft cluster")
+	serverCmd.Flags().Bool(
+		"raft-nonvoter",
+		false,
+		"Join the Raft cluster as non-voter, which replicates the log but doesn't take part in elections",
+	)
+	serverCmd.Flags().String(
+		"raft-join-token",
+		"",
+		"Token to join the Raft cluster with, issued by 'maf server raft token create'",
+	)
+	serverCmd.Flags().Bool(
+		"raft-leave-on-terminate",
+		false,
+		"Leave the Raft cluster on shutdown, so the server no longer counts toward the quorum. Needs an admin server token",
+	)
+	serverCmd.Flags().String(
+		"raft-preferred-leader",
+		"",
+		"Raft node ID the leader hands the leadership over to once it's a healthy voter. Set the same on all servers",
+	)
+	serverCmd.Flags().Bool(
+		"raft-autopilot",
+		false,
+		"Enable autopilot, which introduces new servers as non-voters and removes dead servers. "+
+			"Set the same on all servers",
+	)
+	serverCmd.Flags().Bool(
+		"raft-autopilot-cleanup-dead-servers",
+		true,
+		"Automatically remove servers which have been failed longer than the dead server threshold",
+	)
+	serverCmd.Flags().Duration(
+		"raft-autopilot-dead-server-threshold",
+		defaultAutopilotDeadServerThreshold,
+		"Time a server must be failed before autopilot removes it from the cluster",
+	)
+	serverCmd.Flags().Duration(
+		"raft-autopilot-server-stabilization-time",
+		defaultAutopilotServerStabilizationTime,
+		"Time a new server must be healthy before autopilot promotes it to voter",
+	)
+	serverCmd.Flags().Uint64(
+		"raft-autopilot-max-trailing-logs",
+		defaultAutopilotMaxTrailingLogs,
+		"Maximum number of log entries a server can trail the leader by and still be considered caught up",
+	)
+	serverCmd.Flags().Duration(
+		"raft-heartbeat-timeout",
+		defaultRaftHeartbeatTimeout,
+		"Time without contact with the leader before a follower starts an election",
+	)
+	serverCmd.Flags().Duration(
+		"raft-election-timeout",
+		defaultRaftElectionTimeout,
+		"Time without a leader before a candidate starts an election, must not be lower than heartbeat timeout",
+	)
+	serverCmd.Flags().Duration(
+		"raft-leader-lease-timeout",
+		defaultRaftLeaderLeaseTimeout,
+		"Time the leader keeps the leadership without contact with a quorum, must not exceed heartbeat timeout",
+	)
+	serverCmd.Flags().Duration(
+		"raft-snapshot-interval",
+		defaultRaftSnapshotInterval,
+		"How often to check if a snapshot should be taken",
+	)
+	serverCmd.Flags().Uint64(
+		"raft-snapshot-threshold",
+		defaultRaftSnapshotThreshold,
+		"Number of the log entries since the last snapshot to take a new one",
+	)
+	serverCmd.Flags().Uint64(
+		"raft-trailing-logs",
+		defaultRaftTrailingLogs,
+		"Number of the log entries to keep after a snapshot, so slow followers can catch up without it",
+	)
+	serverCmd.Flags().Int("raft-snapshot-retain", defaultRaftSnapshotRetain, "Number of the snapshots to keep on disk")
+	serverCmd.Flags().Int(
+		"raft-transport-max-pool",
+		defaultRaftTransportMaxPool,
+		"Number of the connections to keep open to every peer",
+	)
+	serverCmd.Flags().Duration(
+		"raft-transport-timeout",
+		defaultRaftTransportTimeout,
+		"Timeout of the raft transport I/O operations",
+	)
+	serverCmd.Flags().String("raft-tls-cert-file", "", "Path to the cert file for Raft transport (enables TLS)")
+	serverCmd.Flags().String("raft-tls-key-file", "", "Path to the key file for Raft transport (enables TLS)")
+	serverCmd.Flags().String(
+		"raft-tls-ca-file",
+		"",
+		"Path to the CA cert file to verify Raft peers certificates (enables TLS)",
+	)
 
 	serverCmd.MarkFlagFilename("http-cert-file")
 	serverCmd.MarkFlagFilename("http-key-file")
@@ -127,46 +394,109 @@ func init() { //nolint:funlen
 	serverCmd.MarkFlagFilename("http-clients-server-cert-file")
 	serverCmd.MarkFlagFilename("http-clients-server-key-file")
 	serverCmd.MarkFlagFilename("http-clients-server-server-cert-file")
+	serverCmd.MarkFlagFilename("http-clients-server-token-file")
 	serverCmd.MarkFlagFilename("http-clients-agent-cert-file")
 	serverCmd.MarkFlagFilename("http-clients-agent-key-file")
 	serverCmd.MarkFlagFilename("http-clients-agent-server-cert-file")
+	serverCmd.MarkFlagFilename("raft-tls-cert-file")
+	serverCmd.MarkFlagFilename("raft-tls-key-file")
+	serverCmd.MarkFlagFilename("raft-tls-ca-file")
 
-	viper.BindPFlag("server.http.addr", serverCmd.Flags().Lookup("http-addr"))
-	viper.BindPFlag("server.http.advertise", serverCmd.Flags().Lookup("http-advertise"))
-	viper.BindPFlag("server.http.cert_file", serverCmd.Flags().Lookup("http-cert-file"))
-	viper.BindPFlag("server.http.key_file", serverCmd.Flags().Lookup("http-key-file"))
-	viper.BindPFlag("server.http.client_cert_file", serverCmd.Flags().Lookup("http-client-cert-file"))
+	bindFlag(cfg, "server.http.addr", serverCmd.Flags().Lookup("http-addr"))
+	bindFlag(cfg, "server.http.advertise", serverCmd.Flags().Lookup("http-advertise"))
+	bindFlag(cfg, "server.http.cert_file", serverCmd.Flags().Lookup("http-cert-file"))
+	bindFlag(cfg, "server.http.key_file", serverCmd.Flags().Lookup("http-key-file"))
+	bindFlag(cfg, "server.http.client_cert_file", serverCmd.Flags().Lookup("http-client-cert-file"))
+	bindFlag(cfg, "server.http.socket", serverCmd.Flags().Lookup("http-socket"))
+	bindFlag(cfg, "server.http.socket_mode", serverCmd.Flags().Lookup("http-socket-mode"))
+	bindFlag(cfg, "server.http.auth.disabled", serverCmd.Flags().Lookup("http-auth-disabled"))
 
-	viper.BindPFlag("server.http.clients.server.cert_file", serverCmd.Flags().Lookup("http-clients-server-cert-file"))
-	viper.BindPFlag("server.http.clients.server.key_file", serverCmd.Flags().Lookup("http-clients-server-key-file"))
-	viper.BindPFlag(
+	bindFlag(cfg, "server.http.clients.server.cert_file", serverCmd.Flags().Lookup("http-clients-server-cert-file"))
+	bindFlag(cfg, "server.http.clients.server.key_file", serverCmd.Flags().Lookup("http-clients-server-key-file"))
+	bindFlag(
+		cfg,
 		"server.http.clients.server.server_cert_file",
 		serverCmd.Flags().Lookup("http-clients-server-server-cert-file"),
 	)
-	viper.BindPFlag("server.http.clients.agent.cert_file", serverCmd.Flags().Lookup("http-clients-agent-cert-file"))
-	viper.BindPFlag(
+	bindFlag(cfg, "server.http.clients.server.token_file", serverCmd.Flags().Lookup("http-clients-server-token-file"))
+	bindFlag(cfg, "server.http.clients.agent.cert_file", serverCmd.Flags().Lookup("http-clients-agent-cert-file"))
+	bindFlag(
+		cfg,
 		"server.http.clients.agent.key_file",
 		serverCmd.Flags().Lookup("http-clients-agent-key-file"),
 	)
-	viper.BindPFlag(
+	bindFlag(
+		cfg,
 		"server.http.clients.agent.server_cert_file",
 		serverCmd.Flags().Lookup("http-clients-agent-server-cert-file"),
 	)
 
-	viper.BindPFlag("server.http.read_timeout", serverCmd.Flags().Lookup("http-read-timeout"))
-	viper.BindPFlag("server.http.write_timeout", serverCmd.Flags().Lookup("http-write-timeout"))
-	viper.BindPFlag("server.http.idle_timeout", serverCmd.Flags().Lookup("http-idle-timeout"))
-	viper.BindPFlag("server.http.graceful_shutdown_timeout", serverCmd.Flags().Lookup("http-graceful-shutdown-timeout"))
+	bindFlag(cfg, "server.http.read_timeout", serverCmd.Flags().Lookup("http-read-timeout"))
+	bindFlag(cfg, "server.http.write_timeout", serverCmd.Flags().Lookup("http-write-timeout"))
+	bindFlag(cfg, "server.http.idle_timeout", serverCmd.Flags().Lookup("http-idle-timeout"))
+	bindFlag(cfg, "server.http.rate_limit", serverCmd.Flags().Lookup("http-rate-limit"))
+	bindFlag(cfg, "server.http.rate_limit_window", serverCmd.Flags().Lookup("http-rate-limit-window"))
+	bindFlag(cfg, "server.http.graceful_shutdown_timeout", serverCmd.Flags().Lookup("http-graceful-shutdown-timeout"))
 
-	viper.BindPFlag("server.log.level", serverCmd.Flags().Lookup("log-level"))
-	viper.BindPFlag("server.log.pretty", serverCmd.Flags().Lookup("log-pretty"))
+	bindFlag(cfg, "server.grpc.addr", serverCmd.Flags().Lookup("grpc-addr"))
+	bindFlag(cfg, "server.grpc.graceful_shutdown_timeout", serverCmd.Flags().Lookup("grpc-graceful-shutdown-timeout"))
 
-	viper.BindPFlag("server.sentry.dsn", serverCmd.Flags().Lookup("sentry-dsn"))
+	bindFlag(cfg, "server.log.level", serverCmd.Flags().Lookup("log-level"))
+	bindFlag(cfg, "server.log.pretty", serverCmd.Flags().Lookup("log-pretty"))
 
-	viper.BindPFlag("server.raft.addr", serverCmd.Flags().Lookup("raft-addr"))
-	viper.BindPFlag("server.raft.node_id", serverCmd.Flags().Lookup("raft-node-id"))
-	viper.BindPFlag("server.raft.datadir", serverCmd.Flags().Lookup("raft-data-dir"))
-	viper.BindPFlag("server.raft.devmode", serverCmd.Flags().Lookup("raft-devmode"))
-	viper.BindPFlag("server.raft.peers", serverCmd.Flags().Lookup("raft-peers"))
-	viper.BindPFlag("server.raft.bootstrap", serverCmd.Flags().Lookup("raft-bootstrap"))
+	bindFlag(cfg, "server.sentry.dsn", serverCmd.Flags().Lookup("sentry-dsn"))
+
+	bindFlag(cfg, "server.raft.addr", serverCmd.Flags().Lookup("raft-addr"))
+	bindFlag(cfg, "server.raft.advertise", serverCmd.Flags().Lookup("raft-advertise"))
+	bindFlag(cfg, "server.raft.node_id", serverCmd.Flags().Lookup("raft-node-id"))
+	bindFlag(cfg, "server.raft.datadir", serverCmd.Flags().Lookup("raft-data-dir"))
+	bindFlag(cfg, "server.raft.devmode", serverCmd.Flags().Lookup("raft-devmode"))
+	bindFlag(cfg, "server.raft.peers", serverCmd.Flags().Lookup("raft-peers"))
+	bindFlag(cfg, "server.raft.discovery.provider", serverCmd.Flags().Lookup("raft-discovery-provider"))
+	bindFlag(cfg, "server.raft.discovery.scheme", serverCmd.Flags().Lookup("raft-discovery-scheme"))
+	bindFlag(cfg, "server.raft.discovery.dns.name", serverCmd.Flags().Lookup("raft-discovery-dns-name"))
+	bindFlag(cfg, "server.raft.discovery.dns.record", serverCmd.Flags().Lookup("raft-discovery-dns-record"))
+	bindFlag(cfg, "server.raft.discovery.dns.port", serverCmd.Flags().Lookup("raft-discovery-dns-port"))
+	bindFlag(cfg, "server.raft.discovery.consul.addr", serverCmd.Flags().Lookup("raft-discovery-consul-addr"))
+	bindFlag(cfg, "server.raft.discovery.consul.service", serverCmd.Flags().Lookup("raft-discovery-consul-service"))
+	bindFlag(cfg, "server.raft.discovery.consul.tag", serverCmd.Flags().Lookup("raft-discovery-consul-tag"))
+	bindFlag(cfg, "server.raft.discovery.consul.token", serverCmd.Flags().Lookup("raft-discovery-consul-token"))
+	bindFlag(cfg, "server.raft.bootstrap", serverCmd.Flags().Lookup("raft-bootstrap"))
+	bindFlag(cfg, "server.raft.nonvoter", serverCmd.Flags().Lookup("raft-nonvoter"))
+	bindFlag(cfg, "server.raft.join_token", serverCmd.Flags().Lookup("raft-join-token"))
+	bindFlag(cfg, "server.raft.leave_on_terminate", serverCmd.Flags().Lookup("raft-leave-on-terminate"))
+	bindFlag(cfg, "server.raft.preferred_leader", serverCmd.Flags().Lookup("raft-preferred-leader"))
+	bindFlag(cfg, "server.raft.autopilot.enabled", serverCmd.Flags().Lookup("raft-autopilot"))
+	bindFlag(
+		cfg,
+		"server.raft.autopilot.cleanup_dead_servers",
+		serverCmd.Flags().Lookup("raft-autopilot-cleanup-dead-servers"),
+	)
+	bindFlag(
+		cfg,
+		"server.raft.autopilot.dead_server_threshold",
+		serverCmd.Flags().Lookup("raft-autopilot-dead-server-threshold"),
+	)
+	bindFlag(
+		cfg,
+		"server.raft.autopilot.server_stabilization_time",
+		serverCmd.Flags().Lookup("raft-autopilot-server-stabilization-time"),
+	)
+	bindFlag(
+		cfg,
+		"server.raft.autopilot.max_trailing_logs",
+		serverCmd.Flags().Lookup("raft-autopilot-max-trailing-logs"),
+	)
+	bindFlag(cfg, "server.raft.heartbeat_timeout", serverCmd.Flags().Lookup("raft-heartbeat-timeout"))
+	bindFlag(cfg, "server.raft.election_timeout", serverCmd.Flags().Lookup("raft-election-timeout"))
+	bindFlag(cfg, "server.raft.leader_lease_timeout", serverCmd.Flags().Lookup("raft-leader-lease-timeout"))
+	bindFlag(cfg, "server.raft.snapshot_interval", serverCmd.Flags().Lookup("raft-snapshot-interval"))
+	bindFlag(cfg, "server.raft.snapshot_threshold", serverCmd.Flags().Lookup("raft-snapshot-threshold"))
+	bindFlag(cfg, "server.raft.trailing_logs", serverCmd.Flags().Lookup("raft-trailing-logs"))
+	bindFlag(cfg, "server.raft.snapshot_retain", serverCmd.Flags().Lookup("raft-snapshot-retain"))
+	bindFlag(cfg, "server.raft.transport.max_pool", serverCmd.Flags().Lookup("raft-transport-max-pool"))
+	bindFlag(cfg, "server.raft.transport.timeout", serverCmd.Flags().Lookup("raft-transport-timeout"))
+	bindFlag(cfg, "server.raft.tls.cert_file", serverCmd.Flags().Lookup("raft-tls-cert-file"))
+	bindFlag(cfg, "server.raft.tls.key_file", serverCmd.Flags().Lookup("raft-tls-key-file"))
+	bindFlag(cfg, "server.raft.tls.ca_file", serverCmd.Flags().Lookup("raft-tls-ca-file"))
 }
@@ -3,12 +3,18 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/weastur/maf/internal/config"
 	serverAPIClient "github.com/weastur/maf/internal/server/client"
 	"github.com/weastur/maf/internal/server/worker/fiber"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
 )
 
 var errLeaderAddrNotFound = errors.New("leader API address not found")
@@ -18,6 +24,24 @@ const (
 	defaultHTTPWriteTimeout            = 5 * time.Second
 	defaultHTTPIdleTimeout             = 60 * time.Second
 	defaultHTTPGracefulShutdownTimeout = 5 * time.Second
+	defaultServerHTTPPort              = 7080
+	defaultSocketMode                  = "0600"
+
+	defaultAutopilotDeadServerThreshold     = 30 * time.Minute
+	defaultAutopilotServerStabilizationTime = 10 * time.Second
+	defaultAutopilotMaxTrailingLogs         = 250
+
+	defaultRaftHeartbeatTimeout   = time.Second
+	defaultRaftElectionTimeout    = time.Second
+	defaultRaftLeaderLeaseTimeout = 500 * time.Millisecond
+	defaultRaftSnapshotInterval   = 120 * time.Second
+	defaultRaftSnapshotThreshold  = 8192
+	defaultRaftTrailingLogs       = 10240
+	defaultRaftSnapshotRetain     = 2
+	defaultRaftTransportMaxPool   = 3
+	defaultRaftTransportTimeout   = 10 * time.Second
+
+	tokenEnvVar = "MAF_TOKEN"
 )
 
 type ServerAPIClient interface {
@@ -25,7 +49,13 @@ type ServerAPIClient interface {
 	RaftKVSet(key, value string) error
 	RaftKVDelete(key string) error
 	RaftForget(serverID string) error
+	RaftPromote(serverID string) error
+	RaftDemote(serverID string) error
+	RaftLeave() error
+	RaftTransferLeadership(serverID string) error
+	RaftTokenCreate(ttl time.Duration) (any, error)
 	RaftInfo(includeStats bool) (any, error)
+	AuditList(since time.Time, after uint64, limit int) (any, error)
 }
 
 func clientTLSConfig() *serverAPIClient.TLSConfig {
@@ -40,14 +70,75 @@ func clientTLSConfig() *serverAPIClient.TLSConfig {
 	}
 }
 
-func readLeaderAddr() (string, error) {
+// clientToken returns the API token for the server client. MAF_TOKEN takes precedence over the config
+func clientToken() string {
+	if token := os.Getenv(tokenEnvVar); token != "" {
+		return token
+	}
+
 	var cfg Config = config.Get()
 
 	viper := cfg.Viper()
 
-	tlsConfig := clientTLSConfig()
+	if tokenFile := viper.GetString("server.http.clients.server.token_file"); tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		cobra.CheckErr(err)
+
+		return strings.TrimSpace(string(data))
+	}
+
+	return viper.GetString("server.http.clients.server.token")
+}
+
+// bindFlag binds the flag to the setting and mentions the environment variable of the setting in the flag usage
+func bindFlag(cfg Config, key string, flag *pflag.Flag) {
+	if err := cfg.BindFlag(key, flag); err != nil {
+		return
+	}
+
+	flag.Usage += fmt.Sprintf(" [env %s]", config.EnvName(key))
+}
+
+func readAuthConfig(key string) (*v1alphaUtils.AuthConfig, error) {
+	var cfg Config = config.Get()
+
+	var authConfig v1alphaUtils.AuthConfig
+
+	if err := cfg.Viper().UnmarshalKey(key, &authConfig); err != nil {
+		return nil, fmt.Errorf("failed to read auth config: %w", err)
+	}
+
+	// the flag and the environment variable aren't seen by UnmarshalKey of the parent key
+	authConfig.Disabled = cfg.Viper().GetBool(key + ".disabled")
+
+	return &authConfig, nil
+}
+
+// newAuthenticator trusts the peerToken as the one of the other servers, agents don't have peers
+func newAuthenticator(key, peerToken string) *v1alphaUtils.Authenticator {
+	authConfig, err := readAuthConfig(key)
+	cobra.CheckErr(err)
 
-	client := serverAPIClient.NewWithAutoTLS(viper.GetString("server.http.advertise"), tlsConfig, false)
+	authConfig.PeerToken = peerToken
+
+	auth, err := v1alphaUtils.NewAuthenticator(authConfig)
+	cobra.CheckErr(err)
+
+	return auth
+}
+
+// socketMode parses the octal file mode of the Unix socket, it's validated with the rest of the config
+func socketMode(key string) fs.FileMode {
+	var cfg Config = config.Get()
+
+	mode, err := strconv.ParseUint(cfg.Viper().GetString(key), 8, 32)
+	cobra.CheckErr(err)
+
+	return fs.FileMode(mode)
+}
+
+func readLeaderAddr() (string, error) {
+	client := newLocalServerAPIClient()
 
 	addr, ok, err := client.RaftKVGet(fiber.LeaderAPIAddrKey)
 	if err != nil {
@@ -62,22 +153,42 @@ func readLeaderAddr() (string, error) {
 }
 
 func getServerAPIClient(leader bool) ServerAPIClient {
-	var addr string
+	if !leader {
+		return newLocalServerAPIClient()
+	}
 
-	var err error
+	addr, err := readLeaderAddr()
+	cobra.CheckErr(err)
 
-	if leader {
-		addr, err = readLeaderAddr()
-		cobra.CheckErr(err)
-	} else {
-		var cfg Config = config.Get()
+	return newServerAPIClient(addr)
+}
 
-		viper := cfg.Viper()
+// newLocalServerAPIClient talks to the server on this host. The Unix socket is preferred if configured,
+// since it needs neither the certs nor the token
+func newLocalServerAPIClient() *serverAPIClient.Client {
+	var cfg Config = config.Get()
+
+	viper := cfg.Viper()
 
-		addr = viper.GetString("server.http.advertise")
+	socket := viper.GetString("server.http.socket")
+	if socket == "" {
+		return newServerAPIClient(viper.GetString("server.http.advertise"))
 	}
 
-	tlsConfig := clientTLSConfig()
+	client := serverAPIClient.NewWithSocket(socket, false)
+
+	_, err := client.Negotiate()
+	cobra.CheckErr(err)
+
+	return client
+}
+
+// newServerAPIClient creates the client speaking the newest API version supported by the server
+func newServerAPIClient(addr string) *serverAPIClient.Client {
+	client := serverAPIClient.NewWithAutoTLS(addr, clientTLSConfig(), false).SetAuthToken(clientToken())
+
+	_, err := client.Negotiate()
+	cobra.CheckErr(err)
 
-	return serverAPIClient.NewWithAutoTLS(addr, tlsConfig, false)
+	return client
 }
@@ -2,14 +2,19 @@ package agent
 
 import (
 	"fmt"
+	"os"
+	"os/signal"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/rs/zerolog/log"
 
 	"github.com/weastur/maf/internal/agent/worker/fiber"
+	"github.com/weastur/maf/internal/agent/worker/grpc"
 
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
 	loggingUtils "github.com/weastur/maf/internal/utils/logging"
 	sentryWrapper "github.com/weastur/maf/internal/utils/sentry"
 
@@ -35,19 +40,32 @@ type Death interface {
 	WaitForDeathWithFunc(f func())
 }
 
+// Reloadable are the settings the running agent applies on SIGHUP without restart
+type Reloadable struct {
+	LogLevel        string
+	Auth            *v1alphaUtils.AuthConfig
+	RateLimit       int
+	RateLimitWindow time.Duration
+}
+
 type Config struct {
 	LogLevel  string
 	LogPretty bool
 	SentryDSN string
+	// Reload re-reads the config on SIGHUP and applies it, the config is kept only if it's applied.
+	// The reload is disabled if it's nil
+	Reload func(apply func(settings *Reloadable) error) error
 }
 
 type Agent struct {
 	config      *Config
 	fiberConfig *fiber.Config
+	grpcConfig  *grpc.Config
 	sentry      Sentry
 	workers     []Worker
 	death       Death
 	wg          sync.WaitGroup
+	hup         chan os.Signal
 }
 
 var (
@@ -58,11 +76,13 @@ var (
 func Get(
 	config *Config,
 	fiberConfig *fiber.Config,
+	grpcConfig *grpc.Config,
 ) *Agent {
 	once.Do(func() {
 		instance = &Agent{
 			config:      config,
 			fiberConfig: fiberConfig,
+			grpcConfig:  grpcConfig,
 			death:       DEATH.NewDeath(SYS.SIGINT, SYS.SIGTERM),
 			wg:          sync.WaitGroup{},
 		}
@@ -74,6 +94,11 @@ func Get(
 func (a *Agent) onDeath() {
 	log.Trace().Msg("Death callback called")
 
+	if a.hup != nil {
+		signal.Stop(a.hup)
+		close(a.hup)
+	}
+
 	slices.Reverse(a.workers)
 
 	for _, worker := range a.workers {
@@ -101,6 +126,16 @@ func (a *Agent) Init() error {
 	fiberWorker := fiber.New(a.fiberConfig, a.sentry.Fork("fiber"))
 	a.workers = []Worker{fiberWorker}
 
+	// gRPC API is optional, it's served only if the address is set
+	if a.grpcConfig != nil && a.grpcConfig.Addr != "" {
+		grpcWorker, err := grpc.New(a.grpcConfig, a.sentry.Fork("grpc"))
+		if err != nil {
+			return fmt.Errorf("failed to run agent: %w", err)
+		}
+
+		a.workers = append(a.workers, grpcWorker)
+	}
+
 	return nil
 }
 
@@ -111,5 +146,54 @@ func (a *Agent) Run() {
 		worker.Run(&a.wg)
 	}
 
+	if a.config != nil && a.config.Reload != nil {
+		a.watchReload()
+	}
+
 	a.death.WaitForDeathWithFunc(a.onDeath)
 }
+
+func (a *Agent) watchReload() {
+	a.hup = make(chan os.Signal, 1)
+	signal.Notify(a.hup, SYS.SIGHUP)
+
+	go func() {
+		for range a.hup {
+			a.reload()
+		}
+	}()
+}
+
+// reload applies the reloaded config to the running workers. The config is rejected as a whole
+// if it's invalid, changes the settings which require a restart, e.g. the listen addresses,
+// or can't be applied, e.g. enables or disables the auth.
+func (a *Agent) reload() {
+	log.Info().Msg("Reloading config")
+
+	if err := a.config.Reload(a.apply); err != nil {
+		log.Error().Err(err).Msg("Config reload rejected, keeping the current config")
+
+		return
+	}
+
+	log.Info().Msg("Config reloaded")
+}
+
+func (a *Agent) apply(settings *Reloadable) error {
+	// The credentials go first, since they are the only settings which may still fail to apply
+	if a.fiberConfig.Auth != nil && settings.Auth != nil {
+		if err := a.fiberConfig.Auth.Update(settings.Auth); err != nil {
+			return fmt.Errorf("failed to update auth: %w", err)
+		}
+	}
+
+	if err := loggingUtils.SetLevel(settings.LogLevel); err != nil {
+		return fmt.Errorf("failed to set log level: %w", err)
+	}
+
+	if a.fiberConfig.RateLimiter != nil {
+		a.fiberConfig.RateLimiter.Set(settings.RateLimit, settings.RateLimitWindow)
+	}
+
+	return nil
+}
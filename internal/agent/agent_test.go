@@ -1,9 +1,13 @@
 package agent
 
 import (
+	"errors"
 	"os"
+	"os/signal"
 	"sync"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/rs/zerolog"
@@ -12,6 +16,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/weastur/maf/internal/agent/worker/fiber"
+	"github.com/weastur/maf/internal/agent/worker/grpc"
+	httpUtils "github.com/weastur/maf/internal/utils/http"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
 	sentryWrapper "github.com/weastur/maf/internal/utils/sentry"
 )
 
@@ -81,15 +88,17 @@ func TestGet(t *testing.T) {
 		SentryDSN: "",
 	}
 	fiberConfig := &fiber.Config{}
+	grpcConfig := &grpc.Config{}
 
-	agentInstance := Get(config, fiberConfig)
+	agentInstance := Get(config, fiberConfig, grpcConfig)
 
 	assert.NotNil(t, agentInstance)
 
 	assert.Equal(t, config, agentInstance.config)
 	assert.Equal(t, fiberConfig, agentInstance.fiberConfig)
+	assert.Equal(t, grpcConfig, agentInstance.grpcConfig)
 
-	secondInstance := Get(nil, nil)
+	secondInstance := Get(nil, nil, nil)
 
 	assert.Equal(t, agentInstance, secondInstance)
 }
@@ -153,14 +162,90 @@ func TestInit(t *testing.T) {
 		SentryDSN: "https://examplePublicKey@o0.ingest.sentry.io/0",
 	}
 	fiberConfig := &fiber.Config{}
+	grpcConfig := &grpc.Config{Addr: "127.0.0.1:0"}
 
 	agent := &Agent{
 		config:      config,
 		fiberConfig: fiberConfig,
+		grpcConfig:  grpcConfig,
 	}
 
 	err := agent.Init()
 
 	require.NoError(t, err)
-	assert.Len(t, agent.workers, 1)
+	assert.Len(t, agent.workers, 2)
+}
+
+func TestReload(t *testing.T) {
+	auth, err := v1alphaUtils.NewAuthenticator(&v1alphaUtils.AuthConfig{
+		Tokens: []v1alphaUtils.TokenConfig{{Name: "old", Role: "read", Token: "old-token"}},
+	})
+	require.NoError(t, err)
+
+	settings := &Reloadable{
+		LogLevel: "warn",
+		Auth: &v1alphaUtils.AuthConfig{
+			Tokens: []v1alphaUtils.TokenConfig{{Name: "new", Role: "admin", Token: "new-token"}},
+		},
+		RateLimit:       10,
+		RateLimitWindow: time.Minute,
+	}
+
+	reloaded := make(chan struct{}, 1)
+
+	agent := &Agent{
+		config: &Config{
+			Reload: func(apply func(settings *Reloadable) error) error {
+				reloaded <- struct{}{}
+
+				return apply(settings)
+			},
+		},
+		fiberConfig: &fiber.Config{Auth: auth, RateLimiter: httpUtils.NewRateLimiter(100, time.Second)},
+	}
+
+	t.Cleanup(func() { zerolog.SetGlobalLevel(zerolog.Disabled) })
+
+	agent.watchReload()
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "config is not reloaded on SIGHUP")
+	}
+
+	assert.Eventually(t, func() bool {
+		_, ok := auth.Authenticate("new-token")
+
+		return ok && zerolog.GlobalLevel() == zerolog.WarnLevel
+	}, time.Second, 10*time.Millisecond)
+
+	// The rejected config leaves the current settings as they are
+	agent.config.Reload = func(_ func(settings *Reloadable) error) error {
+		return errors.New("unsafe change")
+	}
+	agent.reload()
+
+	_, ok := auth.Authenticate("new-token")
+	assert.True(t, ok)
+
+	// The auth mode change fails to apply, so the config reports the reload as failed to roll it back
+	var applyErr error
+
+	agent.config.Reload = func(apply func(settings *Reloadable) error) error {
+		applyErr = apply(&Reloadable{LogLevel: "debug", Auth: &v1alphaUtils.AuthConfig{Disabled: true}})
+
+		return applyErr
+	}
+	agent.reload()
+
+	require.ErrorIs(t, applyErr, v1alphaUtils.ErrAuthModeChange)
+	assert.Equal(t, zerolog.WarnLevel, zerolog.GlobalLevel())
+
+	_, ok = auth.Authenticate("new-token")
+	assert.True(t, ok)
+
+	signal.Stop(agent.hup)
+	close(agent.hup)
 }
@@ -1,6 +1,7 @@
 package fiber
 
 import (
+	"io/fs"
 	"sync"
 	"time"
 
@@ -10,11 +11,12 @@ import (
 	"github.com/weastur/maf/internal/agent/worker/fiber/http/api/v1alpha"
 	"github.com/weastur/maf/internal/utils"
 	httpUtils "github.com/weastur/maf/internal/utils/http"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
 	"github.com/weastur/maf/internal/utils/logging"
 )
 
 type API interface {
-	Init(topRouter fiber.Router, logger zerolog.Logger)
+	Init(topRouter fiber.Router, logger zerolog.Logger, auth *v1alphaUtils.Authenticator)
 }
 
 type Sentry interface {
@@ -26,10 +28,14 @@ type Config struct {
 	CertFile        string
 	KeyFile         string
 	ClientCertFile  string
+	Socket          string
+	SocketMode      fs.FileMode
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	IdleTimeout     time.Duration
 	ShutdownTimeout time.Duration
+	Auth            *v1alphaUtils.Authenticator
+	RateLimiter     *httpUtils.RateLimiter
 }
 
 type Fiber struct {
@@ -60,7 +66,8 @@ func New(config *Config, sentry Sentry) *Fiber {
 			ErrorHandler:          httpUtils.ErrorHandler,
 		},
 	)
-	httpUtils.AttachGenericMiddlewares(f.app, f.logger, f)
+	httpUtils.AttachGenericMiddlewares(f.app, f.logger, f, f.config.RateLimiter)
+	utils.ConfigureMetrics(f.app)
 	f.app.Hooks().OnShutdown(func() error {
 		f.logger.Info().Msg("Shutting down agent handler")
 
@@ -71,7 +78,7 @@ func New(config *Config, sentry Sentry) *Fiber {
 
 	var v1AlphaInstance API = v1alpha.Get()
 
-	v1AlphaInstance.Init(api, f.logger)
+	v1AlphaInstance.Init(api, f.logger, f.config.Auth)
 
 	return f
 }
@@ -91,6 +98,10 @@ func (f *Fiber) IsReady(_ *fiber.Ctx) bool {
 func (f *Fiber) Run(wg *sync.WaitGroup) {
 	f.logger.Info().Msg("Running")
 
+	if f.config.Auth.Disabled() {
+		f.logger.Warn().Msg("API auth is DISABLED, every client gets the admin role. Never use it outside of the development")
+	}
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -102,6 +113,18 @@ func (f *Fiber) Run(wg *sync.WaitGroup) {
 			f.logger.Error().Err(err).Msg("failed to listen")
 		}
 	}()
+
+	if f.config.Socket != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer f.sentry.Recover()
+
+			if err := httpUtils.ListenSocket(f.app, f.logger, f.config.Socket, f.config.SocketMode); err != nil {
+				f.logger.Error().Err(err).Msg("failed to listen on socket")
+			}
+		}()
+	}
 }
 
 func (f *Fiber) Stop() {
@@ -3,6 +3,7 @@ package fiber
 import (
 	"errors"
 	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -140,6 +141,41 @@ func TestFiber_Run(t *testing.T) {
 
 		mockSentry.AssertCalled(t, "Recover")
 	})
+
+	t.Run("serve on socket", func(t *testing.T) {
+		t.Parallel()
+
+		mockSentry := new(MockSentry)
+		mockSentry.On("Recover").Return()
+
+		socket := filepath.Join(t.TempDir(), "maf.sock")
+
+		f := &Fiber{
+			config: &Config{
+				Addr:            "invalid_address",
+				Socket:          socket,
+				SocketMode:      0o600,
+				ShutdownTimeout: 5 * time.Second,
+			},
+			app:    fiber.New(),
+			logger: log.With().Logger(),
+			sentry: mockSentry,
+		}
+
+		wg := &sync.WaitGroup{}
+		f.Run(wg)
+
+		assert.Eventually(t, func() bool {
+			_, err := os.Stat(socket)
+
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+
+		f.Stop()
+		wg.Wait()
+
+		assert.NoFileExists(t, socket)
+	})
 }
 
 func TestNew(t *testing.T) {
@@ -55,10 +55,10 @@ func Get() *APIV1Alpha {
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name X-Auth-Token
-// @description API key for the agent. For now, only 'root' is allowed
+// @description API token bound to a role: read, operator or admin. Optional for mapped client certs
 // @externalDocs.description Find out more about MAF on GitHub
 // @externalDocs.url https://github.com/weastur/maf/wiki
-func (api *APIV1Alpha) Init(topRouter fiber.Router, logger zerolog.Logger) {
+func (api *APIV1Alpha) Init(topRouter fiber.Router, logger zerolog.Logger, auth *v1alphaUtils.Authenticator) {
 	router := httpUtils.APIVersionGroup(topRouter, api.version)
 
 	swaggerContent, _ := swaggerJSON.ReadFile("swagger.json")
@@ -78,7 +78,7 @@ func (api *APIV1Alpha) Init(topRouter fiber.Router, logger zerolog.Logger) {
 		return c.Next()
 	})
 
-	router.Use(v1alphaUtils.AuthMiddleware())
+	router.Use(v1alphaUtils.AuthMiddleware(auth))
 
 	router.Get("/version", v1alphaUtils.VersionHandler)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
 )
 
 func TestAPIV1Alpha_Init(t *testing.T) {
@@ -23,7 +24,12 @@ func TestAPIV1Alpha_Init(t *testing.T) {
 		version: "v1alpha",
 		prefix:  "/v1alpha",
 	}
-	api.Init(app.Group("/api"), logger)
+	auth, err := v1alphaUtils.NewAuthenticator(&v1alphaUtils.AuthConfig{Tokens: []v1alphaUtils.TokenConfig{
+		{Name: "admin", Role: "admin", Token: "admin-token"},
+	}})
+	require.NoError(t, err)
+
+	api.Init(app.Group("/api"), logger, auth)
 
 	t.Run("Swagger Docs Endpoint", func(t *testing.T) {
 		t.Parallel()
@@ -53,7 +59,7 @@ func TestAPIV1Alpha_Init(t *testing.T) {
 		req, _ := http.NewRequest(fiber.MethodGet, "/api/v1alpha/protected", nil)
 		resp, _ := app.Test(req, -1)
 
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
 		body, _ := io.ReadAll(resp.Body)
 
 		var response map[string]any
@@ -67,7 +73,7 @@ func TestAPIV1Alpha_Init(t *testing.T) {
 		t.Parallel()
 
 		req, _ := http.NewRequest(fiber.MethodGet, "/api/v1alpha/invalid", nil)
-		req.Header.Set("X-Auth-Token", "root")
+		req.Header.Set("X-Auth-Token", "admin-token")
 		resp, _ := app.Test(req, -1)
 
 		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
@@ -124,7 +130,7 @@ func TestAPIV1Alpha_ErrorHandler(t *testing.T) {
 		req, _ := http.NewRequest(fiber.MethodGet, "/error", nil)
 		resp, _ := app.Test(req, -1)
 
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 		body, _ := io.ReadAll(resp.Body)
 
 		var response map[string]any
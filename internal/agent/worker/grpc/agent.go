@@ -0,0 +1,31 @@
+package grpc
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/weastur/maf/internal/utils"
+	apiV1 "github.com/weastur/maf/internal/utils/grpc/api/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type agentService struct {
+	apiV1.UnimplementedAgentServer
+
+	startedAt time.Time
+}
+
+func newAgentService() *agentService {
+	return &agentService{startedAt: time.Now().UTC()}
+}
+
+func (s *agentService) GetStatus(_ context.Context, _ *apiV1.GetStatusRequest) (*apiV1.GetStatusResponse, error) {
+	hostname, _ := os.Hostname()
+
+	return &apiV1.GetStatusResponse{
+		Version:   utils.AppVersion(),
+		Hostname:  hostname,
+		StartedAt: timestamppb.New(s.startedAt),
+	}, nil
+}
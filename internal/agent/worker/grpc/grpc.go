@@ -0,0 +1,92 @@
+package grpc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/weastur/maf/internal/utils/certs"
+	grpcUtils "github.com/weastur/maf/internal/utils/grpc"
+	apiV1 "github.com/weastur/maf/internal/utils/grpc/api/v1"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+	"github.com/weastur/maf/internal/utils/logging"
+	"google.golang.org/grpc"
+)
+
+type Config struct {
+	Addr            string
+	CertFile        string
+	KeyFile         string
+	ClientCertFile  string
+	ShutdownTimeout time.Duration
+	Auth            *v1alphaUtils.Authenticator
+}
+
+type Sentry interface {
+	Recover()
+}
+
+type GRPC struct {
+	config *Config
+	server *grpc.Server
+	certs  *certs.Store
+	logger zerolog.Logger
+	sentry Sentry
+}
+
+var methodRoles = grpcUtils.MethodRoles{
+	apiV1.Agent_GetStatus_FullMethodName: v1alphaUtils.RoleRead,
+}
+
+func New(config *Config, sentry Sentry) (*GRPC, error) {
+	log.Trace().Msg("Configuring grpc worker")
+
+	g := &GRPC{
+		config: config,
+		logger: log.With().Str(logging.ComponentCtxKey, "grpc").Logger(),
+		sentry: sentry,
+	}
+
+	store, err := certs.NewServerStore(config.CertFile, config.KeyFile, config.ClientCertFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certs: %w", err)
+	}
+
+	g.certs = store
+	g.server = grpcUtils.NewServer(&grpcUtils.Config{
+		Certs: store,
+		Auth:  config.Auth,
+		Roles: methodRoles,
+	}, g.logger)
+	apiV1.RegisterAgentServer(g.server, newAgentService())
+
+	return g, nil
+}
+
+func (g *GRPC) Run(wg *sync.WaitGroup) {
+	g.logger.Info().Msg("Running")
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer g.sentry.Recover()
+
+		if err := grpcUtils.Serve(g.server, g.logger, g.config.Addr); err != nil {
+			g.logger.Error().Err(err).Msg("failed to listen")
+		}
+	}()
+}
+
+func (g *GRPC) Stop() {
+	g.logger.Info().Msg("Stopping")
+
+	if err := grpcUtils.Stop(g.server, g.config.ShutdownTimeout); err != nil {
+		g.logger.Error().Err(err).Msg("failed to shutdown grpc server")
+	}
+
+	if g.certs != nil {
+		g.certs.Close()
+	}
+}
@@ -0,0 +1,155 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/weastur/maf/internal/utils"
+	grpcUtils "github.com/weastur/maf/internal/utils/grpc"
+	apiV1 "github.com/weastur/maf/internal/utils/grpc/api/v1"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testBufSize = 1024 * 1024
+
+type MockSentry struct {
+	mock.Mock
+}
+
+func (m *MockSentry) Recover() {
+	m.Called()
+}
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	log.Logger = log.Output(zerolog.Nop())
+
+	os.Exit(m.Run())
+}
+
+// startTestServer serves the gRPC API in memory and returns the client connection to it
+func startTestServer(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+
+	auth, err := v1alphaUtils.NewAuthenticator(&v1alphaUtils.AuthConfig{Tokens: []v1alphaUtils.TokenConfig{
+		{Name: "reader", Role: "read", Token: "read-token"},
+	}})
+	require.NoError(t, err)
+
+	g, err := New(&Config{ShutdownTimeout: time.Second, Auth: auth}, new(MockSentry))
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(testBufSize)
+
+	go func() {
+		_ = g.server.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		g.server.Stop()
+	})
+
+	return conn
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful initialization", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := New(&Config{Addr: "127.0.0.1:0"}, new(MockSentry))
+		require.NoError(t, err)
+
+		assert.NotNil(t, g.server)
+		assert.Contains(t, g.server.GetServiceInfo(), "maf.v1.Agent")
+		assert.NotContains(t, g.server.GetServiceInfo(), "maf.v1.KV")
+	})
+
+	t.Run("invalid cert", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(&Config{CertFile: "missing.pem", KeyFile: "missing.pem"}, new(MockSentry))
+		require.Error(t, err)
+	})
+}
+
+func TestGRPC_Run(t *testing.T) {
+	t.Parallel()
+
+	mockSentry := new(MockSentry)
+	mockSentry.On("Recover").Return()
+
+	g, err := New(&Config{Addr: "invalid_address"}, mockSentry)
+	require.NoError(t, err)
+
+	wg := &sync.WaitGroup{}
+
+	assert.NotPanics(t, func() {
+		g.Run(wg)
+		wg.Wait()
+	}, "Run should not panic even if Listen returns an error")
+
+	mockSentry.AssertCalled(t, "Recover")
+}
+
+func TestGRPC_Stop(t *testing.T) {
+	t.Parallel()
+
+	g, err := New(&Config{ShutdownTimeout: time.Second}, new(MockSentry))
+	require.NoError(t, err)
+
+	assert.NotPanics(t, g.Stop)
+}
+
+func TestAgentService_GetStatus(t *testing.T) {
+	t.Parallel()
+
+	conn := startTestServer(t)
+
+	t.Run("Authenticated", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), grpcUtils.AuthMetadataKey, "read-token")
+
+		resp, err := apiV1.NewAgentClient(conn).GetStatus(ctx, &apiV1.GetStatusRequest{})
+		require.NoError(t, err)
+
+		hostname, _ := os.Hostname()
+		assert.Equal(t, utils.AppVersion(), resp.GetVersion())
+		assert.Equal(t, hostname, resp.GetHostname())
+		assert.WithinDuration(t, time.Now(), resp.GetStartedAt().AsTime(), time.Minute)
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := apiV1.NewAgentClient(conn).GetStatus(context.Background(), &apiV1.GetStatusRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
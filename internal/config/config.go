@@ -1,11 +1,16 @@
 package config
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"slices"
+	"strings"
 	"sync"
 
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/weastur/maf/internal/config/validate"
 )
@@ -17,11 +22,41 @@ type Validator interface {
 type Config struct {
 	viperInstance *viper.Viper
 	validators    []Validator
+	// raw is the content of the config file in use, to roll back the failed reload
+	raw []byte
+	// envFiles maps the variables set from the files to their _FILE variants
+	envFiles map[string]string
+	// flags are bound to the settings, to tell the source of the value
+	flags map[string]*pflag.Flag
+	mu    sync.Mutex
 }
 
+const (
+	// EnvPrefix is prepended to the environment variables of the settings, e.g. MAF_SERVER_RAFT_NODE_ID
+	EnvPrefix     = "MAF"
+	envFileSuffix = "_FILE"
+)
+
 var (
+	ErrNoConfigFile = errors.New("no config file to reload")
+	ErrUnsafeChange = errors.New("setting can't be changed without restart")
+	ErrEnvConflict  = errors.New("both the variable and its " + envFileSuffix + " variant are set")
+
+	envKeyReplacer = strings.NewReplacer(".", "_")
+
 	instance *Config
 	once     sync.Once
+
+	// reloadableKeys are the settings the running server and agent apply on the reload.
+	// A key covers the nested ones too.
+	reloadableKeys = []string{
+		"log.level",
+		"http.auth",
+		"http.rate_limit",
+		"http.rate_limit_window",
+		"raft.peers",
+		"raft.discovery",
+	}
 )
 
 func Get() *Config {
@@ -33,6 +68,8 @@ func Get() *Config {
 				validate.NewTLS(),
 				validate.NewLogLevel(),
 				validate.NewRaft(),
+				validate.NewSocket(),
+				validate.NewRateLimit(),
 			},
 		}
 	})
@@ -55,19 +92,201 @@ func (c *Config) Init(cfgFileName string) error {
 		c.viperInstance.SetConfigName(".maf")
 	}
 
+	c.viperInstance.SetEnvPrefix(EnvPrefix)
+	c.viperInstance.SetEnvKeyReplacer(envKeyReplacer)
 	c.viperInstance.AutomaticEnv()
 
+	if err := c.read(); err != nil {
+		return err
+	}
+
+	if err := c.readEnvFiles(); err != nil {
+		return err
+	}
+
+	return c.validate()
+}
+
+func (c *Config) read() error {
 	if err := c.viperInstance.ReadInConfig(); err != nil {
 		var errCfgNotFound viper.ConfigFileNotFoundError
 		if errors.As(err, &errCfgNotFound) {
 			// No config file found, so using defaults
-			return c.validate()
+			return nil
 		}
 
 		return fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	return c.validate()
+	raw, err := os.ReadFile(c.viperInstance.ConfigFileUsed())
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	c.raw = raw
+
+	return nil
+}
+
+// EnvName returns the environment variable the setting is read from
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(envKeyReplacer.Replace(key))
+}
+
+// readEnvFiles sets the variables from the files their _FILE variants point to, e.g. MAF_SERVER_RAFT_JOIN_TOKEN
+// from MAF_SERVER_RAFT_JOIN_TOKEN_FILE, so the secrets can be mounted instead of being passed in the environment.
+// The variables of the settings ending with _file themselves, e.g. MAF_SERVER_HTTP_CERT_FILE, are left as they are.
+func (c *Config) readEnvFiles() error {
+	known := make(map[string]bool)
+
+	for _, key := range c.viperInstance.AllKeys() {
+		known[EnvName(key)] = true
+	}
+
+	for _, env := range os.Environ() {
+		name, file, _ := strings.Cut(env, "=")
+
+		target, ok := strings.CutSuffix(name, envFileSuffix)
+		if !ok || known[name] || !known[target] {
+			continue
+		}
+
+		if _, ok := os.LookupEnv(target); ok {
+			return fmt.Errorf("%w: %s", ErrEnvConflict, target)
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		if err := os.Setenv(target, strings.TrimSpace(string(data))); err != nil {
+			return fmt.Errorf("failed to set %s: %w", target, err)
+		}
+
+		if c.envFiles == nil {
+			c.envFiles = make(map[string]string)
+		}
+
+		c.envFiles[target] = name
+	}
+
+	return nil
+}
+
+// Reload reads the config file again and validates it. Only the reloadable settings of the section, e.g. server,
+// may change, the rest are read once on start. The current config is kept if the new one is invalid
+// or changes any other setting of the section, the settings of the other sections are not compared.
+// Then apply is called with the new config in place, and the current one is restored if it fails too.
+func (c *Config) Reload(section string, apply func() error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	file := c.viperInstance.ConfigFileUsed()
+	if file == "" {
+		return ErrNoConfigFile
+	}
+
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	before := c.sectionSettings(section)
+
+	if err := c.viperInstance.ReadConfig(bytes.NewReader(raw)); err != nil {
+		c.rollback()
+
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := c.validate(); err != nil {
+		c.rollback()
+
+		return err
+	}
+
+	if err := c.unsafeChanges(section, before); err != nil {
+		c.rollback()
+
+		return err
+	}
+
+	if err := apply(); err != nil {
+		c.rollback()
+
+		return err
+	}
+
+	c.raw = raw
+
+	return nil
+}
+
+func (c *Config) rollback() {
+	// The previous content has been read successfully already
+	_ = c.viperInstance.ReadConfig(bytes.NewReader(c.raw))
+}
+
+func isReloadable(section, key string) bool {
+	return slices.ContainsFunc(reloadableKeys, func(reloadable string) bool {
+		reloadable = section + "." + reloadable
+
+		return key == reloadable || strings.HasPrefix(key, reloadable+".")
+	})
+}
+
+// sectionSettings returns the values of the settings of the section which can't be reloaded
+func (c *Config) sectionSettings(section string) map[string]any {
+	settings := make(map[string]any)
+
+	for _, key := range c.viperInstance.AllKeys() {
+		if strings.HasPrefix(key, section+".") && !isReloadable(section, key) {
+			settings[key] = c.viperInstance.Get(key)
+		}
+	}
+
+	return settings
+}
+
+func (c *Config) unsafeChanges(section string, before map[string]any) error {
+	after := c.sectionSettings(section)
+	changed := make([]string, 0)
+
+	for key, value := range after {
+		if previous, ok := before[key]; !ok || !reflect.DeepEqual(previous, value) {
+			changed = append(changed, key)
+		}
+	}
+
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	slices.Sort(changed)
+
+	return fmt.Errorf("%w: %s", ErrUnsafeChange, strings.Join(changed, ", "))
+}
+
+// BindFlag binds the flag to the setting, the flag takes precedence over the other sources once it's set
+func (c *Config) BindFlag(key string, flag *pflag.Flag) error {
+	if err := c.viperInstance.BindPFlag(key, flag); err != nil {
+		return fmt.Errorf("failed to bind flag to %s: %w", key, err)
+	}
+
+	if c.flags == nil {
+		c.flags = make(map[string]*pflag.Flag)
+	}
+
+	c.flags[key] = flag
+
+	return nil
 }
 
 func (c *Config) Viper() *viper.Viper {
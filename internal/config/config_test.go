@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -140,3 +141,215 @@ func TestViper(t *testing.T) {
 	viperInstance := config.Viper()
 	assert.Equal(t, config.viperInstance, viperInstance, "Viper() should return the same viper instance as in the config")
 }
+
+func TestReload(t *testing.T) {
+	t.Parallel()
+
+	const initial = `
+server:
+  log:
+    level: info
+  http:
+    addr: ":7080"
+    rate_limit: 100
+  raft:
+    node_id: node1
+agent:
+  http:
+    addr: ":7070"
+`
+
+	newConfig := func(t *testing.T, validator Validator) (*Config, string) {
+		t.Helper()
+
+		file := filepath.Join(t.TempDir(), "maf.yaml")
+		require.NoError(t, os.WriteFile(file, []byte(initial), 0o600))
+
+		config := &Config{
+			viperInstance: viper.New(),
+			validators:    []Validator{validator},
+		}
+		require.NoError(t, config.Init(file))
+
+		return config, file
+	}
+
+	passing := new(MockValidator)
+	passing.On("Validate", mock.Anything).Return(nil)
+
+	noop := func() error { return nil }
+
+	t.Run("SafeChanges", func(t *testing.T) {
+		t.Parallel()
+
+		config, file := newConfig(t, passing)
+
+		// The other section may change too, it isn't used by the running process
+		updated := `
+server:
+  log:
+    level: debug
+  http:
+    addr: ":7080"
+    rate_limit: 10
+  raft:
+    node_id: node1
+agent:
+  http:
+    addr: ":7071"
+`
+		require.NoError(t, os.WriteFile(file, []byte(updated), 0o600))
+
+		require.NoError(t, config.Reload("server", noop))
+		assert.Equal(t, "debug", config.Viper().GetString("server.log.level"))
+		assert.Equal(t, 10, config.Viper().GetInt("server.http.rate_limit"))
+	})
+
+	t.Run("UnsafeChange", func(t *testing.T) {
+		t.Parallel()
+
+		config, file := newConfig(t, passing)
+
+		updated := `
+server:
+  log:
+    level: debug
+  http:
+    addr: ":7080"
+  raft:
+    node_id: node2
+`
+		require.NoError(t, os.WriteFile(file, []byte(updated), 0o600))
+
+		err := config.Reload("server", noop)
+		require.ErrorIs(t, err, ErrUnsafeChange)
+		assert.ErrorContains(t, err, "server.raft.node_id")
+		assert.Equal(t, "info", config.Viper().GetString("server.log.level"))
+		assert.Equal(t, "node1", config.Viper().GetString("server.raft.node_id"))
+		assert.Equal(t, 100, config.Viper().GetInt("server.http.rate_limit"))
+	})
+
+	t.Run("RejectedByApply", func(t *testing.T) {
+		t.Parallel()
+
+		config, file := newConfig(t, passing)
+
+		updated := `
+server:
+  log:
+    level: info
+  http:
+    addr: ":7080"
+    rate_limit: 100
+    auth:
+      disabled: true
+  raft:
+    node_id: node1
+`
+		require.NoError(t, os.WriteFile(file, []byte(updated), 0o600))
+
+		applyErr := errors.New("auth can't be enabled or disabled without restart")
+
+		err := config.Reload("server", func() error {
+			assert.True(t, config.Viper().GetBool("server.http.auth.disabled"))
+
+			return applyErr
+		})
+		require.ErrorIs(t, err, applyErr)
+		assert.False(t, config.Viper().GetBool("server.http.auth.disabled"))
+	})
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		t.Parallel()
+
+		validationError := errors.New("validation error")
+
+		validator := new(MockValidator)
+		validator.On("Validate", mock.Anything).Return(nil).Once()
+		validator.On("Validate", mock.Anything).Return(validationError).Once()
+
+		config, file := newConfig(t, validator)
+
+		require.NoError(t, os.WriteFile(file, []byte("server:\n  log:\n    level: debug\n"), 0o600))
+
+		require.ErrorIs(t, config.Reload("server", noop), validationError)
+		assert.Equal(t, "info", config.Viper().GetString("server.log.level"))
+	})
+
+	t.Run("MalformedFile", func(t *testing.T) {
+		t.Parallel()
+
+		config, file := newConfig(t, passing)
+
+		require.NoError(t, os.WriteFile(file, []byte("server: ["), 0o600))
+
+		require.ErrorContains(t, config.Reload("server", noop), "failed to read config file")
+		assert.Equal(t, "node1", config.Viper().GetString("server.raft.node_id"))
+	})
+
+	t.Run("NoConfigFile", func(t *testing.T) {
+		t.Parallel()
+
+		config := &Config{viperInstance: viper.New()}
+
+		require.ErrorIs(t, config.Reload("server", noop), ErrNoConfigFile)
+	})
+}
+
+func TestEnvName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "MAF_SERVER_RAFT_NODE_ID", EnvName("server.raft.node_id"))
+}
+
+func TestInitWithEnv(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "maf.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("server:\n  raft:\n    node_id: node1\n    join_token: ''\n"), 0o600))
+
+	secretFile := filepath.Join(t.TempDir(), "join_token")
+	require.NoError(t, os.WriteFile(secretFile, []byte("secret\n"), 0o600))
+
+	newConfig := func() *Config {
+		validator := new(MockValidator)
+		validator.On("Validate", mock.Anything).Return(nil)
+
+		return &Config{
+			viperInstance: viper.New(),
+			validators:    []Validator{validator},
+		}
+	}
+
+	t.Run("NestedKey", func(t *testing.T) {
+		t.Setenv("MAF_SERVER_RAFT_NODE_ID", "node2")
+
+		config := newConfig()
+		require.NoError(t, config.Init(file))
+		assert.Equal(t, "node2", config.Viper().GetString("server.raft.node_id"))
+	})
+
+	t.Run("FileVariant", func(t *testing.T) {
+		// The variable set from the file is removed once the test is done
+		t.Setenv("MAF_SERVER_RAFT_JOIN_TOKEN", "")
+		os.Unsetenv("MAF_SERVER_RAFT_JOIN_TOKEN")
+		t.Setenv("MAF_SERVER_RAFT_JOIN_TOKEN_FILE", secretFile)
+
+		config := newConfig()
+		require.NoError(t, config.Init(file))
+		assert.Equal(t, "secret", config.Viper().GetString("server.raft.join_token"))
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		t.Setenv("MAF_SERVER_RAFT_JOIN_TOKEN", "inline")
+		t.Setenv("MAF_SERVER_RAFT_JOIN_TOKEN_FILE", secretFile)
+
+		require.ErrorIs(t, newConfig().Init(file), ErrEnvConflict)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		t.Setenv("MAF_SERVER_RAFT_JOIN_TOKEN", "")
+		os.Unsetenv("MAF_SERVER_RAFT_JOIN_TOKEN")
+		t.Setenv("MAF_SERVER_RAFT_JOIN_TOKEN_FILE", filepath.Join(t.TempDir(), "missing"))
+
+		require.ErrorContains(t, newConfig().Init(file), "failed to read MAF_SERVER_RAFT_JOIN_TOKEN_FILE")
+	})
+}
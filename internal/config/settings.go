@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+
+	redacted = "[redacted]"
+)
+
+// Setting is the effective value of a setting with the layer it comes from
+type Setting struct {
+	Value  any    `json:"value"`
+	Source string `json:"source"`
+}
+
+// Settings returns the effective settings of the section, e.g. server, with the secrets redacted.
+// The source of the value from the environment names the variable, e.g. "env MAF_SERVER_RAFT_NODE_ID",
+// and the one from the flag names the flag, e.g. "flag --raft-node-id".
+func (c *Config) Settings(section string) map[string]Setting {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	settings := make(map[string]Setting)
+
+	for _, key := range c.viperInstance.AllKeys() {
+		if !strings.HasPrefix(key, section+".") {
+			continue
+		}
+
+		settings[key] = Setting{
+			Value:  redact(key[strings.LastIndex(key, ".")+1:], c.viperInstance.Get(key)),
+			Source: c.source(key),
+		}
+	}
+
+	return settings
+}
+
+// source follows the precedence of viper. The empty variables are ignored the same way
+func (c *Config) source(key string) string {
+	if flag, ok := c.flags[key]; ok && flag.Changed {
+		return SourceFlag + " --" + flag.Name
+	}
+
+	name := EnvName(key)
+
+	if value, ok := os.LookupEnv(name); ok && value != "" {
+		if fileName, ok := c.envFiles[name]; ok {
+			name = fileName
+		}
+
+		return SourceEnv + " " + name
+	}
+
+	if c.viperInstance.InConfig(key) {
+		return SourceFile
+	}
+
+	return SourceDefault
+}
+
+func isSecret(name string) bool {
+	return name == "token" || name == "token_sha256" || name == "password" || name == "dsn" ||
+		strings.HasSuffix(name, "_token")
+}
+
+// redact hides the values of the secret settings, including the ones nested in the lists, e.g. the API tokens.
+// The empty values are kept to show the secret isn't set.
+func redact(name string, value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(typed))
+
+		for key, item := range typed {
+			result[key] = redact(key, item)
+		}
+
+		return result
+	case []any:
+		result := make([]any, len(typed))
+
+		for i, item := range typed {
+			result[i] = redact(name, item)
+		}
+
+		return result
+	}
+
+	if isSecret(name) && value != nil && value != "" {
+		return redacted
+	}
+
+	return value
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettings(t *testing.T) {
+	const content = `
+server:
+  raft:
+    node_id: node1
+    join_token: join-secret
+  http:
+    auth:
+      tokens:
+        - name: ops
+          role: admin
+          token: api-secret
+        - name: ci
+          role: read
+          token_file: /run/secrets/ci
+  sentry:
+    dsn: ""
+agent:
+  http:
+    addr: ":7070"
+`
+
+	file := filepath.Join(t.TempDir(), "maf.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+
+	secretFile := filepath.Join(t.TempDir(), "consul_token")
+	require.NoError(t, os.WriteFile(secretFile, []byte("consul-secret"), 0o600))
+
+	// The variable set from the file is removed once the test is done
+	t.Setenv("MAF_SERVER_RAFT_DISCOVERY_CONSUL_TOKEN", "")
+	os.Unsetenv("MAF_SERVER_RAFT_DISCOVERY_CONSUL_TOKEN")
+	t.Setenv("MAF_SERVER_RAFT_DISCOVERY_CONSUL_TOKEN_FILE", secretFile)
+	t.Setenv("MAF_SERVER_RAFT_NODE_ID", "node2")
+
+	validator := new(MockValidator)
+	validator.On("Validate", mock.Anything).Return(nil)
+
+	viperInstance := viper.New()
+	viperInstance.SetDefault("server.http.addr", ":7080")
+	viperInstance.SetDefault("server.raft.discovery.consul.token", "")
+
+	config := &Config{viperInstance: viperInstance, validators: []Validator{validator}}
+	require.NoError(t, config.Init(file))
+
+	settings := config.Settings("server")
+
+	assert.Equal(t, Setting{Value: ":7080", Source: SourceDefault}, settings["server.http.addr"])
+	assert.Equal(t, Setting{Value: "node2", Source: "env MAF_SERVER_RAFT_NODE_ID"}, settings["server.raft.node_id"])
+	assert.Equal(t, Setting{Value: "[redacted]", Source: SourceFile}, settings["server.raft.join_token"])
+	assert.Equal(
+		t,
+		Setting{Value: "[redacted]", Source: "env MAF_SERVER_RAFT_DISCOVERY_CONSUL_TOKEN_FILE"},
+		settings["server.raft.discovery.consul.token"],
+	)
+	assert.Equal(t, Setting{Value: "", Source: SourceFile}, settings["server.sentry.dsn"])
+	assert.Equal(t, Setting{
+		Value: []any{
+			map[string]any{"name": "ops", "role": "admin", "token": "[redacted]"},
+			map[string]any{"name": "ci", "role": "read", "token_file": "/run/secrets/ci"},
+		},
+		Source: SourceFile,
+	}, settings["server.http.auth.tokens"])
+	assert.NotContains(t, settings, "agent.http.addr")
+}
+
+func TestSettingsFlag(t *testing.T) {
+	t.Setenv("MAF_SERVER_HTTP_ADDR", ":7081")
+
+	flags := pflag.NewFlagSet("server", pflag.ContinueOnError)
+	flags.String("http-addr", ":7080", "")
+	flags.String("raft-node-id", "", "")
+
+	config := &Config{viperInstance: viper.New()}
+	config.viperInstance.AutomaticEnv()
+	config.viperInstance.SetEnvPrefix(EnvPrefix)
+	config.viperInstance.SetEnvKeyReplacer(envKeyReplacer)
+	require.NoError(t, config.BindFlag("server.http.addr", flags.Lookup("http-addr")))
+	require.NoError(t, config.BindFlag("server.raft.node_id", flags.Lookup("raft-node-id")))
+
+	require.NoError(t, flags.Parse([]string{"--raft-node-id", "maf-2"}))
+
+	settings := config.Settings("server")
+
+	// The flag takes precedence over the environment only once it's set
+	assert.Equal(t, Setting{Value: ":7081", Source: "env MAF_SERVER_HTTP_ADDR"}, settings["server.http.addr"])
+	assert.Equal(t, Setting{Value: "maf-2", Source: "flag --raft-node-id"}, settings["server.raft.node_id"])
+}
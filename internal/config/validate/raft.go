@@ -2,6 +2,8 @@ package validate
 
 import (
 	"errors"
+	"net"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -12,22 +14,154 @@ var ErrRaftMissingMandatory = errors.New(
 	"raft peers and node ID must be set",
 )
 
+var ErrRaftDiscovery = errors.New(
+	"raft-discovery-provider must be static, dns or consul, dns requires raft-discovery-dns-name " +
+		"and consul requires raft-discovery-consul-service",
+)
+
 var ErrRaftStorage = errors.New(
 	"raft-data-dir must be set when raft-devmode is false",
 )
 
+var ErrRaftTLS = errors.New(
+	"raft-tls-cert-file, raft-tls-key-file and raft-tls-ca-file must be set together",
+)
+
+var ErrRaftAdvertise = errors.New(
+	"raft-advertise must be a host:port and must not be a wildcard, set it if raft-addr is a wildcard",
+)
+
+var ErrRaftNonvoterBootstrap = errors.New(
+	"raft-nonvoter and raft-bootstrap are mutually exclusive, the bootstrap server must be a voter",
+)
+
+var ErrRaftAutopilot = errors.New(
+	"raft-autopilot-dead-server-threshold and raft-autopilot-server-stabilization-time must be positive",
+)
+
+var ErrRaftTuning = errors.New(
+	"raft timeouts must be at least 5ms, raft-leader-lease-timeout must not exceed raft-heartbeat-timeout, " +
+		"raft-election-timeout must not be lower than raft-heartbeat-timeout " +
+		"and raft-snapshot-retain, raft-transport-max-pool must not be negative",
+)
+
+// Lowest timeout the raft library accepts.
+const minRaftTimeout = 5 * time.Millisecond
+
 func NewRaft() *Raft {
 	return &Raft{}
 }
 
 func (v *Raft) Validate(viperInstance *viper.Viper) error {
-	if !viperInstance.IsSet("server.raft.peers") || !viperInstance.IsSet("server.raft.node_id") {
+	if !viperInstance.IsSet("server.raft.node_id") {
 		return ErrRaftMissingMandatory
 	}
 
+	if err := validateRaftDiscovery(viperInstance); err != nil {
+		return err
+	}
+
 	if !viperInstance.GetBool("server.raft.devmode") && !viperInstance.IsSet("server.raft.datadir") {
 		return ErrRaftStorage
 	}
 
+	if viperInstance.GetBool("server.raft.nonvoter") && viperInstance.GetBool("server.raft.bootstrap") {
+		return ErrRaftNonvoterBootstrap
+	}
+
+	if viperInstance.GetBool("server.raft.autopilot.enabled") &&
+		(viperInstance.GetDuration("server.raft.autopilot.dead_server_threshold") <= 0 ||
+			viperInstance.GetDuration("server.raft.autopilot.server_stabilization_time") <= 0) {
+		return ErrRaftAutopilot
+	}
+
+	if err := validateRaftTuning(viperInstance); err != nil {
+		return err
+	}
+
+	if err := validateRaftAdvertise(viperInstance); err != nil {
+		return err
+	}
+
+	certSet := viperInstance.IsSet("server.raft.tls.cert_file")
+	if certSet != viperInstance.IsSet("server.raft.tls.key_file") ||
+		certSet != viperInstance.IsSet("server.raft.tls.ca_file") {
+		return ErrRaftTLS
+	}
+
+	return nil
+}
+
+func validateRaftAdvertise(viperInstance *viper.Viper) error {
+	advertise := viperInstance.GetString("server.raft.advertise")
+	if advertise == "" {
+		advertise = viperInstance.GetString("server.raft.addr")
+	}
+
+	if advertise == "" {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(advertise)
+	if err != nil {
+		return ErrRaftAdvertise
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		return ErrRaftAdvertise
+	}
+
+	return nil
+}
+
+func validateRaftDiscovery(viperInstance *viper.Viper) error {
+	switch viperInstance.GetString("server.raft.discovery.provider") {
+	case "static", "":
+		if !viperInstance.IsSet("server.raft.peers") {
+			return ErrRaftMissingMandatory
+		}
+	case "dns":
+		if viperInstance.GetString("server.raft.discovery.dns.name") == "" {
+			return ErrRaftDiscovery
+		}
+	case "consul":
+		if viperInstance.GetString("server.raft.discovery.consul.service") == "" {
+			return ErrRaftDiscovery
+		}
+	default:
+		return ErrRaftDiscovery
+	}
+
+	return nil
+}
+
+// validateRaftTuning checks the timeouts against the constraints of the raft library.
+// Zero values keep the library defaults, so they are not checked.
+func validateRaftTuning(viperInstance *viper.Viper) error {
+	heartbeat := viperInstance.GetDuration("server.raft.heartbeat_timeout")
+	election := viperInstance.GetDuration("server.raft.election_timeout")
+	leaderLease := viperInstance.GetDuration("server.raft.leader_lease_timeout")
+
+	for _, timeout := range []time.Duration{
+		heartbeat,
+		election,
+		leaderLease,
+		viperInstance.GetDuration("server.raft.snapshot_interval"),
+		viperInstance.GetDuration("server.raft.transport.timeout"),
+	} {
+		if timeout != 0 && timeout < minRaftTimeout {
+			return ErrRaftTuning
+		}
+	}
+
+	if heartbeat != 0 && ((leaderLease != 0 && leaderLease > heartbeat) || (election != 0 && election < heartbeat)) {
+		return ErrRaftTuning
+	}
+
+	if viperInstance.GetInt("server.raft.snapshot_retain") < 0 ||
+		viperInstance.GetInt("server.raft.transport.max_pool") < 0 {
+		return ErrRaftTuning
+	}
+
 	return nil
 }
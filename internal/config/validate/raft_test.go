@@ -52,6 +52,207 @@ func TestRaftValidate(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name: "dns discovery without peers",
+			config: map[string]any{
+				"server.raft.node_id":            "node1",
+				"server.raft.devmode":            true,
+				"server.raft.discovery.provider": "dns",
+				"server.raft.discovery.dns.name": "maf.service.consul",
+			},
+			expectedError: nil,
+		},
+		{
+			name: "dns discovery without name",
+			config: map[string]any{
+				"server.raft.node_id":            "node1",
+				"server.raft.devmode":            true,
+				"server.raft.discovery.provider": "dns",
+			},
+			expectedError: ErrRaftDiscovery,
+		},
+		{
+			name: "consul discovery",
+			config: map[string]any{
+				"server.raft.node_id":                  "node1",
+				"server.raft.devmode":                  true,
+				"server.raft.discovery.provider":       "consul",
+				"server.raft.discovery.consul.service": "maf-server",
+			},
+			expectedError: nil,
+		},
+		{
+			name: "unknown discovery provider",
+			config: map[string]any{
+				"server.raft.node_id":            "node1",
+				"server.raft.devmode":            true,
+				"server.raft.discovery.provider": "etcd",
+			},
+			expectedError: ErrRaftDiscovery,
+		},
+		{
+			name: "static discovery without peers",
+			config: map[string]any{
+				"server.raft.node_id":            "node1",
+				"server.raft.devmode":            true,
+				"server.raft.discovery.provider": "static",
+			},
+			expectedError: ErrRaftMissingMandatory,
+		},
+		{
+			name: "nonvoter bootstrap",
+			config: map[string]any{
+				"server.raft.peers":     "peer1,peer2",
+				"server.raft.node_id":   "node1",
+				"server.raft.devmode":   true,
+				"server.raft.nonvoter":  true,
+				"server.raft.bootstrap": true,
+			},
+			expectedError: ErrRaftNonvoterBootstrap,
+		},
+		{
+			name: "autopilot without thresholds",
+			config: map[string]any{
+				"server.raft.peers":             "peer1,peer2",
+				"server.raft.node_id":           "node1",
+				"server.raft.devmode":           true,
+				"server.raft.autopilot.enabled": true,
+			},
+			expectedError: ErrRaftAutopilot,
+		},
+		{
+			name: "autopilot with thresholds",
+			config: map[string]any{
+				"server.raft.peers":                               "peer1,peer2",
+				"server.raft.node_id":                             "node1",
+				"server.raft.devmode":                             true,
+				"server.raft.autopilot.enabled":                   true,
+				"server.raft.autopilot.dead_server_threshold":     "30m",
+				"server.raft.autopilot.server_stabilization_time": "10s",
+			},
+			expectedError: nil,
+		},
+		{
+			name: "raft tuning for WAN",
+			config: map[string]any{
+				"server.raft.peers":                "peer1,peer2",
+				"server.raft.node_id":              "node1",
+				"server.raft.devmode":              true,
+				"server.raft.heartbeat_timeout":    "5s",
+				"server.raft.election_timeout":     "10s",
+				"server.raft.leader_lease_timeout": "2500ms",
+				"server.raft.transport.timeout":    "30s",
+			},
+			expectedError: nil,
+		},
+		{
+			name: "raft timeout too low",
+			config: map[string]any{
+				"server.raft.peers":             "peer1,peer2",
+				"server.raft.node_id":           "node1",
+				"server.raft.devmode":           true,
+				"server.raft.heartbeat_timeout": "1ms",
+			},
+			expectedError: ErrRaftTuning,
+		},
+		{
+			name: "raft election timeout lower than heartbeat timeout",
+			config: map[string]any{
+				"server.raft.peers":             "peer1,peer2",
+				"server.raft.node_id":           "node1",
+				"server.raft.devmode":           true,
+				"server.raft.heartbeat_timeout": "5s",
+				"server.raft.election_timeout":  "1s",
+			},
+			expectedError: ErrRaftTuning,
+		},
+		{
+			name: "raft leader lease timeout exceeds heartbeat timeout",
+			config: map[string]any{
+				"server.raft.peers":                "peer1,peer2",
+				"server.raft.node_id":              "node1",
+				"server.raft.devmode":              true,
+				"server.raft.heartbeat_timeout":    "1s",
+				"server.raft.election_timeout":     "1s",
+				"server.raft.leader_lease_timeout": "2s",
+			},
+			expectedError: ErrRaftTuning,
+		},
+		{
+			name: "negative raft transport pool",
+			config: map[string]any{
+				"server.raft.peers":              "peer1,peer2",
+				"server.raft.node_id":            "node1",
+				"server.raft.devmode":            true,
+				"server.raft.transport.max_pool": -1,
+			},
+			expectedError: ErrRaftTuning,
+		},
+		{
+			name: "wildcard raft address without advertise",
+			config: map[string]any{
+				"server.raft.peers":   "peer1,peer2",
+				"server.raft.node_id": "node1",
+				"server.raft.devmode": true,
+				"server.raft.addr":    "0.0.0.0:7081",
+			},
+			expectedError: ErrRaftAdvertise,
+		},
+		{
+			name: "wildcard raft advertise",
+			config: map[string]any{
+				"server.raft.peers":     "peer1,peer2",
+				"server.raft.node_id":   "node1",
+				"server.raft.devmode":   true,
+				"server.raft.addr":      "0.0.0.0:7081",
+				"server.raft.advertise": "[::]:7081",
+			},
+			expectedError: ErrRaftAdvertise,
+		},
+		{
+			name: "malformed raft advertise",
+			config: map[string]any{
+				"server.raft.peers":     "peer1,peer2",
+				"server.raft.node_id":   "node1",
+				"server.raft.devmode":   true,
+				"server.raft.advertise": "10.1.2.3",
+			},
+			expectedError: ErrRaftAdvertise,
+		},
+		{
+			name: "wildcard raft address with advertise",
+			config: map[string]any{
+				"server.raft.peers":     "peer1,peer2",
+				"server.raft.node_id":   "node1",
+				"server.raft.devmode":   true,
+				"server.raft.addr":      ":7081",
+				"server.raft.advertise": "maf-1.example.com:7081",
+			},
+			expectedError: nil,
+		},
+		{
+			name: "partial raft TLS configuration",
+			config: map[string]any{
+				"server.raft.peers":         "peer1,peer2",
+				"server.raft.node_id":       "node1",
+				"server.raft.devmode":       true,
+				"server.raft.tls.cert_file": "/etc/maf/raft.pem",
+				"server.raft.tls.key_file":  "/etc/maf/raft.key",
+			},
+			expectedError: ErrRaftTLS,
+		},
+		{
+			name: "valid raft TLS configuration",
+			config: map[string]any{
+				"server.raft.peers":         "peer1,peer2",
+				"server.raft.node_id":       "node1",
+				"server.raft.devmode":       true,
+				"server.raft.tls.cert_file": "/etc/maf/raft.pem",
+				"server.raft.tls.key_file":  "/etc/maf/raft.key",
+				"server.raft.tls.ca_file":   "/etc/maf/ca.pem",
+			},
+			expectedError: nil,
+		},
 	}
 
 	for _, tt := range tests {
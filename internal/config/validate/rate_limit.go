@@ -0,0 +1,30 @@
+package validate
+
+import (
+	"errors"
+
+	"github.com/spf13/viper"
+)
+
+type RateLimit struct{}
+
+var ErrRateLimit = errors.New("rate limit and its window must be positive")
+
+func NewRateLimit() *RateLimit {
+	return &RateLimit{}
+}
+
+// Validate makes sure the rate limit is positive, the limiter silently falls back to its own default otherwise
+func (v *RateLimit) Validate(viperInstance *viper.Viper) error {
+	for _, key := range []string{"agent.http", "server.http"} {
+		if viperInstance.IsSet(key+".rate_limit") && viperInstance.GetInt(key+".rate_limit") <= 0 {
+			return ErrRateLimit
+		}
+
+		if viperInstance.IsSet(key+".rate_limit_window") && viperInstance.GetDuration(key+".rate_limit_window") <= 0 {
+			return ErrRateLimit
+		}
+	}
+
+	return nil
+}
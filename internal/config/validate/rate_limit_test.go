@@ -0,0 +1,67 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		config    map[string]any
+		expectErr bool
+	}{
+		{
+			name: "Valid rate limits",
+			config: map[string]any{
+				"agent.http.rate_limit":         100,
+				"server.http.rate_limit":        "50",
+				"server.http.rate_limit_window": "1m",
+			},
+			expectErr: false,
+		},
+		{
+			name:      "Missing rate limits",
+			config:    map[string]any{},
+			expectErr: false,
+		},
+		{
+			name: "Zero rate limit",
+			config: map[string]any{
+				"agent.http.rate_limit": 0,
+			},
+			expectErr: true,
+		},
+		{
+			name: "Negative window",
+			config: map[string]any{
+				"server.http.rate_limit_window": "-1s",
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := viper.New()
+			for key, value := range tt.config {
+				v.Set(key, value)
+			}
+
+			rateLimitValidator := NewRateLimit()
+			err := rateLimitValidator.Validate(v)
+
+			if tt.expectErr {
+				require.ErrorIs(t, err, ErrRateLimit)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package validate
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/spf13/viper"
+)
+
+type Socket struct{}
+
+var ErrSocketMode = errors.New(
+	"socket mode must be an octal file mode without access for others, e.g. 0600 or 0660",
+)
+
+func NewSocket() *Socket {
+	return &Socket{}
+}
+
+// Validate makes sure the socket isn't world-accessible, since anyone able to connect gets the admin access
+func (v *Socket) Validate(viperInstance *viper.Viper) error {
+	for _, key := range []string{"agent.http", "server.http"} {
+		if !viperInstance.IsSet(key + ".socket_mode") {
+			continue
+		}
+
+		mode, err := strconv.ParseUint(viperInstance.GetString(key+".socket_mode"), 8, 32)
+		if err != nil || mode > 0o777 || mode&0o007 != 0 {
+			return ErrSocketMode
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSocket_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		config    map[string]string
+		expectErr bool
+	}{
+		{
+			name: "Valid socket modes",
+			config: map[string]string{
+				"agent.http.socket_mode":  "0600",
+				"server.http.socket_mode": "660",
+			},
+			expectErr: false,
+		},
+		{
+			name:      "Missing socket modes",
+			config:    map[string]string{},
+			expectErr: false,
+		},
+		{
+			name: "Not an octal mode",
+			config: map[string]string{
+				"agent.http.socket_mode": "rw-------",
+			},
+			expectErr: true,
+		},
+		{
+			name: "World-accessible socket",
+			config: map[string]string{
+				"server.http.socket_mode": "0666",
+			},
+			expectErr: true,
+		},
+		{
+			name: "Mode with special bits",
+			config: map[string]string{
+				"server.http.socket_mode": "4600",
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := viper.New()
+			for key, value := range tt.config {
+				v.Set(key, value)
+			}
+
+			socketValidator := NewSocket()
+			err := socketValidator.Validate(v)
+
+			if tt.expectErr {
+				require.ErrorIs(t, err, ErrSocketMode)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
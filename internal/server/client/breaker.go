@@ -0,0 +1,162 @@
+package client
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"resty.dev/v3"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+const (
+	defaultBreakerFailureThreshold = 3
+	defaultBreakerSuccessThreshold = 1
+)
+
+// breakers are shared by all the clients of the same host, since the callers, e.g. raft worker,
+// make a new client for every call, and a breaker of their own would never trip
+var (
+	breakersMu sync.Mutex
+	breakers   = make(map[string]*circuitBreaker)
+)
+
+// circuitBreaker follows the resty one, which doesn't report its state changes: the requests are rejected
+// while open, they are let through again after the timeout, and the 5xx responses count as failures,
+// except 501, which isn't retried either.
+type circuitBreaker struct {
+	mu            sync.Mutex
+	state         breakerState
+	failures      int
+	successes     int
+	lastFailureAt time.Time
+	timeout       time.Duration
+	onStateChange func(state breakerState)
+}
+
+func newCircuitBreaker(timeout time.Duration, onStateChange func(state breakerState)) *circuitBreaker {
+	return &circuitBreaker{
+		state:         breakerClosed,
+		timeout:       timeout,
+		onStateChange: onStateChange,
+	}
+}
+
+// breakerFor returns the breaker of the host, the first client of the host creates it
+func breakerFor(host string, onStateChange func(state breakerState)) *circuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	cb, ok := breakers[host]
+	if !ok {
+		cb = newCircuitBreaker(defaultCircuitBreakerTimeout, onStateChange)
+		breakers[host] = cb
+	}
+
+	return cb
+}
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// attach rejects the requests while the breaker is open and records the responses
+func (cb *circuitBreaker) attach(rclient *resty.Client) {
+	rclient.AddRequestMiddleware(func(_ *resty.Client, _ *resty.Request) error {
+		return cb.allow()
+	})
+	rclient.AddResponseMiddleware(func(_ *resty.Client, res *resty.Response) error {
+		if res.RawResponse != nil {
+			cb.record(res.RawResponse.StatusCode)
+		}
+
+		return nil
+	})
+}
+
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == breakerOpen {
+		return resty.ErrCircuitBreakerOpen
+	}
+
+	return nil
+}
+
+func (cb *circuitBreaker) record(status int) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if status < http.StatusInternalServerError || status == http.StatusNotImplemented {
+		if cb.state == breakerHalfOpen {
+			cb.successes++
+			if cb.successes >= defaultBreakerSuccessThreshold {
+				cb.changeState(breakerClosed)
+			}
+		}
+
+		return
+	}
+
+	if cb.failures > 0 && time.Since(cb.lastFailureAt) > cb.timeout {
+		cb.failures = 0
+	}
+
+	switch cb.state {
+	case breakerClosed:
+		cb.failures++
+		if cb.failures >= defaultBreakerFailureThreshold {
+			cb.open()
+		} else {
+			cb.lastFailureAt = time.Now()
+		}
+	case breakerHalfOpen:
+		cb.open()
+	case breakerOpen:
+	}
+}
+
+func (cb *circuitBreaker) open() {
+	cb.changeState(breakerOpen)
+
+	time.AfterFunc(cb.timeout, func() {
+		cb.mu.Lock()
+		defer cb.mu.Unlock()
+
+		if cb.state == breakerOpen {
+			cb.changeState(breakerHalfOpen)
+		}
+	})
+}
+
+// changeState must be called with the lock held
+func (cb *circuitBreaker) changeState(state breakerState) {
+	cb.failures = 0
+	cb.successes = 0
+
+	if cb.state == state {
+		return
+	}
+
+	cb.state = state
+
+	if cb.onStateChange != nil {
+		cb.onStateChange(state)
+	}
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"resty.dev/v3"
+)
+
+type stateRecorder struct {
+	mu     sync.Mutex
+	states []breakerState
+}
+
+func (r *stateRecorder) record(state breakerState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.states = append(r.states, state)
+}
+
+func (r *stateRecorder) get() []breakerState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]breakerState(nil), r.states...)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OpensAfterFailures", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &stateRecorder{}
+		cb := newCircuitBreaker(time.Hour, recorder.record)
+
+		cb.record(http.StatusInternalServerError)
+		cb.record(http.StatusInternalServerError)
+		require.NoError(t, cb.allow())
+
+		cb.record(http.StatusBadGateway)
+		require.ErrorIs(t, cb.allow(), resty.ErrCircuitBreakerOpen)
+		assert.Equal(t, []breakerState{breakerOpen}, recorder.get())
+	})
+
+	t.Run("ClientErrorsAreNotFailures", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &stateRecorder{}
+		cb := newCircuitBreaker(time.Hour, recorder.record)
+
+		for range defaultBreakerFailureThreshold {
+			cb.record(http.StatusNotFound)
+			cb.record(http.StatusNotImplemented)
+		}
+
+		require.NoError(t, cb.allow())
+		assert.Empty(t, recorder.get())
+	})
+
+	t.Run("HalfOpenAfterTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &stateRecorder{}
+		cb := newCircuitBreaker(10*time.Millisecond, recorder.record)
+
+		for range defaultBreakerFailureThreshold {
+			cb.record(http.StatusInternalServerError)
+		}
+
+		require.Eventually(t, func() bool { return cb.allow() == nil }, time.Second, 5*time.Millisecond)
+
+		cb.record(http.StatusOK)
+		assert.Equal(t, []breakerState{breakerOpen, breakerHalfOpen, breakerClosed}, recorder.get())
+	})
+
+	t.Run("HalfOpenFailureReopens", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &stateRecorder{}
+		cb := newCircuitBreaker(10*time.Millisecond, recorder.record)
+
+		for range defaultBreakerFailureThreshold {
+			cb.record(http.StatusInternalServerError)
+		}
+
+		require.Eventually(t, func() bool { return cb.allow() == nil }, time.Second, 5*time.Millisecond)
+
+		cb.record(http.StatusServiceUnavailable)
+		require.ErrorIs(t, cb.allow(), resty.ErrCircuitBreakerOpen)
+		assert.Equal(t, []breakerState{breakerOpen, breakerHalfOpen, breakerOpen}, recorder.get()[:3])
+	})
+}
+
+func TestCircuitBreakerAttached(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, false)
+	client.rclient.SetRetryCount(0)
+
+	for range defaultBreakerFailureThreshold {
+		require.Error(t, client.RaftLeave())
+	}
+
+	require.ErrorIs(t, client.RaftLeave(), resty.ErrCircuitBreakerOpen)
+	assert.Equal(t, defaultBreakerFailureThreshold, calls)
+}
+
+func TestBreakerState_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "closed", breakerClosed.String())
+	assert.Equal(t, "open", breakerOpen.String())
+	assert.Equal(t, "half-open", breakerHalfOpen.String())
+}
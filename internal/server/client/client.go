@@ -1,11 +1,15 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/url"
-	"path"
+	"net"
+	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -18,51 +22,48 @@ import (
 
 const (
 	authHeader                   = "X-Auth-Token"
-	apiKey                       = "root"
 	defaultTimeout               = 10 * time.Second
 	defaultRetryCount            = 3
 	defaultRetryWaitTime         = 1 * time.Second
 	defaultRetryMaxWaitTime      = 3 * time.Second
 	defaultCircuitBreakerTimeout = 10 * time.Second
-	raftJoinPath                 = "/raft/join"
-	raftKVPath                   = "/raft/kv"
-	raftForgetPath               = "/raft/forget"
-	raftInfoPath                 = "/raft/info"
+	apiPrefix                    = "/api"
+	apiVersionsPath              = "/versions"
+	socketHost                   = "http://localhost"
 )
 
 type Client struct {
-	Host      string
-	urlPrefix string
-	AuthToken string
-	rclient   *resty.Client
-	logger    zerolog.Logger
+	Host       string
+	APIVersion string
+	urlPrefix  string
+	AuthToken  string
+	rclient    *resty.Client
+	logger     zerolog.Logger
 }
 
 func New(host string, loggingEnabled bool) *Client {
 	client := &Client{
-		Host:      host,
-		AuthToken: apiKey,
-		rclient:   resty.New(),
-		urlPrefix: host + "/api/v1alpha",
-		logger:    log.With().Str(logging.ComponentCtxKey, "server-client").Logger(),
+		Host:    host,
+		rclient: resty.New(),
+		logger:  log.With().Str(logging.ComponentCtxKey, "server-client").Logger(),
 	}
+	client.SetAPIVersion(APIVersionV1Alpha)
 	if !loggingEnabled {
 		client.logger = client.logger.Level(zerolog.Disabled)
 	}
 
-	cb := resty.NewCircuitBreaker().SetTimeout(defaultCircuitBreakerTimeout)
-
 	client.rclient.SetHeaderAuthorizationKey(authHeader)
 	client.rclient.SetAuthScheme("")
-	client.rclient.SetAuthToken(client.AuthToken)
 	client.rclient.SetHeader("User-Agent", "maf/"+utils.AppVersion())
 	client.rclient.SetTimeout(defaultTimeout)
 	client.rclient.SetRetryCount(defaultRetryCount)
 	client.rclient.SetRetryWaitTime(defaultRetryWaitTime)
 	client.rclient.SetRetryMaxWaitTime(defaultRetryMaxWaitTime)
-	client.rclient.SetCircuitBreaker(cb)
+	client.rclient.SetError(&response{})
 	client.rclient.SetLogger(restyzerolog.New(client.logger))
 	client.rclient.AddContentDecompresser("br", decompressBrotli)
+	client.attachMetrics()
+	breakerFor(host, client.onBreakerStateChange).attach(client.rclient)
 
 	if e := client.logger.Debug(); e.Enabled() {
 		e.Msg("Request debug enabled")
@@ -94,6 +95,19 @@ func NewWithMutualTLS(host, certFile, keyFile, serverCertFile string, loggingEna
 	return client
 }
 
+// NewWithSocket creates the client talking to the local server through the Unix socket, no auth is required there
+func NewWithSocket(path string, loggingEnabled bool) *Client {
+	client := New(socketHost, loggingEnabled)
+
+	if transport, ok := client.rclient.Transport().(*http.Transport); ok {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		}
+	}
+
+	return client
+}
+
 func NewWithAutoTLS(host string, config *TLSConfig, loggingEnabled bool) *Client {
 	if config == nil || (config.CertFile == "" && config.KeyFile == "" && config.ServerCertFile == "") {
 		return New(host, loggingEnabled)
@@ -104,12 +118,86 @@ func NewWithAutoTLS(host string, config *TLSConfig, loggingEnabled bool) *Client
 	return NewWithMutualTLS(host, config.CertFile, config.KeyFile, config.ServerCertFile, loggingEnabled)
 }
 
+func (c *Client) onBreakerStateChange(state breakerState) {
+	c.logger.Info().Msgf("Circuit breaker is %s", state)
+	c.setBreakerStateMetric(state)
+}
+
+// SetAuthToken sets the API token sent with every request
+func (c *Client) SetAuthToken(token string) *Client {
+	c.AuthToken = token
+	c.rclient.SetAuthToken(token)
+
+	return c
+}
+
+// SetAPIVersion pins the API version, e.g. to skip the negotiation
+func (c *Client) SetAPIVersion(version string) *Client {
+	c.APIVersion = version
+	c.urlPrefix = strings.TrimSuffix(c.Host, "/") + apiPrefix + "/" + version
+
+	return c
+}
+
+// Negotiate switches the client to the newest API version both the client and the server support.
+// Servers which don't list their versions support only v1alpha
+func (c *Client) Negotiate() (string, error) {
+	res, err := c.rclient.R().
+		SetResult(&apiVersionsResponse{}).
+		Get(strings.TrimSuffix(c.Host, "/") + apiPrefix + apiVersionsPath)
+	if err != nil {
+		c.logger.Error().Err(err).Msg("Failed to perform API versions request")
+
+		return "", fmt.Errorf("failed to perform API versions request: %w", err)
+	}
+
+	if res.StatusCode() == http.StatusNotFound {
+		c.SetAPIVersion(APIVersionV1Alpha)
+
+		return c.APIVersion, nil
+	}
+
+	if res.IsError() {
+		return "", &StatusCodeError{Code: res.StatusCode()}
+	}
+
+	data, ok := res.Result().(*apiVersionsResponse)
+	if !ok {
+		return "", ErrUnknownResponseFormat
+	}
+
+	for _, version := range supportedAPIVersions {
+		if slices.Contains(data.Versions, version) {
+			c.SetAPIVersion(version)
+			c.logger.Debug().Msgf("Negotiated API version %s", version)
+
+			return version, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: server supports %s", ErrNoCommonAPIVersion, strings.Join(data.Versions, ", "))
+}
+
+// request prepares the request to the endpoint of the negotiated API version
+func (c *Client) request(endpoint string) *resty.Request {
+	r := routes[c.APIVersion][endpoint]
+
+	return c.rclient.R().
+		SetMethod(r.method).
+		SetURL(c.urlPrefix + r.path).
+		SetResult(&response{})
+}
+
 func (c *Client) Close() error {
 	return c.rclient.Close()
 }
 
 func (c *Client) parseResponse(res *resty.Response) (any, error) {
 	if res.IsError() {
+		if data, ok := res.Error().(*response); ok && data.Code != "" {
+			return nil, newAPIError(res.StatusCode(), data)
+		}
+
 		err := &StatusCodeError{Code: res.StatusCode()}
 
 		return nil, err
@@ -121,6 +209,10 @@ func (c *Client) parseResponse(res *resty.Response) (any, error) {
 	}
 
 	if !data.IsSuccess() {
+		if data.Code != "" {
+			return nil, newAPIError(res.StatusCode(), data)
+		}
+
 		err := &ServerError{Details: data.Error}
 
 		return nil, err
@@ -145,33 +237,105 @@ func (c *Client) parseRaftKVGetResponse(data any) (*raftKVGetResponse, error) {
 	return &raftResp, nil
 }
 
-func (c *Client) makeURL(elem ...string) string {
-	baseURL, err := url.Parse(c.urlPrefix)
+func (c *Client) RaftJoin(serverID, addr string, nonvoter bool, token string) error {
+	res, err := c.request(endpointJoin).
+		SetBody(&raftJoinRequest{
+			ServerID: serverID,
+			Addr:     addr,
+			Nonvoter: nonvoter,
+			Token:    token,
+		}).
+		Send()
 	if err != nil {
-		panic("Failed to parse base URL. Can't continue")
+		c.logger.Error().Err(err).Msg("Failed to perform join request")
+
+		return fmt.Errorf("failed to perform join request: %w", err)
 	}
 
-	baseURL.Path = path.Join(baseURL.Path, path.Join(elem...))
+	if _, err := c.parseResponse(res); err != nil {
+		c.logger.Error().Err(err).Msg("Failed to perform join request")
 
-	return baseURL.String()
+		return err
+	}
+
+	return nil
 }
 
-func (c *Client) RaftJoin(serverID, addr string) error {
-	res, err := c.rclient.R().
-		SetBody(&raftJoinRequest{
+func (c *Client) RaftPromote(serverID string) error {
+	res, err := c.request(endpointPromote).
+		SetPathParam("serverId", serverID).
+		SetBody(&raftPromoteRequest{
 			ServerID: serverID,
-			Addr:     addr,
 		}).
-		SetResult(&response{}).
-		Post(c.makeURL(raftJoinPath))
+		Send()
 	if err != nil {
-		c.logger.Error().Err(err).Msg("Failed to perform join request")
+		c.logger.Error().Err(err).Msg("Failed to perform promote request")
 
-		return fmt.Errorf("failed to perform join request: %w", err)
+		return fmt.Errorf("failed to perform promote request: %w", err)
 	}
 
 	if _, err := c.parseResponse(res); err != nil {
-		c.logger.Error().Err(err).Msg("Failed to perform join request")
+		c.logger.Error().Err(err).Msg("Failed to perform promote request")
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) RaftDemote(serverID string) error {
+	res, err := c.request(endpointDemote).
+		SetPathParam("serverId", serverID).
+		SetBody(&raftDemoteRequest{
+			ServerID: serverID,
+		}).
+		Send()
+	if err != nil {
+		c.logger.Error().Err(err).Msg("Failed to perform demote request")
+
+		return fmt.Errorf("failed to perform demote request: %w", err)
+	}
+
+	if _, err := c.parseResponse(res); err != nil {
+		c.logger.Error().Err(err).Msg("Failed to perform demote request")
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) RaftLeave() error {
+	res, err := c.request(endpointLeave).Send()
+	if err != nil {
+		c.logger.Error().Err(err).Msg("Failed to perform leave request")
+
+		return fmt.Errorf("failed to perform leave request: %w", err)
+	}
+
+	if _, err := c.parseResponse(res); err != nil {
+		c.logger.Error().Err(err).Msg("Failed to perform leave request")
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) RaftTransferLeadership(serverID string) error {
+	res, err := c.request(endpointLeadershipTransfer).
+		SetBody(&raftLeadershipTransferRequest{
+			ServerID: serverID,
+		}).
+		Send()
+	if err != nil {
+		c.logger.Error().Err(err).Msg("Failed to perform leadership transfer request")
+
+		return fmt.Errorf("failed to perform leadership transfer request: %w", err)
+	}
+
+	if _, err := c.parseResponse(res); err != nil {
+		c.logger.Error().Err(err).Msg("Failed to perform leadership transfer request")
 
 		return err
 	}
@@ -180,12 +344,12 @@ func (c *Client) RaftJoin(serverID, addr string) error {
 }
 
 func (c *Client) RaftForget(serverID string) error {
-	res, err := c.rclient.R().
+	res, err := c.request(endpointForget).
+		SetPathParam("serverId", serverID).
 		SetBody(&raftForgetRequest{
 			ServerID: serverID,
 		}).
-		SetResult(&response{}).
-		Post(c.makeURL(raftForgetPath))
+		Send()
 	if err != nil {
 		c.logger.Error().Err(err).Msg("Failed to perform forget request")
 
@@ -202,12 +366,9 @@ func (c *Client) RaftForget(serverID string) error {
 }
 
 func (c *Client) RaftKVGet(key string) (string, bool, error) {
-	res, err := c.rclient.R().
-		SetBody(&raftKVGetRequest{
-			Key: key,
-		}).
-		SetResult(&response{}).
-		Get(c.makeURL(raftKVPath, key))
+	res, err := c.request(endpointKVGet).
+		SetPathParam("key", key).
+		Send()
 	if err != nil {
 		c.logger.Error().Err(err).Msg("Failed to perform KV get request")
 
@@ -216,24 +377,32 @@ func (c *Client) RaftKVGet(key string) (string, bool, error) {
 
 	wrappedRes, err := c.parseResponse(res)
 	if err != nil {
+		// v1 responds with not_found to the missing keys instead of the 'exist' flag
+		if c.APIVersion != APIVersionV1Alpha && errors.Is(err, ErrNotFound) {
+			return "", false, nil
+		}
+
 		c.logger.Error().Err(err).Msg("Failed to perform KV get request")
 
 		return "", false, err
 	}
 
 	kvData, _ := c.parseRaftKVGetResponse(wrappedRes)
+	if c.APIVersion != APIVersionV1Alpha {
+		kvData.Exist = true
+	}
 
 	return kvData.Value, kvData.Exist, nil
 }
 
 func (c *Client) RaftKVSet(key, value string) error {
-	res, err := c.rclient.R().
+	res, err := c.request(endpointKVSet).
+		SetPathParam("key", key).
 		SetBody(&raftKVSetRequest{
 			Key:   key,
 			Value: value,
 		}).
-		SetResult(&response{}).
-		Post(c.makeURL(raftKVPath))
+		Send()
 	if err != nil {
 		c.logger.Error().Err(err).Msg("Failed to perform KV set request")
 
@@ -250,9 +419,9 @@ func (c *Client) RaftKVSet(key, value string) error {
 }
 
 func (c *Client) RaftKVDelete(key string) error {
-	res, err := c.rclient.R().
-		SetResult(&response{}).
-		Delete(c.makeURL(raftKVPath, key))
+	res, err := c.request(endpointKVDelete).
+		SetPathParam("key", key).
+		Send()
 	if err != nil {
 		c.logger.Error().Err(err).Msg("Failed to perform KV delete request")
 
@@ -268,11 +437,32 @@ func (c *Client) RaftKVDelete(key string) error {
 	return nil
 }
 
+func (c *Client) RaftTokenCreate(ttl time.Duration) (any, error) {
+	res, err := c.request(endpointTokenCreate).
+		SetBody(&raftTokenCreateRequest{
+			TTL: int64(ttl.Seconds()),
+		}).
+		Send()
+	if err != nil {
+		c.logger.Error().Err(err).Msg("Failed to perform token create request")
+
+		return nil, fmt.Errorf("failed to perform token create request: %w", err)
+	}
+
+	data, err := c.parseResponse(res)
+	if err != nil {
+		c.logger.Error().Err(err).Msg("Failed to perform token create request")
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
 func (c *Client) RaftInfo(includeStats bool) (any, error) {
-	res, err := c.rclient.R().
+	res, err := c.request(endpointInfo).
 		SetQueryParam("include_stats", strconv.FormatBool(includeStats)).
-		SetResult(&response{}).
-		Get(c.makeURL(raftInfoPath))
+		Send()
 	if err != nil {
 		c.logger.Error().Err(err).Msg("Failed to perform raft info request")
 
@@ -288,3 +478,53 @@ func (c *Client) RaftInfo(includeStats bool) (any, error) {
 
 	return data, nil
 }
+
+// AuditList returns the page of the audit records, zero after and limit mean the first page of the default size
+func (c *Client) AuditList(since time.Time, after uint64, limit int) (any, error) {
+	req := c.request(endpointAuditList)
+	if !since.IsZero() {
+		req.SetQueryParam("since", since.UTC().Format(time.RFC3339))
+	}
+
+	if after > 0 {
+		req.SetQueryParam("after", strconv.FormatUint(after, 10))
+	}
+
+	if limit > 0 {
+		req.SetQueryParam("limit", strconv.Itoa(limit))
+	}
+
+	res, err := req.Send()
+	if err != nil {
+		c.logger.Error().Err(err).Msg("Failed to perform audit list request")
+
+		return nil, fmt.Errorf("failed to perform audit list request: %w", err)
+	}
+
+	data, err := c.parseResponse(res)
+	if err != nil {
+		c.logger.Error().Err(err).Msg("Failed to perform audit list request")
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// AuditAppend sends the audit entry to the leader, it's used by the followers to record the calls they served
+func (c *Client) AuditAppend(entry any) error {
+	res, err := c.request(endpointAuditAppend).SetBody(entry).Send()
+	if err != nil {
+		c.logger.Error().Err(err).Msg("Failed to perform audit append request")
+
+		return fmt.Errorf("failed to perform audit append request: %w", err)
+	}
+
+	if _, err := c.parseResponse(res); err != nil {
+		c.logger.Debug().Err(err).Msg("Failed to perform audit append request")
+
+		return err
+	}
+
+	return nil
+}
@@ -3,10 +3,13 @@ package client
 import (
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -34,16 +37,24 @@ func TestNew(t *testing.T) {
 	client := New("http://localhost", false)
 
 	assert.Equal(t, "http://localhost", client.Host)
-	assert.Equal(t, "root", client.AuthToken)
+	assert.Empty(t, client.AuthToken)
 	assert.NotNil(t, client.rclient)
 	assert.Equal(t, "X-Auth-Token", client.rclient.HeaderAuthorizationKey())
 	assert.Empty(t, client.rclient.AuthScheme())
-	assert.Equal(t, "root", client.rclient.AuthToken())
 	assert.Contains(t, client.rclient.Header().Get("User-Agent"), "maf/")
 	assert.Contains(t, client.rclient.ContentDecompresserKeys(), "br")
 	assert.Contains(t, client.urlPrefix, "/api/v1alpha")
 }
 
+func TestSetAuthToken(t *testing.T) {
+	t.Parallel()
+
+	client := New("http://localhost", false).SetAuthToken("secret")
+
+	assert.Equal(t, "secret", client.AuthToken)
+	assert.Equal(t, "secret", client.rclient.AuthToken())
+}
+
 func TestNewWithTLS(t *testing.T) {
 	t.Parallel()
 
@@ -74,6 +85,33 @@ func TestNewWithMutualTLS(t *testing.T) {
 	assert.NotEmpty(t, certs)
 }
 
+func TestNewWithSocket(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "maf.sock")
+
+	ln, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("X-Auth-Token"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"versions":["v1"]}`))
+	}))
+	server.Listener = ln
+	server.Start()
+
+	defer server.Close()
+
+	client := NewWithSocket(socket, false)
+
+	version, err := client.Negotiate()
+	require.NoError(t, err)
+	assert.Equal(t, APIVersionV1, version)
+	assert.Equal(t, "http://localhost/api/v1", client.urlPrefix)
+}
+
 func TestNewWithAutoTLS(t *testing.T) {
 	t.Parallel()
 
@@ -83,7 +121,7 @@ func TestNewWithAutoTLS(t *testing.T) {
 		client := NewWithAutoTLS("http://localhost", nil, true)
 
 		assert.Equal(t, "http://localhost", client.Host)
-		assert.Equal(t, "root", client.AuthToken)
+		assert.Empty(t, client.AuthToken)
 		assert.NotNil(t, client.rclient)
 	})
 
@@ -93,7 +131,7 @@ func TestNewWithAutoTLS(t *testing.T) {
 		client := NewWithAutoTLS("http://localhost", &TLSConfig{}, true)
 
 		assert.Equal(t, "http://localhost", client.Host)
-		assert.Equal(t, "root", client.AuthToken)
+		assert.Empty(t, client.AuthToken)
 		assert.NotNil(t, client.rclient)
 	})
 
@@ -166,6 +204,57 @@ func TestParseRaftKVGetResponse(t *testing.T) {
 		assert.Equal(t, expected, result)
 	})
 
+	t.Run("NotLeader", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusMisdirectedRequest)
+			_ = json.NewEncoder(w).Encode(response{
+				Status:  "error",
+				Error:   "not a leader",
+				Code:    CodeNotLeader,
+				Details: map[string]string{"leader": "http://10.0.0.1:7080"},
+			})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		err := client.RaftJoin("server-1", "127.0.0.1:8080", false, "secret")
+		require.ErrorIs(t, err, ErrNotLeader)
+
+		var notLeaderErr *NotLeaderError
+
+		require.ErrorAs(t, err, &notLeaderErr)
+		assert.Equal(t, "http://10.0.0.1:7080", notLeaderErr.Leader)
+		assert.Equal(t, http.StatusMisdirectedRequest, notLeaderErr.Status)
+	})
+
+	t.Run("ValidationFailed", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(response{
+				Status:  "error",
+				Error:   "wildcard address",
+				Code:    CodeValidationFailed,
+				Details: map[string]string{"field": "addr"},
+			})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		err := client.RaftJoin("server-1", "0.0.0.0:8080", false, "secret")
+		require.ErrorIs(t, err, ErrValidationFailed)
+
+		var validationErr *ValidationError
+
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "addr", validationErr.Field)
+	})
+
 	t.Run("InvalidResponseFormat", func(t *testing.T) {
 		t.Parallel()
 
@@ -210,61 +299,115 @@ func TestParseRaftKVGetResponse(t *testing.T) {
 	})
 }
 
-func TestMakeURL(t *testing.T) {
+func TestSetAPIVersion(t *testing.T) {
 	t.Parallel()
 
-	client := &Client{
-		urlPrefix: "http://localhost/api/v1alpha",
-		logger:    zerolog.Nop(),
-	}
+	client := New("http://localhost/", false)
+	assert.Equal(t, APIVersionV1Alpha, client.APIVersion)
+	assert.Equal(t, "http://localhost/api/v1alpha", client.urlPrefix)
+
+	client.SetAPIVersion(APIVersionV1)
+	assert.Equal(t, APIVersionV1, client.APIVersion)
+	assert.Equal(t, "http://localhost/api/v1", client.urlPrefix)
+}
 
-	t.Run("SingleElement", func(t *testing.T) {
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NewestCommonVersion", func(t *testing.T) {
 		t.Parallel()
 
-		result := client.makeURL("raft/join")
-		expected := "http://localhost/api/v1alpha/raft/join"
-		assert.Equal(t, expected, result)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/versions", r.URL.Path)
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"versions":["v2","v1","v1alpha"]}`))
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		version, err := client.Negotiate()
+		require.NoError(t, err)
+		assert.Equal(t, APIVersionV1, version)
+		assert.Equal(t, server.URL+"/api/v1", client.urlPrefix)
 	})
 
-	t.Run("MultipleElements", func(t *testing.T) {
+	t.Run("LegacyServer", func(t *testing.T) {
 		t.Parallel()
 
-		result := client.makeURL("raft", "kv", "key1")
-		expected := "http://localhost/api/v1alpha/raft/kv/key1"
-		assert.Equal(t, expected, result)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false).SetAPIVersion(APIVersionV1)
+		version, err := client.Negotiate()
+		require.NoError(t, err)
+		assert.Equal(t, APIVersionV1Alpha, version)
 	})
 
-	t.Run("EmptyElements", func(t *testing.T) {
+	t.Run("NoCommonVersion", func(t *testing.T) {
 		t.Parallel()
 
-		result := client.makeURL()
-		expected := "http://localhost/api/v1alpha"
-		assert.Equal(t, expected, result)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"versions":["v2"]}`))
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		_, err := client.Negotiate()
+		require.ErrorIs(t, err, ErrNoCommonAPIVersion)
+		assert.Equal(t, APIVersionV1Alpha, client.APIVersion)
 	})
 
-	t.Run("TrailingSlash", func(t *testing.T) {
+	t.Run("RequestFailure", func(t *testing.T) {
 		t.Parallel()
 
-		clientWithSlash := &Client{
-			urlPrefix: "http://localhost/api/v1alpha/",
-			logger:    zerolog.Nop(),
-		}
-		result := clientWithSlash.makeURL("raft", "info")
-		expected := "http://localhost/api/v1alpha/raft/info"
-		assert.Equal(t, expected, result)
+		client := New("http://invalid-url", false)
+		_, err := client.Negotiate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to perform API versions request")
 	})
+}
+
+func TestRaftKVGetV1(t *testing.T) {
+	t.Parallel()
 
-	t.Run("InvalidURLPrefix", func(t *testing.T) {
+	t.Run("SuccessfulGet", func(t *testing.T) {
 		t.Parallel()
 
-		clientInvalid := &Client{
-			urlPrefix: "http://[::1]:invalid",
-			logger:    zerolog.Nop(),
-		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1/kv/test-key", r.URL.Path)
+			assert.Equal(t, http.MethodGet, r.Method)
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":{"key":"test-key","value":"test-value"}}`))
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false).SetAPIVersion(APIVersionV1)
+		value, exist, err := client.RaftKVGet("test-key")
+		require.NoError(t, err)
+		assert.True(t, exist)
+		assert.Equal(t, "test-value", value)
+	})
 
-		assert.Panics(t, func() {
-			clientInvalid.makeURL("raft", "info")
-		})
+	t.Run("KeyDoesNotExist", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":"not_found","message":"key is not found: test-key"}}`))
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false).SetAPIVersion(APIVersionV1)
+		value, exist, err := client.RaftKVGet("test-key")
+		require.NoError(t, err)
+		assert.False(t, exist)
+		assert.Empty(t, value)
 	})
 }
 
@@ -283,6 +426,8 @@ func TestRaftJoin(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Equal(t, "server-1", req.ServerID)
 			assert.Equal(t, "127.0.0.1:8080", req.Addr)
+			assert.True(t, req.Nonvoter)
+			assert.Equal(t, "secret", req.Token)
 
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
@@ -291,7 +436,7 @@ func TestRaftJoin(t *testing.T) {
 		defer server.Close()
 
 		client := New(server.URL, false)
-		err := client.RaftJoin("server-1", "127.0.0.1:8080")
+		err := client.RaftJoin("server-1", "127.0.0.1:8080", true, "secret")
 		require.NoError(t, err)
 	})
 
@@ -306,7 +451,7 @@ func TestRaftJoin(t *testing.T) {
 		defer server.Close()
 
 		client := New(server.URL, false)
-		err := client.RaftJoin("server-1", "127.0.0.1:8080")
+		err := client.RaftJoin("server-1", "127.0.0.1:8080", false, "secret")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "internal error")
 	})
@@ -320,7 +465,7 @@ func TestRaftJoin(t *testing.T) {
 		defer server.Close()
 
 		client := New(server.URL, false)
-		err := client.RaftJoin("server-1", "127.0.0.1:8080")
+		err := client.RaftJoin("server-1", "127.0.0.1:8080", false, "secret")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "bad status code")
 	})
@@ -336,7 +481,7 @@ func TestRaftJoin(t *testing.T) {
 		defer server.Close()
 
 		client := New(server.URL, false)
-		err := client.RaftJoin("server-1", "127.0.0.1:8080")
+		err := client.RaftJoin("server-1", "127.0.0.1:8080", false, "secret")
 		require.Error(t, err)
 	})
 
@@ -351,7 +496,7 @@ func TestRaftJoin(t *testing.T) {
 		defer server.Close()
 
 		client := New(server.URL, false)
-		err := client.RaftJoin("server-1", "127.0.0.1:8080")
+		err := client.RaftJoin("server-1", "127.0.0.1:8080", false, "secret")
 		require.Error(t, err)
 	})
 
@@ -359,7 +504,7 @@ func TestRaftJoin(t *testing.T) {
 		t.Parallel()
 
 		client := New("http://invalid-url", false)
-		err := client.RaftJoin("server-1", "127.0.0.1:8080")
+		err := client.RaftJoin("server-1", "127.0.0.1:8080", false, "secret")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to perform join request")
 	})
@@ -461,6 +606,209 @@ func TestRaftForget(t *testing.T) {
 	})
 }
 
+func TestRaftPromote(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SuccessfulPromote", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1alpha/raft/promote", r.URL.Path)
+			assert.Equal(t, http.MethodPost, r.Method)
+
+			var req raftPromoteRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			assert.NoError(t, err)
+			assert.Equal(t, "server-1", req.ServerID)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(response{Status: "success"})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		err := client.RaftPromote("server-1")
+		require.NoError(t, err)
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(response{Status: "error", Error: "not a leader"})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		err := client.RaftPromote("server-1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a leader")
+	})
+
+	t.Run("RequestFailure", func(t *testing.T) {
+		t.Parallel()
+
+		client := New("http://invalid-url", false)
+		err := client.RaftPromote("server-1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to perform promote request")
+	})
+}
+
+func TestRaftDemote(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SuccessfulDemote", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1alpha/raft/demote", r.URL.Path)
+			assert.Equal(t, http.MethodPost, r.Method)
+
+			var req raftDemoteRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			assert.NoError(t, err)
+			assert.Equal(t, "server-1", req.ServerID)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(response{Status: "success"})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		err := client.RaftDemote("server-1")
+		require.NoError(t, err)
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(response{Status: "error", Error: "not a leader"})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		err := client.RaftDemote("server-1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a leader")
+	})
+
+	t.Run("RequestFailure", func(t *testing.T) {
+		t.Parallel()
+
+		client := New("http://invalid-url", false)
+		err := client.RaftDemote("server-1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to perform demote request")
+	})
+}
+
+func TestRaftLeave(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SuccessfulLeave", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1alpha/raft/leave", r.URL.Path)
+			assert.Equal(t, http.MethodPost, r.Method)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(response{Status: "success"})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		err := client.RaftLeave()
+		require.NoError(t, err)
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(response{Status: "error", Error: "leaving would break the quorum"})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		err := client.RaftLeave()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "leaving would break the quorum")
+	})
+
+	t.Run("RequestFailure", func(t *testing.T) {
+		t.Parallel()
+
+		client := New("http://invalid-url", false)
+		err := client.RaftLeave()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to perform leave request")
+	})
+}
+
+func TestRaftTransferLeadership(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SuccessfulTransfer", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1alpha/raft/leadership-transfer", r.URL.Path)
+			assert.Equal(t, http.MethodPost, r.Method)
+
+			var req raftLeadershipTransferRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			assert.NoError(t, err)
+			assert.Equal(t, "server-2", req.ServerID)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(response{Status: "success"})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		err := client.RaftTransferLeadership("server-2")
+		require.NoError(t, err)
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(response{Status: "error", Error: "server is not a voter"})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		err := client.RaftTransferLeadership("server-2")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "server is not a voter")
+	})
+
+	t.Run("RequestFailure", func(t *testing.T) {
+		t.Parallel()
+
+		client := New("http://invalid-url", false)
+		err := client.RaftTransferLeadership("")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to perform leadership transfer request")
+	})
+}
+
 func TestRaftKVGet(t *testing.T) {
 	t.Parallel()
 
@@ -784,6 +1132,57 @@ func TestRaftKVDelete(t *testing.T) {
 	})
 }
 
+func TestRaftTokenCreate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SuccessfulCreate", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1alpha/raft/token", r.URL.Path)
+			assert.Equal(t, http.MethodPost, r.Method)
+
+			var req raftTokenCreateRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1800), req.TTL)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(response{
+				Status: "success",
+				Data:   map[string]any{"token": "secret"},
+			})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		data, err := client.RaftTokenCreate(30 * time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"token": "secret"}, data)
+	})
+
+	t.Run("APIError", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(response{
+				Status: "error",
+				Error:  "not a leader",
+			})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		data, err := client.RaftTokenCreate(time.Hour)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a leader")
+		assert.Nil(t, data)
+	})
+}
+
 func TestRaftInfo(t *testing.T) {
 	t.Parallel()
 
@@ -897,3 +1296,94 @@ func TestRaftInfo(t *testing.T) {
 		assert.Nil(t, data)
 	})
 }
+
+func TestAuditList(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WithSince", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1alpha/audit", r.URL.Path)
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "2025-01-01T00:00:00Z", r.URL.Query().Get("since"))
+			assert.Equal(t, "10", r.URL.Query().Get("after"))
+			assert.Equal(t, "50", r.URL.Query().Get("limit"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(response{
+				Status: "success",
+				Data:   map[string]any{"intact": true, "records": []any{}},
+			})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		data, err := client.AuditList(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 10, 50)
+		require.NoError(t, err)
+
+		auditLog, ok := data.(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, true, auditLog["intact"])
+	})
+
+	t.Run("WithoutSince", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.False(t, r.URL.Query().Has("since"))
+			assert.False(t, r.URL.Query().Has("after"))
+			assert.False(t, r.URL.Query().Has("limit"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(response{Status: "error", Error: "audit log is corrupted"})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		_, err := client.AuditList(time.Time{}, 0, 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "audit log is corrupted")
+	})
+}
+
+func TestAuditAppend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1alpha/audit", r.URL.Path)
+			assert.Equal(t, http.MethodPost, r.Method)
+
+			var body map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "raft.leave", body["action"])
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(response{Status: "success"})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		require.NoError(t, client.AuditAppend(map[string]string{"action": "raft.leave"}))
+	})
+
+	t.Run("NotLeader", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusMisdirectedRequest)
+			_ = json.NewEncoder(w).Encode(response{Status: "error", Code: CodeNotLeader, Error: "not a leader"})
+		}))
+		defer server.Close()
+
+		client := New(server.URL, false)
+		require.ErrorIs(t, client.AuditAppend(map[string]string{"action": "raft.leave"}), ErrNotLeader)
+	})
+}
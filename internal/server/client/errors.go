@@ -5,7 +5,49 @@ import (
 	"fmt"
 )
 
-var ErrUnknownResponseFormat = errors.New("unknown response format")
+// Error codes returned by the server API
+const (
+	CodeNotLeader        = "not_leader"
+	CodeValidationFailed = "validation_failed"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeConflict         = "conflict"
+	CodeTimeout          = "timeout"
+	CodeInternal         = "internal"
+	CodeNotImplemented   = "not_implemented"
+)
+
+const (
+	detailLeader = "leader"
+	detailField  = "field"
+)
+
+var (
+	ErrUnknownResponseFormat = errors.New("unknown response format")
+	ErrNoCommonAPIVersion    = errors.New("no API version supported by both client and server")
+	ErrNotLeader             = errors.New("not a leader")
+	ErrValidationFailed      = errors.New("validation failed")
+	ErrUnauthorized          = errors.New("unauthorized")
+	ErrForbidden             = errors.New("forbidden")
+	ErrNotFound              = errors.New("not found")
+	ErrConflict              = errors.New("conflict")
+	ErrTimeout               = errors.New("timeout")
+	ErrInternal              = errors.New("internal error")
+	ErrNotImplemented        = errors.New("not implemented")
+)
+
+var codeErrors = map[string]error{
+	CodeNotLeader:        ErrNotLeader,
+	CodeValidationFailed: ErrValidationFailed,
+	CodeUnauthorized:     ErrUnauthorized,
+	CodeForbidden:        ErrForbidden,
+	CodeNotFound:         ErrNotFound,
+	CodeConflict:         ErrConflict,
+	CodeTimeout:          ErrTimeout,
+	CodeInternal:         ErrInternal,
+	CodeNotImplemented:   ErrNotImplemented,
+}
 
 type StatusCodeError struct {
 	Code int
@@ -15,6 +57,26 @@ type ServerError struct {
 	Details string
 }
 
+// APIError is the typed error returned by the server, so it's matched with errors.Is against ErrNotLeader, etc.
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Details map[string]string
+}
+
+// NotLeaderError is returned when the request must be sent to the leader. Leader is empty if it's unknown
+type NotLeaderError struct {
+	*APIError
+	Leader string
+}
+
+// ValidationError is returned when the request is invalid. Field is empty if the whole request is malformed
+type ValidationError struct {
+	*APIError
+	Field string
+}
+
 func (e *StatusCodeError) Error() string {
 	return fmt.Sprintf("bad status code: %d", e.Code)
 }
@@ -22,3 +84,40 @@ func (e *StatusCodeError) Error() string {
 func (e *ServerError) Error() string {
 	return "server error: " + e.Details
 }
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("server error: %s: %s", e.Code, e.Message)
+}
+
+func (e *APIError) Is(target error) bool {
+	err, ok := codeErrors[e.Code]
+
+	return ok && err == target
+}
+
+func (e *NotLeaderError) Unwrap() error {
+	return e.APIError
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.APIError
+}
+
+// newAPIError converts the error response to the most specific error type
+func newAPIError(status int, res *response) error {
+	apiErr := &APIError{
+		Status:  status,
+		Code:    res.Code,
+		Message: res.Error,
+		Details: res.Details,
+	}
+
+	switch apiErr.Code {
+	case CodeNotLeader:
+		return &NotLeaderError{APIError: apiErr, Leader: apiErr.Details[detailLeader]}
+	case CodeValidationFailed:
+		return &ValidationError{APIError: apiErr, Field: apiErr.Details[detailField]}
+	}
+
+	return apiErr
+}
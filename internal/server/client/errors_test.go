@@ -43,3 +43,95 @@ func TestErrorsAreComparable(t *testing.T) {
 		t.Errorf("expected ErrUnknownResponseFormat to be comparable to itself")
 	}
 }
+
+func TestAPIError(t *testing.T) {
+	t.Parallel()
+
+	err := &APIError{Status: 409, Code: CodeConflict, Message: "server is not a voter"}
+
+	if err.Error() != "server error: conflict: server is not a voter" {
+		t.Errorf("unexpected error message %q", err.Error())
+	}
+
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("expected error to be ErrConflict")
+	}
+
+	if errors.Is(err, ErrNotFound) {
+		t.Errorf("expected error not to be ErrNotFound")
+	}
+
+	if errors.Is(&APIError{Code: "unknown"}, ErrInternal) {
+		t.Errorf("expected unknown code not to match any error")
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotLeader", func(t *testing.T) {
+		t.Parallel()
+
+		err := newAPIError(421, &response{
+			Code:    CodeNotLeader,
+			Error:   "not a leader",
+			Details: map[string]string{"leader": "http://10.0.0.1:7080"},
+		})
+
+		var notLeaderErr *NotLeaderError
+		if !errors.As(err, &notLeaderErr) {
+			t.Fatalf("expected NotLeaderError, got %T", err)
+		}
+
+		if notLeaderErr.Leader != "http://10.0.0.1:7080" {
+			t.Errorf("unexpected leader %q", notLeaderErr.Leader)
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Status != 421 {
+			t.Errorf("expected APIError with status 421")
+		}
+
+		if !errors.Is(err, ErrNotLeader) {
+			t.Errorf("expected error to be ErrNotLeader")
+		}
+	})
+
+	t.Run("ValidationFailed", func(t *testing.T) {
+		t.Parallel()
+
+		err := newAPIError(400, &response{
+			Code:    CodeValidationFailed,
+			Error:   "invalid addr",
+			Details: map[string]string{"field": "addr"},
+		})
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected ValidationError, got %T", err)
+		}
+
+		if validationErr.Field != "addr" {
+			t.Errorf("unexpected field %q", validationErr.Field)
+		}
+
+		if !errors.Is(err, ErrValidationFailed) {
+			t.Errorf("expected error to be ErrValidationFailed")
+		}
+	})
+
+	t.Run("Other", func(t *testing.T) {
+		t.Parallel()
+
+		err := newAPIError(404, &response{Code: CodeNotFound, Error: "unknown server"})
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != CodeNotFound {
+			t.Fatalf("expected APIError with not_found code, got %v", err)
+		}
+
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected error to be ErrNotFound")
+		}
+	})
+}
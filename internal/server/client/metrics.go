@@ -0,0 +1,66 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VictoriaMetrics/metrics"
+	"resty.dev/v3"
+)
+
+// attachMetrics counts the calls to the server API, their latency, retries and the calls
+// rejected by the open circuit breaker.
+func (c *Client) attachMetrics() {
+	c.rclient.OnSuccess(func(_ *resty.Client, res *resty.Response) {
+		labels := fmt.Sprintf(`{path=%q,status="%s"}`, c.metricsPath(res.Request), strconv.Itoa(res.StatusCode()))
+
+		metrics.GetOrCreateCounter("maf_server_client_requests_total" + labels).Inc()
+		metrics.GetOrCreateHistogram("maf_server_client_request_duration_seconds" + labels).
+			Update(res.Duration().Seconds())
+	})
+	c.rclient.OnError(func(req *resty.Request, err error) {
+		if errors.Is(err, resty.ErrCircuitBreakerOpen) {
+			metrics.GetOrCreateCounter("maf_server_client_circuit_breaker_rejections_total").Inc()
+		}
+
+		metrics.GetOrCreateCounter(fmt.Sprintf(
+			`maf_server_client_requests_total{path=%q,status="error"}`,
+			c.metricsPath(req),
+		)).Inc()
+	})
+	c.rclient.AddRetryHooks(func(res *resty.Response, _ error) {
+		path := ""
+		if res != nil {
+			path = c.metricsPath(res.Request)
+		}
+
+		metrics.GetOrCreateCounter(fmt.Sprintf(`maf_server_client_retries_total{path=%q}`, path)).Inc()
+	})
+}
+
+// setBreakerStateMetric exposes the circuit breaker state of the host: 0 is closed, 1 is open and 2 is half-open.
+// It's set on the state changes only, the breaker of the host is shared by all its clients
+func (c *Client) setBreakerStateMetric(state breakerState) {
+	metrics.GetOrCreateGauge(fmt.Sprintf(`maf_server_client_circuit_breaker_state{host=%q}`, c.Host), nil).
+		Set(float64(state))
+}
+
+// metricsPath returns the API path of the request, with the keys and server IDs collapsed to keep the cardinality low.
+func (c *Client) metricsPath(req *resty.Request) string {
+	if req == nil {
+		return ""
+	}
+
+	path := strings.TrimPrefix(req.URL, c.urlPrefix)
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	if template, ok := matchRoute(c.APIVersion, path); ok {
+		return template
+	}
+
+	return path
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"resty.dev/v3"
+)
+
+func TestMetricsPath(t *testing.T) {
+	t.Parallel()
+
+	client := New("http://127.0.0.1:7080", false)
+
+	tests := map[string]string{
+		"http://127.0.0.1:7080/api/v1alpha/raft/join":      "/raft/join",
+		"http://127.0.0.1:7080/api/v1alpha/raft/kv/leader": "/raft/kv/:key",
+		"http://127.0.0.1:7080/api/v1alpha/raft/info?x=1":  "/raft/info",
+	}
+
+	for url, expected := range tests {
+		assert.Equal(t, expected, client.metricsPath(&resty.Request{URL: url}))
+	}
+
+	assert.Empty(t, client.metricsPath(nil))
+}
+
+func TestClientMetrics(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(response{Status: "success"})
+	}))
+	defer server.Close()
+
+	counter := metrics.GetOrCreateCounter(`maf_server_client_requests_total{path="/raft/leave",status="200"}`)
+	before := counter.Get()
+
+	client := New(server.URL, false)
+	require.NoError(t, client.RaftLeave())
+	// Other tests may call the same endpoint in parallel
+	assert.GreaterOrEqual(t, counter.Get(), before+1)
+}
+
+func TestBreakerStateMetric(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	gauge := metrics.GetOrCreateGauge(fmt.Sprintf(`maf_server_client_circuit_breaker_state{host=%q}`, server.URL), nil)
+
+	// every call is made with a new client, as the raft worker does
+	for range defaultBreakerFailureThreshold {
+		client := New(server.URL, false)
+		client.rclient.SetRetryCount(0)
+		require.Error(t, client.RaftLeave())
+	}
+
+	assert.InDelta(t, float64(breakerOpen), gauge.Get(), 0)
+
+	client := New(server.URL, false)
+	require.ErrorIs(t, client.RaftLeave(), resty.ErrCircuitBreakerOpen)
+	assert.InDelta(t, float64(breakerOpen), gauge.Get(), 0)
+}
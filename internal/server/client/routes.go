@@ -0,0 +1,103 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	APIVersionV1      = "v1"
+	APIVersionV1Alpha = "v1alpha"
+)
+
+const (
+	endpointJoin               = "join"
+	endpointForget             = "forget"
+	endpointPromote            = "promote"
+	endpointDemote             = "demote"
+	endpointLeave              = "leave"
+	endpointLeadershipTransfer = "leadership-transfer"
+	endpointTokenCreate        = "token-create"
+	endpointInfo               = "info"
+	endpointKVGet              = "kv-get"
+	endpointKVSet              = "kv-set"
+	endpointKVDelete           = "kv-delete"
+	endpointAuditList          = "audit-list"
+	endpointAuditAppend        = "audit-append"
+)
+
+// supportedAPIVersions are the API versions the client speaks, the newest first
+var supportedAPIVersions = []string{APIVersionV1, APIVersionV1Alpha}
+
+type route struct {
+	method string
+	// Path relative to the API version prefix, with the {serverId} and {key} placeholders
+	path string
+}
+
+var routes = map[string]map[string]route{
+	APIVersionV1: {
+		endpointJoin:               {http.MethodPost, "/cluster/servers"},
+		endpointForget:             {http.MethodDelete, "/cluster/servers/{serverId}"},
+		endpointPromote:            {http.MethodPost, "/cluster/servers/{serverId}/promote"},
+		endpointDemote:             {http.MethodPost, "/cluster/servers/{serverId}/demote"},
+		endpointLeave:              {http.MethodPost, "/cluster/leave"},
+		endpointLeadershipTransfer: {http.MethodPost, "/cluster/leadership-transfer"},
+		endpointTokenCreate:        {http.MethodPost, "/cluster/tokens"},
+		endpointInfo:               {http.MethodGet, "/cluster"},
+		endpointKVGet:              {http.MethodGet, "/kv/{key}"},
+		endpointKVSet:              {http.MethodPut, "/kv/{key}"},
+		endpointKVDelete:           {http.MethodDelete, "/kv/{key}"},
+		endpointAuditList:          {http.MethodGet, "/audit"},
+		endpointAuditAppend:        {http.MethodPost, "/audit"},
+	},
+	APIVersionV1Alpha: {
+		endpointJoin:               {http.MethodPost, "/raft/join"},
+		endpointForget:             {http.MethodPost, "/raft/forget"},
+		endpointPromote:            {http.MethodPost, "/raft/promote"},
+		endpointDemote:             {http.MethodPost, "/raft/demote"},
+		endpointLeave:              {http.MethodPost, "/raft/leave"},
+		endpointLeadershipTransfer: {http.MethodPost, "/raft/leadership-transfer"},
+		endpointTokenCreate:        {http.MethodPost, "/raft/token"},
+		endpointInfo:               {http.MethodGet, "/raft/info"},
+		endpointKVGet:              {http.MethodGet, "/raft/kv/{key}"},
+		endpointKVSet:              {http.MethodPost, "/raft/kv"},
+		endpointKVDelete:           {http.MethodDelete, "/raft/kv/{key}"},
+		endpointAuditList:          {http.MethodGet, "/audit"},
+		endpointAuditAppend:        {http.MethodPost, "/audit"},
+	},
+}
+
+// matchRoute returns the path template of the route matching the request path, e.g. /raft/kv/:key
+func matchRoute(version, path string) (string, bool) {
+	segments := strings.Split(path, "/")
+
+	for _, r := range routes[version] {
+		templateSegments := strings.Split(r.path, "/")
+		if len(templateSegments) != len(segments) {
+			continue
+		}
+
+		matched := true
+
+		for i, segment := range templateSegments {
+			if isPlaceholder(segment) {
+				templateSegments[i] = ":" + strings.Trim(segment, "{}")
+			} else if segment != segments[i] {
+				matched = false
+
+				break
+			}
+		}
+
+		if matched {
+			return strings.Join(templateSegments, "/"), true
+		}
+	}
+
+	return "", false
+}
+
+func isPlaceholder(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
@@ -1,5 +1,7 @@
 package client
 
+import "encoding/json"
+
 const (
 	statusSuccess = "success"
 	statusError   = "error"
@@ -7,22 +9,49 @@ const (
 )
 
 type response struct {
-	Status string `json:"status"`
-	Data   any    `json:"data,omitempty"`
-	Error  string `json:"error,omitempty"`
+	Status  string            `json:"status"`
+	Data    any               `json:"data,omitempty"`
+	Error   string            `json:"error,omitempty"`
+	Code    string            `json:"code,omitempty"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// v1Error is the typed error of the v1 API
+type v1Error struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+type apiVersionsResponse struct {
+	Versions []string `json:"versions"`
 }
 
 type raftJoinRequest struct {
 	ServerID string `json:"serverId"`
 	Addr     string `json:"addr"`
+	Nonvoter bool   `json:"nonvoter"`
+	Token    string `json:"token"`
+}
+
+type raftTokenCreateRequest struct {
+	TTL int64 `json:"ttl"`
 }
 
 type raftForgetRequest struct {
 	ServerID string `json:"serverId"`
 }
 
-type raftKVGetRequest struct {
-	Key string `json:"key"`
+type raftPromoteRequest struct {
+	ServerID string `json:"serverId"`
+}
+
+type raftDemoteRequest struct {
+	ServerID string `json:"serverId"`
+}
+
+type raftLeadershipTransferRequest struct {
+	ServerID string `json:"serverId"`
 }
 
 type raftKVSetRequest struct {
@@ -42,6 +71,47 @@ type TLSConfig struct {
 	ServerCertFile string
 }
 
+// UnmarshalJSON reads both the v1alpha response and the v1 one, which has no status and the error object
+func (r *response) UnmarshalJSON(data []byte) error {
+	type alias response
+
+	raw := struct {
+		*alias
+
+		Error json.RawMessage `json:"error,omitempty"`
+	}{
+		alias: (*alias)(r),
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if len(raw.Error) == 0 || string(raw.Error) == "null" {
+		if r.Status == "" {
+			r.Status = statusSuccess
+		}
+
+		return nil
+	}
+
+	if raw.Error[0] != '{' {
+		return json.Unmarshal(raw.Error, &r.Error)
+	}
+
+	var v1Err v1Error
+	if err := json.Unmarshal(raw.Error, &v1Err); err != nil {
+		return err
+	}
+
+	r.Status = statusError
+	r.Error = v1Err.Message
+	r.Code = v1Err.Code
+	r.Details = v1Err.Details
+
+	return nil
+}
+
 func (r *response) IsSuccess() bool {
 	return r.Status == statusSuccess
 }
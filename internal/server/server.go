@@ -2,15 +2,21 @@ package server
 
 import (
 	"fmt"
+	"os"
+	"os/signal"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/rs/zerolog/log"
 
 	"github.com/weastur/maf/internal/server/worker/fiber"
+	"github.com/weastur/maf/internal/server/worker/grpc"
 	"github.com/weastur/maf/internal/server/worker/raft"
+	"github.com/weastur/maf/internal/server/worker/raft/discovery"
 
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
 	loggingUtils "github.com/weastur/maf/internal/utils/logging"
 	sentryWrapper "github.com/weastur/maf/internal/utils/sentry"
 
@@ -36,20 +42,40 @@ type Death interface {
 	WaitForDeathWithFunc(f func())
 }
 
+// Reloadable are the settings the running server applies on SIGHUP without restart
+type Reloadable struct {
+	LogLevel        string
+	Auth            *v1alphaUtils.AuthConfig
+	RateLimit       int
+	RateLimitWindow time.Duration
+	Peers           []string
+	Discovery       discovery.Provider
+}
+
+type PeersSetter interface {
+	SetDiscovery(peers []string, provider discovery.Provider)
+}
+
 type Config struct {
 	LogLevel  string
 	LogPretty bool
 	SentryDSN string
+	// Reload re-reads the config on SIGHUP and applies it, the config is kept only if it's applied.
+	// The reload is disabled if it's nil
+	Reload func(apply func(settings *Reloadable) error) error
 }
 
 type Server struct {
 	fiberConfig *fiber.Config
+	grpcConfig  *grpc.Config
 	raftConfig  *raft.Config
 	config      *Config
 	sentry      Sentry
 	workers     []Worker
 	death       Death
 	wg          sync.WaitGroup
+	peers       PeersSetter
+	hup         chan os.Signal
 }
 
 var (
@@ -61,11 +87,13 @@ func Get(
 	config *Config,
 	raftConfig *raft.Config,
 	fiberConfig *fiber.Config,
+	grpcConfig *grpc.Config,
 ) *Server {
 	once.Do(func() {
 		instance = &Server{
 			config:      config,
 			fiberConfig: fiberConfig,
+			grpcConfig:  grpcConfig,
 			raftConfig:  raftConfig,
 			death:       DEATH.NewDeath(SYS.SIGINT, SYS.SIGTERM),
 			wg:          sync.WaitGroup{},
@@ -78,6 +106,11 @@ func Get(
 func (s *Server) onDeath() {
 	log.Trace().Msg("Death callback called")
 
+	if s.hup != nil {
+		signal.Stop(s.hup)
+		close(s.hup)
+	}
+
 	slices.Reverse(s.workers)
 
 	for _, worker := range s.workers {
@@ -103,9 +136,20 @@ func (s *Server) Init() error {
 	}
 
 	raftWorker := raft.New(s.raftConfig, s.sentry.Fork("raft"))
+	s.peers = raftWorker
 	fiberWorker := fiber.New(s.fiberConfig, raftWorker, s.sentry.Fork("fiber"))
 	s.workers = []Worker{raftWorker, fiberWorker}
 
+	// gRPC API is optional, it's served only if the address is set
+	if s.grpcConfig != nil && s.grpcConfig.Addr != "" {
+		grpcWorker, err := grpc.New(s.grpcConfig, raftWorker, s.sentry.Fork("grpc"))
+		if err != nil {
+			return fmt.Errorf("failed to run server: %w", err)
+		}
+
+		s.workers = append(s.workers, grpcWorker)
+	}
+
 	return nil
 }
 
@@ -116,5 +160,58 @@ func (s *Server) Run() {
 		worker.Run(&s.wg)
 	}
 
+	if s.config != nil && s.config.Reload != nil {
+		s.watchReload()
+	}
+
 	s.death.WaitForDeathWithFunc(s.onDeath)
 }
+
+func (s *Server) watchReload() {
+	s.hup = make(chan os.Signal, 1)
+	signal.Notify(s.hup, SYS.SIGHUP)
+
+	go func() {
+		for range s.hup {
+			s.reload()
+		}
+	}()
+}
+
+// reload applies the reloaded config to the running workers. The config is rejected as a whole
+// if it's invalid, changes the settings which require a restart, e.g. the listen addresses,
+// or can't be applied, e.g. enables or disables the auth.
+func (s *Server) reload() {
+	log.Info().Msg("Reloading config")
+
+	if err := s.config.Reload(s.apply); err != nil {
+		log.Error().Err(err).Msg("Config reload rejected, keeping the current config")
+
+		return
+	}
+
+	log.Info().Msg("Config reloaded")
+}
+
+func (s *Server) apply(settings *Reloadable) error {
+	// The credentials go first, since they are the only settings which may still fail to apply
+	if s.fiberConfig.Auth != nil && settings.Auth != nil {
+		if err := s.fiberConfig.Auth.Update(settings.Auth); err != nil {
+			return fmt.Errorf("failed to update auth: %w", err)
+		}
+	}
+
+	if err := loggingUtils.SetLevel(settings.LogLevel); err != nil {
+		return fmt.Errorf("failed to set log level: %w", err)
+	}
+
+	if s.fiberConfig.RateLimiter != nil {
+		s.fiberConfig.RateLimiter.Set(settings.RateLimit, settings.RateLimitWindow)
+	}
+
+	if s.peers != nil {
+		s.peers.SetDiscovery(settings.Peers, settings.Discovery)
+	}
+
+	return nil
+}
@@ -1,9 +1,13 @@
 package server
 
 import (
+	"errors"
 	"os"
+	"os/signal"
 	"sync"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/rs/zerolog"
@@ -12,7 +16,11 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/weastur/maf/internal/server/worker/fiber"
+	"github.com/weastur/maf/internal/server/worker/grpc"
 	"github.com/weastur/maf/internal/server/worker/raft"
+	"github.com/weastur/maf/internal/server/worker/raft/discovery"
+	httpUtils "github.com/weastur/maf/internal/utils/http"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
 	sentryWrapper "github.com/weastur/maf/internal/utils/sentry"
 )
 
@@ -73,6 +81,14 @@ func (m *MockDeath) WaitForDeathWithFunc(f func()) {
 	m.Called(f)
 }
 
+type MockPeersSetter struct {
+	mock.Mock
+}
+
+func (m *MockPeersSetter) SetDiscovery(peers []string, provider discovery.Provider) {
+	m.Called(peers, provider)
+}
+
 func TestGet(t *testing.T) {
 	t.Parallel()
 
@@ -85,16 +101,18 @@ func TestGet(t *testing.T) {
 		NodeID: "test-node",
 	}
 	fiberConfig := &fiber.Config{}
+	grpcConfig := &grpc.Config{}
 
-	serverInstance := Get(config, raftConfig, fiberConfig)
+	serverInstance := Get(config, raftConfig, fiberConfig, grpcConfig)
 
 	assert.NotNil(t, serverInstance)
 
 	assert.Equal(t, config, serverInstance.config)
 	assert.Equal(t, raftConfig, serverInstance.raftConfig)
 	assert.Equal(t, fiberConfig, serverInstance.fiberConfig)
+	assert.Equal(t, grpcConfig, serverInstance.grpcConfig)
 
-	secondInstance := Get(nil, nil, nil)
+	secondInstance := Get(nil, nil, nil, nil)
 
 	assert.Equal(t, serverInstance, secondInstance)
 }
@@ -161,15 +179,97 @@ func TestInit(t *testing.T) {
 		NodeID: "test-node",
 	}
 	fiberConfig := &fiber.Config{}
+	grpcConfig := &grpc.Config{Addr: "127.0.0.1:0"}
 
 	server := &Server{
 		config:      config,
 		fiberConfig: fiberConfig,
+		grpcConfig:  grpcConfig,
 		raftConfig:  raftConfig,
 	}
 
 	err := server.Init()
 
 	require.NoError(t, err)
-	assert.Len(t, server.workers, 2)
+	assert.Len(t, server.workers, 3)
+}
+
+func TestReload(t *testing.T) {
+	auth, err := v1alphaUtils.NewAuthenticator(&v1alphaUtils.AuthConfig{
+		Tokens: []v1alphaUtils.TokenConfig{{Name: "old", Role: "read", Token: "old-token"}},
+	})
+	require.NoError(t, err)
+
+	mockPeers := &MockPeersSetter{}
+	mockPeers.On("SetDiscovery", []string{"http://node2"}, nil).Return().Once()
+
+	settings := &Reloadable{
+		LogLevel: "warn",
+		Auth: &v1alphaUtils.AuthConfig{
+			Tokens: []v1alphaUtils.TokenConfig{{Name: "new", Role: "admin", Token: "new-token"}},
+		},
+		RateLimit:       10,
+		RateLimitWindow: time.Minute,
+		Peers:           []string{"http://node2"},
+	}
+
+	reloaded := make(chan struct{}, 1)
+
+	server := &Server{
+		config: &Config{
+			Reload: func(apply func(settings *Reloadable) error) error {
+				reloaded <- struct{}{}
+
+				return apply(settings)
+			},
+		},
+		fiberConfig: &fiber.Config{Auth: auth, RateLimiter: httpUtils.NewRateLimiter(100, time.Second)},
+		peers:       mockPeers,
+	}
+
+	t.Cleanup(func() { zerolog.SetGlobalLevel(zerolog.Disabled) })
+
+	server.watchReload()
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "config is not reloaded on SIGHUP")
+	}
+
+	assert.Eventually(t, func() bool {
+		_, ok := auth.Authenticate("new-token")
+
+		return ok && zerolog.GlobalLevel() == zerolog.WarnLevel
+	}, time.Second, 10*time.Millisecond)
+	mockPeers.AssertExpectations(t)
+
+	// The rejected config leaves the current settings as they are
+	server.config.Reload = func(_ func(settings *Reloadable) error) error {
+		return errors.New("unsafe change")
+	}
+	server.reload()
+
+	_, ok := auth.Authenticate("new-token")
+	assert.True(t, ok)
+
+	// The auth mode change fails to apply, so the config reports the reload as failed to roll it back
+	var applyErr error
+
+	server.config.Reload = func(apply func(settings *Reloadable) error) error {
+		applyErr = apply(&Reloadable{LogLevel: "debug", Auth: &v1alphaUtils.AuthConfig{Disabled: true}})
+
+		return applyErr
+	}
+	server.reload()
+
+	require.ErrorIs(t, applyErr, v1alphaUtils.ErrAuthModeChange)
+	assert.Equal(t, zerolog.WarnLevel, zerolog.GlobalLevel())
+
+	_, ok = auth.Authenticate("new-token")
+	assert.True(t, ok)
+
+	signal.Stop(server.hup)
+	close(server.hup)
 }
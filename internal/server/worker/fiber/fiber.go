@@ -1,20 +1,24 @@
 package fiber
 
 import (
+	"io/fs"
 	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	serverAPI "github.com/weastur/maf/internal/server/worker/fiber/http/api"
+	v1 "github.com/weastur/maf/internal/server/worker/fiber/http/api/v1"
 	"github.com/weastur/maf/internal/server/worker/fiber/http/api/v1alpha"
 	"github.com/weastur/maf/internal/server/worker/raft"
 	"github.com/weastur/maf/internal/utils"
 	httpUtils "github.com/weastur/maf/internal/utils/http"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
 	"github.com/weastur/maf/internal/utils/logging"
 )
 
-const LeaderAPIAddrKey = "leaderAPIAddr"
+const LeaderAPIAddrKey = serverAPI.LeaderAPIAddrKey
 
 type Consensus interface {
 	IsReady() bool
@@ -29,10 +33,14 @@ type Config struct {
 	CertFile        string
 	KeyFile         string
 	ClientCertFile  string
+	Socket          string
+	SocketMode      fs.FileMode
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	IdleTimeout     time.Duration
 	ShutdownTimeout time.Duration
+	Auth            *v1alphaUtils.Authenticator
+	RateLimiter     *httpUtils.RateLimiter
 }
 
 type Sentry interface {
@@ -46,6 +54,8 @@ type Fiber struct {
 	logger              zerolog.Logger
 	leadershipChangesCh raft.LeadershipChangesCh
 	sentry              Sentry
+	// streamsDone ends the long-living responses, e.g. event streams, which would block the shutdown otherwise
+	streamsDone chan struct{}
 }
 
 func New(config *Config, co Consensus, sentry Sentry) *Fiber {
@@ -56,14 +66,17 @@ func New(config *Config, co Consensus, sentry Sentry) *Fiber {
 		co:                  co,
 		logger:              log.With().Str(logging.ComponentCtxKey, "fiber").Logger(),
 		leadershipChangesCh: make(raft.LeadershipChangesCh, 1),
+		streamsDone:         make(chan struct{}),
 		sentry:              sentry,
 	}
 
 	f.app = fiber.New(
 		fiber.Config{
-			AppName:               "maf-server " + utils.AppVersion(),
-			ServerHeader:          "maf-server/" + utils.AppVersion(),
-			RequestMethods:        []string{fiber.MethodGet, fiber.MethodHead, fiber.MethodPost, fiber.MethodDelete},
+			AppName:      "maf-server " + utils.AppVersion(),
+			ServerHeader: "maf-server/" + utils.AppVersion(),
+			RequestMethods: []string{
+				fiber.MethodGet, fiber.MethodHead, fiber.MethodPost, fiber.MethodPut, fiber.MethodDelete,
+			},
 			ReadTimeout:           f.config.ReadTimeout,
 			WriteTimeout:          f.config.WriteTimeout,
 			IdleTimeout:           f.config.IdleTimeout,
@@ -71,7 +84,8 @@ func New(config *Config, co Consensus, sentry Sentry) *Fiber {
 			ErrorHandler:          httpUtils.ErrorHandler,
 		},
 	)
-	httpUtils.AttachGenericMiddlewares(f.app, f.logger, f)
+	httpUtils.AttachGenericMiddlewares(f.app, f.logger, f, f.config.RateLimiter)
+	utils.ConfigureMetrics(f.app)
 	f.app.Hooks().OnShutdown(func() error {
 		f.logger.Info().Msg("Shutting down server handler")
 
@@ -81,13 +95,21 @@ func New(config *Config, co Consensus, sentry Sentry) *Fiber {
 	co.SubscribeOnLeadershipChanges(f.leadershipChangesCh)
 
 	api := httpUtils.APIGroup(f.app)
+	api.Get("/versions", httpUtils.APIVersionsHandler(v1.Version, v1alpha.Version))
+
+	v1Consensus, ok := f.co.(v1.Consensus)
+	if !ok {
+		panic("Consensus does not implement v1 interface")
+	}
+
+	v1.Get().Init(api, f.logger, v1Consensus, f.config.Auth, f.streamsDone)
 
 	v1alphaConsensus, ok := f.co.(v1alpha.Consensus)
 	if !ok {
 		panic("Consensus does not implement v1alpha interface")
 	}
 
-	v1alpha.Get().Init(api, f.logger, v1alphaConsensus)
+	v1alpha.Get().Init(api, f.logger, v1alphaConsensus, f.config.Auth)
 
 	return f
 }
@@ -128,6 +150,10 @@ func (f *Fiber) WatchLeadershipChanges(done <-chan struct{}) {
 func (f *Fiber) Run(wg *sync.WaitGroup) {
 	f.logger.Info().Msg("Running")
 
+	if f.config.Auth.Disabled() {
+		f.logger.Warn().Msg("API auth is DISABLED, every client gets the admin role. Never use it outside of the development")
+	}
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -143,11 +169,25 @@ func (f *Fiber) Run(wg *sync.WaitGroup) {
 			f.logger.Error().Err(err).Msg("failed to listen")
 		}
 	}()
+
+	if f.config.Socket != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer f.sentry.Recover()
+
+			if err := httpUtils.ListenSocket(f.app, f.logger, f.config.Socket, f.config.SocketMode); err != nil {
+				f.logger.Error().Err(err).Msg("failed to listen on socket")
+			}
+		}()
+	}
 }
 
 func (f *Fiber) Stop() {
 	f.logger.Info().Msg("Stopping")
 
+	close(f.streamsDone)
+
 	if err := f.app.ShutdownWithTimeout(f.config.ShutdownTimeout); err != nil {
 		f.logger.Error().Err(err).Msg("failed to shutdown fiber app")
 	}
@@ -47,8 +47,42 @@ func (m *MockConsensus) IsLeader() bool {
 	return args.Bool(0)
 }
 
-func (m *MockConsensus) Join(serverID, addr string) error {
-	args := m.Called(serverID, addr)
+func (m *MockConsensus) Join(serverID, addr string, nonvoter bool, token string) error {
+	args := m.Called(serverID, addr, nonvoter, token)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) CreateJoinToken(ttl time.Duration) (*raft.JoinToken, error) {
+	args := m.Called(ttl)
+
+	if token, ok := args.Get(0).(*raft.JoinToken); ok {
+		return token, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockConsensus) Promote(serverID string) error {
+	args := m.Called(serverID)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) Demote(serverID string) error {
+	args := m.Called(serverID)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) Leave() error {
+	args := m.Called()
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) TransferLeadership(serverID string) error {
+	args := m.Called(serverID)
 
 	return args.Error(0)
 }
@@ -59,6 +93,42 @@ func (m *MockConsensus) Forget(serverID string) error {
 	return args.Error(0)
 }
 
+func (m *MockConsensus) Audit(entry *raft.AuditEntry) error {
+	args := m.Called(entry)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) AppendAudit(entry *raft.AuditEntry) error {
+	args := m.Called(entry)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) AuditLog(query *raft.AuditQuery) (*raft.AuditLog, error) {
+	args := m.Called(query)
+
+	if auditLog, ok := args.Get(0).(*raft.AuditLog); ok {
+		return auditLog, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockConsensus) SubscribeOnEvents(ch raft.EventsCh, lastEventID uint64) []*raft.Event {
+	args := m.Called(ch, lastEventID)
+
+	if events, ok := args.Get(0).([]*raft.Event); ok {
+		return events
+	}
+
+	return nil
+}
+
+func (m *MockConsensus) UnsubscribeFromEvents(ch raft.EventsCh) {
+	m.Called(ch)
+}
+
 func (m *MockConsensus) GetInfo(verbose bool) (*raft.Info, error) {
 	args := m.Called(verbose)
 
@@ -150,10 +220,17 @@ func TestFiber_Stop(t *testing.T) {
 			config: &Config{
 				ShutdownTimeout: 5 * time.Second,
 			},
+			streamsDone: make(chan struct{}),
 		}
 
 		f.Stop()
 		assert.True(t, shutdownCalled)
+
+		select {
+		case <-f.streamsDone:
+		default:
+			t.Fatal("expected streams to be ended on shutdown")
+		}
 	})
 
 	t.Run("shutdown with error", func(t *testing.T) {
@@ -168,6 +245,7 @@ func TestFiber_Stop(t *testing.T) {
 			config: &Config{
 				ShutdownTimeout: 5 * time.Second,
 			},
+			streamsDone: make(chan struct{}),
 		}
 
 		app.Hooks().OnShutdown(func() error {
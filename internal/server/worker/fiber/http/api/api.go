@@ -0,0 +1,23 @@
+// Package api contains the pieces shared by all the server API versions
+package api
+
+import "github.com/weastur/maf/internal/server/worker/raft"
+
+const (
+	// LeaderAPIAddrKey is the key the leader stores its advertised API address under
+	LeaderAPIAddrKey = "leaderAPIAddr"
+	// SuccessorVersion is the API version the deprecated ones point to
+	SuccessorVersion = "v1"
+)
+
+// KV is the part of the consensus needed to look up the leader address
+type KV interface {
+	Get(key string) (string, bool)
+}
+
+// Auditor is the part of the consensus writing and reading the audit log
+type Auditor interface {
+	Audit(entry *raft.AuditEntry) error
+	AppendAudit(entry *raft.AuditEntry) error
+	AuditLog(query *raft.AuditQuery) (*raft.AuditLog, error)
+}
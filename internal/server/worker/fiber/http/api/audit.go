@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	apiUtils "github.com/weastur/maf/internal/utils/http/api"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+const (
+	auditTargetLocalsKey = "auditTarget"
+	AnonymousActor       = "anonymous"
+	// AuditFailedHeader is set on the successful response if the record of the call wasn't written
+	AuditFailedHeader = "X-Audit-Failed"
+)
+
+// SetAuditTarget records the subject of the call, e.g. server ID or key, for the audit
+func SetAuditTarget(c *fiber.Ctx, target string) {
+	c.Locals(auditTargetLocalsKey, target)
+}
+
+// NewAuditEntry describes the finished call: who made it, on what and with which outcome
+func NewAuditEntry(c *fiber.Ctx, action, requestID string, err error) *raft.AuditEntry {
+	entry := &raft.AuditEntry{
+		Time:      time.Now().UTC(),
+		Actor:     AnonymousActor,
+		Action:    action,
+		RequestID: requestID,
+		Outcome:   raft.AuditOutcomeSuccess,
+	}
+
+	if identity, ok := v1alphaUtils.GetIdentity(c); ok {
+		entry.Actor = identity.Name
+		entry.Role = identity.Role.String()
+	}
+
+	entry.Target, _ = c.Locals(auditTargetLocalsKey).(string)
+
+	if entry.RequestID == "" {
+		entry.RequestID, _ = c.Locals(apiUtils.RequestIDContextKey).(string)
+	}
+
+	if err != nil {
+		entry.Outcome = raft.AuditOutcomeFailure
+		entry.Error = err.Error()
+	}
+
+	return entry
+}
+
+// ReportAuditFailure logs and counts the record which wasn't written. The call itself isn't failed,
+// since its change is already applied and the client would make it again on the retry
+func ReportAuditFailure(logger *zerolog.Logger, action string, err error) {
+	logger.Error().Err(err).Msgf("Failed to write audit record for %s", action)
+
+	metrics.GetOrCreateCounter(fmt.Sprintf(`maf_audit_write_failures_total{action=%q}`, action)).Inc()
+}
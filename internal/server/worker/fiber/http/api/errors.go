@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+
+	hraft "github.com/hashicorp/raft"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+// ClassifyError maps the consensus errors to the API error codes, the rest is classified by the common rules
+func ClassifyError(err error, kv KV) *v1alphaUtils.APIError {
+	switch {
+	case errors.Is(err, raft.ErrNotALeader), errors.Is(err, hraft.ErrNotLeader), errors.Is(err, hraft.ErrLeadershipLost):
+		return v1alphaUtils.NewAPIError(v1alphaUtils.CodeNotLeader, err, leaderDetails(kv))
+	case errors.Is(err, raft.ErrInvalidJoinToken):
+		return v1alphaUtils.NewAPIError(v1alphaUtils.CodeUnauthorized, err, nil)
+	case errors.Is(err, raft.ErrUnknownServer):
+		return v1alphaUtils.NewAPIError(v1alphaUtils.CodeNotFound, err, nil)
+	case errors.Is(err, raft.ErrWildcardAddr):
+		return v1alphaUtils.NewAPIError(
+			v1alphaUtils.CodeValidationFailed, err, map[string]string{v1alphaUtils.DetailField: "addr"},
+		)
+	case errors.Is(err, raft.ErrJoinTokenTTL):
+		return v1alphaUtils.NewAPIError(
+			v1alphaUtils.CodeValidationFailed, err, map[string]string{v1alphaUtils.DetailField: "ttl"},
+		)
+	case errors.Is(err, raft.ErrLeaveForbidden):
+		return v1alphaUtils.NewAPIError(v1alphaUtils.CodeForbidden, err, nil)
+	case errors.Is(err, raft.ErrReservedKey):
+		return v1alphaUtils.NewAPIError(
+			v1alphaUtils.CodeForbidden, err, map[string]string{v1alphaUtils.DetailField: "key"},
+		)
+	case errors.Is(err, raft.ErrNotAVoter),
+		errors.Is(err, raft.ErrLeaveQuorum),
+		errors.Is(err, hraft.ErrLeadershipTransferInProgress):
+		return v1alphaUtils.NewAPIError(v1alphaUtils.CodeConflict, err, nil)
+	case errors.Is(err, hraft.ErrEnqueueTimeout):
+		return v1alphaUtils.NewAPIError(v1alphaUtils.CodeTimeout, err, nil)
+	}
+
+	return v1alphaUtils.AsAPIError(err)
+}
+
+// leaderDetails points the client to the leader API address, if it's known
+func leaderDetails(kv KV) map[string]string {
+	if kv == nil {
+		return nil
+	}
+
+	addr, ok := kv.Get(LeaderAPIAddrKey)
+	if !ok || addr == "" {
+		return nil
+	}
+
+	return map[string]string{v1alphaUtils.DetailLeader: addr}
+}
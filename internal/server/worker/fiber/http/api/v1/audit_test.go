@@ -0,0 +1,190 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	serverAPI "github.com/weastur/maf/internal/server/worker/fiber/http/api"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+func TestAuditMiddleware(t *testing.T) {
+	t.Parallel()
+
+	app, mockConsensus := getTestFiberApp()
+	app.Delete("/test/:id", audit("raft.forget"), serverForgetHandler)
+
+	defer app.Shutdown()
+	mockConsensus.On("Forget", "maf-2").Return(errors.New("forget error")).Once()
+	mockConsensus.On("Audit", mock.MatchedBy(func(entry *raft.AuditEntry) bool {
+		return entry.Action == "raft.forget" &&
+			entry.Target == "maf-2" &&
+			entry.Actor == serverAPI.AnonymousActor &&
+			entry.RequestID == requestID &&
+			entry.Outcome == raft.AuditOutcomeFailure &&
+			entry.Error == "forget error"
+	})).Return(nil).Once()
+
+	req, _ := http.NewRequest(http.MethodDelete, "/test/maf-2", nil)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	mockConsensus.AssertExpectations(t)
+}
+
+func TestAuditMiddlewareFailure(t *testing.T) {
+	t.Parallel()
+
+	app, mockConsensus := getTestFiberApp()
+	app.Delete("/test/:id", audit("raft.forget"), serverForgetHandler)
+
+	defer app.Shutdown()
+	mockConsensus.On("Forget", "maf-2").Return(nil).Once()
+	mockConsensus.On("Audit", mock.Anything).Return(raft.ErrNoLeader).Once()
+
+	req, _ := http.NewRequest(http.MethodDelete, "/test/maf-2", nil)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get(serverAPI.AuditFailedHeader))
+
+	mockConsensus.AssertExpectations(t)
+}
+
+func TestAuditListHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful list", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Get("/test", auditListHandler)
+
+		defer app.Shutdown()
+
+		since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockConsensus.On("AuditLog", &raft.AuditQuery{Since: since, After: 10, Limit: 50}).Return(&raft.AuditLog{
+			Records: []raft.AuditRecord{{Seq: 1, AuditEntry: raft.AuditEntry{Action: "raft.forget"}, Hash: "abc"}},
+			Intact:  true,
+		}, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/test?since=2025-01-01T00:00:00Z&after=10&limit=50", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		data := readResponse(t, resp)["data"].(map[string]any)
+		assert.Equal(t, true, data["intact"])
+		require.Len(t, data["records"], 1)
+		assert.Equal(t, "raft.forget", data["records"].([]any)[0].(map[string]any)["action"])
+	})
+
+	t.Run("invalid since", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Get("/test", auditListHandler)
+
+		defer app.Shutdown()
+
+		req, _ := http.NewRequest(http.MethodGet, "/test?since=yesterday", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		apiErr := readResponse(t, resp)["error"].(map[string]any)
+		assert.Equal(t, "validation_failed", apiErr["code"])
+		assert.Equal(t, map[string]any{"field": "since"}, apiErr["details"])
+
+		mockConsensus.AssertNotCalled(t, "AuditLog", mock.Anything)
+	})
+}
+
+func TestAuditAppendHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful append", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", auditAppendHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("AppendAudit", mock.MatchedBy(func(entry *raft.AuditEntry) bool {
+			return entry.Actor == "admin" && entry.Action == "raft.leave" && entry.Outcome == raft.AuditOutcomeSuccess
+		})).Return(nil).Once()
+
+		body := `{"actor": "admin", "role": "admin", "action": "raft.leave", "outcome": "success"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("forwarder is stamped", func(t *testing.T) {
+		t.Parallel()
+
+		auth, err := v1alphaUtils.NewAuthenticator(&v1alphaUtils.AuthConfig{
+			Tokens:    []v1alphaUtils.TokenConfig{{Name: "maf-2", Role: "admin", Token: "server-token"}},
+			PeerToken: "server-token",
+		})
+		require.NoError(t, err)
+
+		app, mockConsensus := getTestFiberApp()
+		app.Use(v1alphaUtils.AuthMiddleware(auth))
+		app.Post("/test", v1alphaUtils.RequirePeer(), auditAppendHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("AppendAudit", mock.MatchedBy(func(entry *raft.AuditEntry) bool {
+			return entry.Actor == "admin" && entry.Forwarder == "maf-2"
+		})).Return(nil).Once()
+
+		body := `{"actor": "admin", "role": "admin", "action": "raft.leave", "outcome": "success"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Auth-Token", "server-token")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("not leader", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", auditAppendHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("AppendAudit", mock.Anything).Return(raft.ErrNotALeader).Once()
+		mockConsensus.On("Get", serverAPI.LeaderAPIAddrKey).Return("", false).Maybe()
+
+		body := `{"actor": "admin", "action": "raft.leave", "outcome": "failure", "error": "boom"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusMisdirectedRequest, resp.StatusCode)
+		assert.Equal(t, "not_leader", readResponse(t, resp)["error"].(map[string]any)["code"])
+	})
+}
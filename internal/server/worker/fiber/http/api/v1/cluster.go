@@ -0,0 +1,326 @@
+//go:generate replacer
+package v1
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jinzhu/copier"
+	serverAPI "github.com/weastur/maf/internal/server/worker/fiber/http/api"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	v1Utils "github.com/weastur/maf/internal/utils/http/api/v1"
+)
+
+// Get the cluster
+//
+// @Summary      Return the cluster
+// @Description  Return the raft cluster with the state of the server handling the request
+// @Tags         cluster
+// @Success      200 {object} Response{data=Cluster} "Raft cluster"
+// @Failure      default {object} Response{error=Error} "Typed error"
+// @Router       /cluster [get]
+// @Param        include_stats query bool false "Include extended stats"
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func clusterGetHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	coInfo, err := uCtx.co.GetInfo(c.QueryBool("include_stats"))
+	if err != nil {
+		return err
+	}
+
+	data := &Cluster{}
+	if err := copier.Copy(data, coInfo); err != nil {
+		return err
+	}
+
+	return v1Utils.WrapResponse(c, fiber.StatusOK, data)
+}
+
+// Leave the cluster
+//
+// @Summary      Leave the cluster
+// @Description  Remove the server handling the request from the cluster, so it no longer counts toward the quorum.
+// @Description  Refused if the remaining voters can't keep the quorum. The server must be stopped afterwards.
+// @Description  Requires the admin role, like removing any other server
+// @Tags         cluster
+// @Success      200 {object} Response "Empty response"
+// @Failure      default {object} Response{error=Error} "Typed error"
+// @Router       /cluster/leave [post]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func clusterLeaveHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	if err := uCtx.co.Leave(); err != nil {
+		return err
+	}
+
+	return v1Utils.WrapResponse(c, fiber.StatusOK, nil)
+}
+
+// Transfer leadership
+//
+// @Summary      Transfer leadership
+// @Description  Transfer the leadership to the given voter, or to the most up-to-date voter
+// @Description  if the server ID is empty. Must be called on the leader
+// @Tags         cluster
+// @Param        request body LeadershipTransferRequest true "Leadership transfer request"
+// @Success      200 {object} Response "Empty response"
+// @Failure      default {object} Response{error=Error} "Typed error"
+// @Router       /cluster/leadership-transfer [post]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func leadershipTransferHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	transferReq := new(LeadershipTransferRequest)
+	if err := parseAndValidate(c, transferReq); err != nil {
+		return err
+	}
+
+	serverAPI.SetAuditTarget(c, transferReq.ServerID)
+
+	if err := uCtx.co.TransferLeadership(transferReq.ServerID); err != nil {
+		return err
+	}
+
+	return v1Utils.WrapResponse(c, fiber.StatusOK, nil)
+}
+
+// Create join token
+//
+// @Summary      Create join token
+// @Description  Issue a short-lived token the new servers must present to join the cluster.
+// @Description  The token can be used by several servers until it expires. Must be called on the leader
+// @Tags         cluster
+// @Param        request body TokenCreateRequest true "Token create request"
+// @Success      201 {object} Response{data=Token} "Issued join token"
+// @Failure      default {object} Response{error=Error} "Typed error"
+// @Router       /cluster/tokens [post]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func tokenCreateHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	tokenReq := new(TokenCreateRequest)
+	if err := parseAndValidate(c, tokenReq); err != nil {
+		return err
+	}
+
+	token, err := uCtx.co.CreateJoinToken(time.Duration(tokenReq.TTL) * time.Second)
+	if err != nil {
+		return err
+	}
+
+	return v1Utils.WrapResponse(c, fiber.StatusCreated, &Token{Token: token.Token, ExpiresAt: token.ExpiresAt})
+}
+
+// List servers
+//
+// @Summary      List servers
+// @Description  Return the servers of the raft cluster
+// @Tags         cluster
+// @Success      200 {object} Response{data=[]Server} "Servers"
+// @Failure      default {object} Response{error=Error} "Typed error"
+// @Router       /cluster/servers [get]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func serverListHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	coInfo, err := uCtx.co.GetInfo(false)
+	if err != nil {
+		return err
+	}
+
+	data := []Server{}
+	if err := copier.Copy(&data, coInfo.Servers); err != nil {
+		return err
+	}
+
+	return v1Utils.WrapResponse(c, fiber.StatusOK, data)
+}
+
+// Join server to the cluster
+//
+// @Summary      Join server to the cluster
+// @Description  Join the server to the cluster. The server becomes voter in case of success,
+// @Description  or non-voter if requested. Non-voters replicate the log but don't take part in elections.
+// @Description  New servers must present a valid join token, see /cluster/tokens
+// @Tags         cluster
+// @Param        request body ServerJoinRequest true "Join request"
+// @Success      201 {object} Response "Empty response, the server URL is in the Location header"
+// @Failure      default {object} Response{error=Error} "Typed error"
+// @Router       /cluster/servers [post]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func serverJoinHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	joinReq := new(ServerJoinRequest)
+	if err := parseAndValidate(c, joinReq); err != nil {
+		return err
+	}
+
+	serverAPI.SetAuditTarget(c, joinReq.ServerID)
+
+	if err := uCtx.co.Join(joinReq.ServerID, joinReq.Addr, joinReq.Nonvoter, joinReq.Token); err != nil {
+		return err
+	}
+
+	c.Location(c.Path() + "/" + joinReq.ServerID)
+
+	return v1Utils.WrapResponse(c, fiber.StatusCreated, nil)
+}
+
+// Get server
+//
+// @Summary      Return the server
+// @Description  Return the server of the raft cluster
+// @Tags         cluster
+// @Param        id path string true "Server ID"
+// @Success      200 {object} Response{data=Server} "Server"
+// @Failure      default {object} Response{error=Error} "Typed error"
+// @Router       /cluster/servers/{id} [get]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func serverGetHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	serverID := c.Params("id")
+
+	coInfo, err := uCtx.co.GetInfo(false)
+	if err != nil {
+		return err
+	}
+
+	for _, server := range coInfo.Servers {
+		if server.ID == serverID {
+			data := &Server{}
+			if err := copier.Copy(data, &server); err != nil {
+				return err
+			}
+
+			return v1Utils.WrapResponse(c, fiber.StatusOK, data)
+		}
+	}
+
+	return fmt.Errorf("%w: %s", raft.ErrUnknownServer, serverID)
+}
+
+// Forget the server
+//
+// @Summary      Forget the server
+// @Description  Forget the server. The server becomes non-voter and forgotten by the cluster in case of success
+// @Description  and will not participate in the consensus
+// @Tags         cluster
+// @Param        id path string true "Server ID"
+// @Success      200 {object} Response "Empty response"
+// @Failure      default {object} Response{error=Error} "Typed error"
+// @Router       /cluster/servers/{id} [delete]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func serverForgetHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	serverID := c.Params("id")
+	serverAPI.SetAuditTarget(c, serverID)
+
+	if err := uCtx.co.Forget(serverID); err != nil {
+		return err
+	}
+
+	return v1Utils.WrapResponse(c, fiber.StatusOK, nil)
+}
+
+// Promote the server
+//
+// @Summary      Promote the server to voter
+// @Description  Promote the non-voter server to voter. Promoting a voter is a no-op
+// @Tags         cluster
+// @Param        id path string true "Server ID"
+// @Success      200 {object} Response "Empty response"
+// @Failure      default {object} Response{error=Error} "Typed error"
+// @Router       /cluster/servers/{id}/promote [post]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func serverPromoteHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	serverID := c.Params("id")
+	serverAPI.SetAuditTarget(c, serverID)
+
+	if err := uCtx.co.Promote(serverID); err != nil {
+		return err
+	}
+
+	return v1Utils.WrapResponse(c, fiber.StatusOK, nil)
+}
+
+// Demote the server
+//
+// @Summary      Demote the server to non-voter
+// @Description  Demote the voter server to non-voter. The server keeps replicating the log,
+// @Description  but doesn't take part in elections. Demoting a non-voter is a no-op
+// @Tags         cluster
+// @Param        id path string true "Server ID"
+// @Success      200 {object} Response "Empty response"
+// @Failure      default {object} Response{error=Error} "Typed error"
+// @Router       /cluster/servers/{id}/demote [post]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func serverDemoteHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	serverID := c.Params("id")
+	serverAPI.SetAuditTarget(c, serverID)
+
+	if err := uCtx.co.Demote(serverID); err != nil {
+		return err
+	}
+
+	return v1Utils.WrapResponse(c, fiber.StatusOK, nil)
+}
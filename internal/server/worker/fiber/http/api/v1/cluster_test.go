@@ -0,0 +1,325 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/weastur/maf/internal/server/worker/raft"
+)
+
+func testInfo() *raft.Info {
+	return &raft.Info{
+		State: "Leader",
+		ID:    "maf-1",
+		Servers: []raft.Server{
+			{ID: "maf-1", Address: "10.0.0.1:7081", Suffrage: "Voter", Leader: true},
+			{ID: "maf-2", Address: "10.0.0.2:7081", Suffrage: "Nonvoter"},
+		},
+	}
+}
+
+func TestClusterGetHandler(t *testing.T) {
+	t.Parallel()
+
+	app, mockConsensus := getTestFiberApp()
+	app.Get("/test", clusterGetHandler)
+
+	defer app.Shutdown()
+	mockConsensus.On("GetInfo", true).Return(testInfo(), nil).Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/test?include_stats=true", nil)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	data := readResponse(t, resp)["data"].(map[string]any)
+	assert.Equal(t, "Leader", data["state"])
+	assert.Len(t, data["servers"], 2)
+
+	mockConsensus.AssertExpectations(t)
+}
+
+func TestClusterLeaveHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful leave", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", clusterLeaveHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Leave").Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/test", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("quorum loss", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", clusterLeaveHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Leave").Return(raft.ErrLeaveQuorum).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/test", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+		assert.Equal(t, "conflict", readResponse(t, resp)["error"].(map[string]any)["code"])
+	})
+}
+
+func TestLeadershipTransferHandler(t *testing.T) {
+	t.Parallel()
+
+	app, mockConsensus := getTestFiberApp()
+	app.Post("/test", leadershipTransferHandler)
+
+	defer app.Shutdown()
+	mockConsensus.On("TransferLeadership", "maf-2").Return(nil).Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"serverId": "maf-2"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	mockConsensus.AssertExpectations(t)
+}
+
+func TestTokenCreateHandler(t *testing.T) {
+	t.Parallel()
+
+	app, mockConsensus := getTestFiberApp()
+	app.Post("/test", tokenCreateHandler)
+
+	defer app.Shutdown()
+
+	expiresAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := &raft.JoinToken{Token: "secret", ExpiresAt: expiresAt}
+	mockConsensus.On("CreateJoinToken", time.Hour).Return(token, nil).Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"ttl": 3600}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	data := readResponse(t, resp)["data"].(map[string]any)
+	assert.Equal(t, "secret", data["token"])
+	assert.Equal(t, "2025-01-01T00:00:00Z", data["expiresAt"])
+
+	mockConsensus.AssertExpectations(t)
+}
+
+func TestServerListHandler(t *testing.T) {
+	t.Parallel()
+
+	app, mockConsensus := getTestFiberApp()
+	app.Get("/test", serverListHandler)
+
+	defer app.Shutdown()
+	mockConsensus.On("GetInfo", false).Return(testInfo(), nil).Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	data := readResponse(t, resp)["data"].([]any)
+	require.Len(t, data, 2)
+	assert.Equal(t, "maf-2", data[1].(map[string]any)["id"])
+	assert.Equal(t, "Nonvoter", data[1].(map[string]any)["suffrage"])
+}
+
+func TestServerJoinHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful join", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/cluster/servers", serverJoinHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Join", "maf-2", "10.0.0.2:7081", true, "secret").Return(nil).Once()
+
+		body := `{"serverId": "maf-2", "addr": "10.0.0.2:7081", "nonvoter": true, "token": "secret"}`
+		req, _ := http.NewRequest(http.MethodPost, "/cluster/servers", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "/cluster/servers/maf-2", resp.Header.Get("Location"))
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/cluster/servers", serverJoinHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Join", "maf-2", "10.0.0.2:7081", false, "bad").Return(raft.ErrInvalidJoinToken).Once()
+
+		body := `{"serverId": "maf-2", "addr": "10.0.0.2:7081", "token": "bad"}`
+		req, _ := http.NewRequest(http.MethodPost, "/cluster/servers", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Location"))
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/cluster/servers", serverJoinHandler)
+
+		defer app.Shutdown()
+
+		req, _ := http.NewRequest(http.MethodPost, "/cluster/servers", strings.NewReader(`{"serverId": `))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "validation_failed", readResponse(t, resp)["error"].(map[string]any)["code"])
+
+		mockConsensus.AssertNotCalled(t, "Join", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestServerGetHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("existing server", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Get("/test/:id", serverGetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("GetInfo", false).Return(testInfo(), nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/test/maf-1", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		data := readResponse(t, resp)["data"].(map[string]any)
+		assert.Equal(t, "10.0.0.1:7081", data["address"])
+		assert.Equal(t, true, data["leader"])
+	})
+
+	t.Run("unknown server", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Get("/test/:id", serverGetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("GetInfo", false).Return(testInfo(), nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/test/maf-3", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, "not_found", readResponse(t, resp)["error"].(map[string]any)["code"])
+	})
+
+	t.Run("info error", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Get("/test/:id", serverGetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("GetInfo", false).Return(nil, errors.New("info error")).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/test/maf-1", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+}
+
+func TestServerMembershipHandlers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		handler fiber.Handler
+		coCall  string
+	}{
+		{"forget", http.MethodDelete, "/test/:id", serverForgetHandler, "Forget"},
+		{"promote", http.MethodPost, "/test/:id/promote", serverPromoteHandler, "Promote"},
+		{"demote", http.MethodPost, "/test/:id/demote", serverDemoteHandler, "Demote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			app, mockConsensus := getTestFiberApp()
+			app.Add(tt.method, tt.path, tt.handler)
+
+			defer app.Shutdown()
+			mockConsensus.On(tt.coCall, "maf-2").Return(nil).Once()
+
+			req, _ := http.NewRequest(tt.method, strings.Replace(tt.path, ":id", "maf-2", 1), nil)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			mockConsensus.AssertExpectations(t)
+		})
+
+		t.Run(tt.name+" unknown server", func(t *testing.T) {
+			t.Parallel()
+
+			app, mockConsensus := getTestFiberApp()
+			app.Add(tt.method, tt.path, tt.handler)
+
+			defer app.Shutdown()
+			mockConsensus.On(tt.coCall, "maf-3").Return(raft.ErrUnknownServer).Once()
+
+			req, _ := http.NewRequest(tt.method, strings.Replace(tt.path, ":id", "maf-3", 1), nil)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+//go:generate replacer
+package v1
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+const (
+	eventsKeepAliveInterval = 15 * time.Second
+	// eventsRetryInterval is the reconnection delay suggested to the clients, e.g. after the server shutdown
+	eventsRetryInterval = 3 * time.Second
+	eventsBuffer        = 64
+	lastEventIDHeader   = "Last-Event-ID"
+)
+
+var ErrUnknownEventType = errors.New("unknown event type")
+
+var eventTypes = []raft.EventType{
+	raft.EventLeadership,
+	raft.EventMembership,
+	raft.EventKV,
+	raft.EventFailure,
+	raft.EventFailover,
+}
+
+// eventStream keeps what the stream writer needs, as the fiber context is released once the handler returns
+type eventStream struct {
+	co           Consensus
+	logger       zerolog.Logger
+	done         <-chan struct{}
+	ch           raft.EventsCh
+	types        []raft.EventType
+	writeTimeout time.Duration
+	setDeadline  func(time.Time) error
+}
+
+// Stream cluster events
+//
+// @Summary      Stream cluster events
+// @Description  Stream the cluster events as seen by the server handling the request as server-sent events:
+// @Description  leadership and membership changes, KV changes, detected failures and failover steps.
+// @Description  Reconnect with the Last-Event-ID header, or the last_event_id query for the clients which
+// @Description  can't set headers, to receive the events missed in between, as long as they are buffered.
+// @Description  The IDs are sequential, so the gap means the events were dropped for the slow client
+// @Tags         events
+// @Produce      text/event-stream
+// @Param        Last-Event-ID header int false "ID of the last received event"
+// @Param        last_event_id query int false "ID of the last received event, if the header can't be set"
+// @Param        types query string false "Comma separated event types to receive, all by default"
+// @Success      200 {object} Event "Stream of events, the event type is the SSE event name"
+// @Failure      default {object} Response{error=Error} "Typed error"
+// @Router       /events [get]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func eventsHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		uCtx.logger.Error().Err(err).Msg("Failed to parse last event ID")
+
+		return err
+	}
+
+	types, err := parseEventTypes(c.Query("types"))
+	if err != nil {
+		uCtx.logger.Error().Err(err).Msg("Failed to parse event types")
+
+		return err
+	}
+
+	stream := &eventStream{
+		co:           uCtx.co,
+		logger:       uCtx.logger,
+		done:         uCtx.done,
+		ch:           make(raft.EventsCh, eventsBuffer),
+		types:        types,
+		writeTimeout: c.App().Config().WriteTimeout,
+		setDeadline:  c.Context().Conn().SetWriteDeadline,
+	}
+	replay := uCtx.co.SubscribeOnEvents(stream.ch, lastEventID)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+	// The compression buffers the stream and holds the events back
+	c.Set(fiber.HeaderContentEncoding, "identity")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer stream.co.UnsubscribeFromEvents(stream.ch)
+
+		stream.run(w, replay)
+	})
+
+	return nil
+}
+
+// run writes the events until the client goes away or the server shuts down
+func (s *eventStream) run(w *bufio.Writer, replay []*raft.Event) {
+	s.logger.Debug().Msgf("Streaming events, %d to replay", len(replay))
+
+	// Something must be written to send the response headers, otherwise the client waits for the first event
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", eventsRetryInterval.Milliseconds()); err != nil {
+		return
+	}
+
+	for _, event := range replay {
+		if err := s.write(w, event); err != nil {
+			return
+		}
+	}
+
+	if err := s.flush(w); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(eventsKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event := <-s.ch:
+			if err := s.write(w, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := w.WriteString(": keepalive\n\n"); err != nil {
+				return
+			}
+		case <-s.done:
+			s.logger.Debug().Msg("Server is shutting down, closing events stream")
+
+			return
+		}
+
+		if err := s.flush(w); err != nil {
+			s.logger.Debug().Err(err).Msg("Events stream is closed by the client")
+
+			return
+		}
+	}
+}
+
+func (s *eventStream) write(w *bufio.Writer, event *raft.Event) error {
+	if len(s.types) > 0 && !slices.Contains(s.types, event.Type) {
+		return nil
+	}
+
+	data, err := json.Marshal(&Event{
+		ID:   event.ID,
+		Type: string(event.Type),
+		Time: event.Time,
+		Data: event.Data,
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Msgf("Failed to marshal event %d", event.ID)
+
+		return nil
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+
+	return err
+}
+
+// flush pushes the events to the client. The write timeout applies to every flush, not to the whole stream
+func (s *eventStream) flush(w *bufio.Writer) error {
+	if s.writeTimeout > 0 {
+		if err := s.setDeadline(time.Now().Add(s.writeTimeout)); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+func parseLastEventID(c *fiber.Ctx) (uint64, error) {
+	value := c.Get(lastEventIDHeader)
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+
+	if value == "" {
+		return 0, nil
+	}
+
+	lastEventID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, v1alphaUtils.NewAPIError(
+			v1alphaUtils.CodeValidationFailed,
+			fmt.Errorf("failed to parse last event ID: %w", err),
+			map[string]string{v1alphaUtils.DetailField: "last_event_id"},
+		)
+	}
+
+	return lastEventID, nil
+}
+
+func parseEventTypes(value string) ([]raft.EventType, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	types := make([]raft.EventType, 0)
+
+	for _, name := range strings.Split(value, ",") {
+		eventType := raft.EventType(strings.TrimSpace(name))
+		if !slices.Contains(eventTypes, eventType) {
+			return nil, v1alphaUtils.NewAPIError(
+				v1alphaUtils.CodeValidationFailed,
+				fmt.Errorf("%w: %s", ErrUnknownEventType, eventType),
+				map[string]string{v1alphaUtils.DetailField: "types"},
+			)
+		}
+
+		types = append(types, eventType)
+	}
+
+	return types, nil
+}
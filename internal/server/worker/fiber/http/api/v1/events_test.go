@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/weastur/maf/internal/server/worker/raft"
+)
+
+// getTestEventsApp serves the events of the server which is already shutting down,
+// so the stream ends right after the replay
+func getTestEventsApp() (*fiber.App, *MockConsensus) {
+	app, mockConsensus := getTestFiberApp()
+
+	done := make(chan struct{})
+	close(done)
+
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(context.WithValue(c.UserContext(), shutdownContextKey, (<-chan struct{})(done)))
+
+		return c.Next()
+	})
+	app.Get("/events", eventsHandler)
+
+	return app, mockConsensus
+}
+
+func testEvents() []*raft.Event {
+	eventTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return []*raft.Event{
+		{ID: 2, Type: raft.EventMembership, Time: eventTime, Data: map[string]string{"serverId": "maf-2"}},
+		{ID: 3, Type: raft.EventKV, Time: eventTime, Data: map[string]string{"key": "key"}},
+	}
+}
+
+func TestEventsHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Replay", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestEventsApp()
+
+		defer app.Shutdown()
+		mockConsensus.On("SubscribeOnEvents", mock.Anything, uint64(1)).Return(testEvents()).Once()
+		mockConsensus.On("UnsubscribeFromEvents", mock.Anything).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/events", nil)
+		req.Header.Set(lastEventIDHeader, "1")
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get(fiber.HeaderContentType))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t,
+			"retry: 3000\n\n"+
+				"id: 2\nevent: membership\n"+
+				`data: {"id":2,"type":"membership","time":"2025-01-01T00:00:00Z","data":{"serverId":"maf-2"}}`+"\n\n"+
+				"id: 3\nevent: kv\n"+
+				`data: {"id":3,"type":"kv","time":"2025-01-01T00:00:00Z","data":{"key":"key"}}`+"\n\n",
+			string(body),
+		)
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("TypesFilter", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestEventsApp()
+
+		defer app.Shutdown()
+		mockConsensus.On("SubscribeOnEvents", mock.Anything, uint64(1)).Return(testEvents()).Once()
+		mockConsensus.On("UnsubscribeFromEvents", mock.Anything).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/events?last_event_id=1&types=kv,failure", nil)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.NotContains(t, string(body), "membership")
+		assert.Contains(t, string(body), "id: 3\nevent: kv\n")
+	})
+
+	t.Run("InvalidLastEventID", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestEventsApp()
+
+		defer app.Shutdown()
+
+		req, _ := http.NewRequest(http.MethodGet, "/events", nil)
+		req.Header.Set(lastEventIDHeader, "abc")
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		apiErr := readResponse(t, resp)["error"].(map[string]any)
+		assert.Equal(t, "validation_failed", apiErr["code"])
+		assert.Equal(t, map[string]any{"field": "last_event_id"}, apiErr["details"])
+		mockConsensus.AssertNotCalled(t, "SubscribeOnEvents", mock.Anything, mock.Anything)
+	})
+
+	t.Run("UnknownType", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestEventsApp()
+
+		defer app.Shutdown()
+
+		req, _ := http.NewRequest(http.MethodGet, "/events?types=kv,unknown", nil)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		apiErr := readResponse(t, resp)["error"].(map[string]any)
+		assert.Equal(t, "validation_failed", apiErr["code"])
+		assert.Equal(t, "unknown event type: unknown", apiErr["message"])
+		mockConsensus.AssertNotCalled(t, "SubscribeOnEvents", mock.Anything, mock.Anything)
+	})
+}
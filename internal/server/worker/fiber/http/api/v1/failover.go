@@ -0,0 +1,74 @@
+//go:generate replacer
+package v1
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+var ErrNotImplemented = errors.New("resource is not implemented yet")
+
+// The failover resources are reserved in v1, so the clients can rely on the paths,
+// but the server doesn't track the instances, agents and recoveries yet
+
+// List MySQL instances
+//
+// @Summary      List MySQL instances
+// @Description  Return the MySQL instances managed by the cluster. Not implemented yet, always not_implemented
+// @Tags         instances
+// @Failure      default {object} Response{error=Error} "Typed error, not_implemented"
+// @Router       /instances [get]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func instanceListHandler(_ *fiber.Ctx) error {
+	return notImplemented("instances")
+}
+
+// List agents
+//
+// @Summary      List agents
+// @Description  Return the agents running next to the MySQL instances. Not implemented yet, always not_implemented
+// @Tags         agents
+// @Failure      default {object} Response{error=Error} "Typed error, not_implemented"
+// @Router       /agents [get]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func agentListHandler(_ *fiber.Ctx) error {
+	return notImplemented("agents")
+}
+
+// List recoveries
+//
+// @Summary      List recoveries
+// @Description  Return the failover recoveries made by the cluster. Not implemented yet, always not_implemented
+// @Tags         recoveries
+// @Failure      default {object} Response{error=Error} "Typed error, not_implemented"
+// @Router       /recoveries [get]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func recoveryListHandler(_ *fiber.Ctx) error {
+	return notImplemented("recoveries")
+}
+
+func notImplemented(resource string) error {
+	return v1alphaUtils.NewAPIError(
+		v1alphaUtils.CodeNotImplemented,
+		fmt.Errorf("%w: %s", ErrNotImplemented, resource),
+		nil,
+	)
+}
@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"fmt"
+
+	"github.com/gofiber/contrib/fiberzerolog"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	apiUtils "github.com/weastur/maf/internal/utils/http/api"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+const requestIDLogField = fiberzerolog.FieldRequestID
+
+type unwrappedCtx struct {
+	logger zerolog.Logger
+	co     Consensus
+	api    *APIV1
+	rid    string
+	done   <-chan struct{}
+}
+
+func unpackCtx(c *fiber.Ctx) *unwrappedCtx {
+	logger := zerolog.Ctx(c.UserContext())
+	co, _ := c.UserContext().Value(consensusInstanceContextKey).(Consensus)
+	api, _ := c.UserContext().Value(apiUtils.APIInstanceContextKey).(*APIV1)
+	rid, _ := c.UserContext().Value(apiUtils.RequestIDContextKey).(string)
+	done, _ := c.UserContext().Value(shutdownContextKey).(<-chan struct{})
+
+	return &unwrappedCtx{
+		logger: logger.With().Str(requestIDLogField, rid).Logger(),
+		co:     co,
+		api:    api,
+		rid:    rid,
+		done:   done,
+	}
+}
+
+func parseAndValidate(c *fiber.Ctx, req any) error {
+	uCtx := unpackCtx(c)
+
+	if err := c.BodyParser(req); err != nil {
+		uCtx.logger.Error().Err(err).Msg("Failed to parse request")
+
+		return v1alphaUtils.NewAPIError(
+			v1alphaUtils.CodeValidationFailed, fmt.Errorf("failed to parse request: %w", err), nil,
+		)
+	}
+
+	if err := uCtx.api.validator.Validate(req); err != nil {
+		uCtx.logger.Error().Err(err).Msg("Failed to validate request")
+
+		return fmt.Errorf("failed to validate request: %w", err)
+	}
+
+	return nil
+}
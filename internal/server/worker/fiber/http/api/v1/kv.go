@@ -0,0 +1,106 @@
+//go:generate replacer
+package v1
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	serverAPI "github.com/weastur/maf/internal/server/worker/fiber/http/api"
+	v1Utils "github.com/weastur/maf/internal/utils/http/api/v1"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+var ErrKeyNotFound = errors.New("key is not found")
+
+// Get key from kv store
+//
+// @Summary      Return the value of the key
+// @Description  Return the value of the key from kv store
+// @Tags         kv
+// @Param        key path string true "Key to receive value"
+// @Success      200 {object} Response{data=KV} "Key-value pair"
+// @Failure      default {object} Response{error=Error} "Typed error, not_found if the key is missing"
+// @Router       /kv/{key} [get]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func kvGetHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	key := c.Params("key")
+
+	value, ok := uCtx.co.Get(key)
+	if !ok {
+		return v1alphaUtils.NewAPIError(v1alphaUtils.CodeNotFound, fmt.Errorf("%w: %s", ErrKeyNotFound, key), nil)
+	}
+
+	return v1Utils.WrapResponse(c, fiber.StatusOK, &KV{Key: key, Value: value})
+}
+
+// Set key in kv store
+//
+// @Summary      Set the value of the key
+// @Description  Set the value of the key in kv store. The keys with the maf/ prefix are reserved.
+// @Description  Must be called on the leader
+// @Tags         kv
+// @Param        key path string true "Key to set value for"
+// @Param        request body KVSetRequest true "KV set request"
+// @Success      200 {object} Response "Empty response"
+// @Failure      default {object} Response{error=Error} "Typed error, forbidden if the key is reserved"
+// @Router       /kv/{key} [put]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func kvSetHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	key := c.Params("key")
+	serverAPI.SetAuditTarget(c, key)
+
+	setReq := new(KVSetRequest)
+	if err := parseAndValidate(c, setReq); err != nil {
+		return err
+	}
+
+	if err := uCtx.co.Set(key, setReq.Value); err != nil {
+		return err
+	}
+
+	return v1Utils.WrapResponse(c, fiber.StatusOK, nil)
+}
+
+// Delete key from kv store
+//
+// @Summary      Delete the key
+// @Description  Delete the key from kv store. Deleting a missing key is a no-op, the maf/ prefix is reserved.
+// @Description  Must be called on the leader
+// @Tags         kv
+// @Param        key path string true "Key to delete"
+// @Success      200 {object} Response "Empty response"
+// @Failure      default {object} Response{error=Error} "Typed error, forbidden if the key is reserved"
+// @Router       /kv/{key} [delete]
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func kvDeleteHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	key := c.Params("key")
+	serverAPI.SetAuditTarget(c, key)
+
+	if err := uCtx.co.Delete(key); err != nil {
+		return err
+	}
+
+	return v1Utils.WrapResponse(c, fiber.StatusOK, nil)
+}
@@ -0,0 +1,179 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weastur/maf/internal/server/worker/raft"
+)
+
+func TestKVGetHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("existing key", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Get("/test/:key", kvGetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Get", "key").Return("value", true).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/test/key", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, map[string]any{"key": "key", "value": "value"}, readResponse(t, resp)["data"])
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Get("/test/:key", kvGetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Get", "key").Return("", false).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/test/key", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+		apiErr := readResponse(t, resp)["error"].(map[string]any)
+		assert.Equal(t, "not_found", apiErr["code"])
+		assert.Equal(t, "key is not found: key", apiErr["message"])
+	})
+}
+
+func TestKVSetHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful set", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Put("/test/:key", kvSetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Set", "key", "value").Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPut, "/test/key", strings.NewReader(`{"value": "value"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("error on set", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Put("/test/:key", kvSetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Set", "key", "value").Return(errors.New("set error")).Once()
+
+		req, _ := http.NewRequest(http.MethodPut, "/test/key", strings.NewReader(`{"value": "value"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, "set error", readResponse(t, resp)["error"].(map[string]any)["message"])
+	})
+
+	t.Run("reserved key", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Put("/test/:key", kvSetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Set", "maf", "value").Return(fmt.Errorf("%w: maf", raft.ErrReservedKey)).Once()
+
+		req, _ := http.NewRequest(http.MethodPut, "/test/maf", strings.NewReader(`{"value": "value"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+		apiErr := readResponse(t, resp)["error"].(map[string]any)
+		assert.Equal(t, "forbidden", apiErr["code"])
+		assert.Equal(t, map[string]any{"field": "key"}, apiErr["details"])
+	})
+
+	t.Run("follower", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Put("/test/:key", kvSetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Set", "key", "value").Return(raft.ErrNotALeader).Once()
+		mockConsensus.On("Get", "leaderAPIAddr").Return("http://10.0.0.1:7080", true).Once()
+
+		req, _ := http.NewRequest(http.MethodPut, "/test/key", strings.NewReader(`{"value": "value"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusMisdirectedRequest, resp.StatusCode)
+
+		apiErr := readResponse(t, resp)["error"].(map[string]any)
+		assert.Equal(t, "not_leader", apiErr["code"])
+		assert.Equal(t, map[string]any{"leader": "http://10.0.0.1:7080"}, apiErr["details"])
+	})
+}
+
+func TestKVDeleteHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful delete", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Delete("/test/:key", kvDeleteHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Delete", "key").Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodDelete, "/test/key", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("follower", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Delete("/test/:key", kvDeleteHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Delete", "key").Return(raft.ErrNotALeader).Once()
+		mockConsensus.On("Get", "leaderAPIAddr").Return("", false).Once()
+
+		req, _ := http.NewRequest(http.MethodDelete, "/test/key", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusMisdirectedRequest, resp.StatusCode)
+		assert.Equal(t, "not_leader", readResponse(t, resp)["error"].(map[string]any)["code"])
+	})
+}
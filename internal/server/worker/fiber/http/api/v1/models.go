@@ -0,0 +1,133 @@
+package v1
+
+import "time"
+
+// Join request
+// @Description Request to join the server to the cluster
+type ServerJoinRequest struct {
+	ServerID string `example:"maf-2"         json:"serverId" validate:"required"`
+	Addr     string `example:"10.1.2.3:7081" json:"addr"     validate:"required,tcp_addr"`
+	// Join as non-voter, which replicates the log but doesn't take part in elections
+	Nonvoter bool `example:"false" json:"nonvoter"`
+	// Join token issued by the cluster. Not required to rejoin with the same ID and address, the suffrage is kept
+	Token string `example:"6b1f...c9a2" json:"token"`
+} // @Name ServerJoinRequest
+
+// Token create request
+// @Description Request to issue a join token
+type TokenCreateRequest struct {
+	// Lifetime of the token in seconds, 0 means the default of 1 hour. Must not exceed 24 hours
+	TTL int64 `example:"3600" json:"ttl" validate:"gte=0"`
+} // @Name TokenCreateRequest
+
+// Token
+// @Description Issued join token. It's shown only once and can't be retrieved later
+type Token struct {
+	Token     string    `example:"6b1f...c9a2"          json:"token"`
+	ExpiresAt time.Time `example:"2025-01-01T00:00:00Z" json:"expiresAt"`
+} // @Name Token
+
+// Leadership transfer request
+// @Description Request to transfer the leadership to the target server
+type LeadershipTransferRequest struct {
+	// ID of the voter to transfer the leadership to. Empty means the most up-to-date voter
+	ServerID string `example:"maf-2" json:"serverId"`
+} // @Name LeadershipTransferRequest
+
+// Server
+// @Description Server in the raft cluster
+type Server struct {
+	ID      string `example:"maf-1"          json:"id"`
+	Address string `example:"127.0.0.1:7081" json:"address"`
+	// Suffrage of the server in terms of the consensus: Voter, Nonvoter, Staging
+	Suffrage string `enums:"Voter,Nonvoter,Staging" example:"Voter" json:"suffrage"`
+	Leader   bool   `example:"true"                 json:"leader"`
+} // @Name Server
+
+// Cluster
+// @Description Raft cluster as seen by the server handling the request
+type Cluster struct {
+	// State of the server in terms of the consensus: Leader, Follower, Candidate, etc.
+	State string `enums:"Follower,Candidate,Leader,Shutdown,Unknown" example:"Leader" json:"state"`
+	// Address the raft transport is bound to
+	Addr string `example:"0.0.0.0:7081" json:"addr"`
+	// Address advertised to other servers
+	Advertise string `example:"10.1.2.3:7081" json:"advertise"`
+	ID        string `example:"maf-1"         json:"id"`
+	// List of servers in the cluster
+	Servers []Server `json:"servers"`
+	// Extended stats of the raft cluster
+	Stats map[string]string `json:"stats,omitempty"`
+} // @Name Cluster
+
+// KV pair
+// @Description Key-value pair. Missing keys are reported with not_found error
+type KV struct {
+	Key   string `example:"key"   json:"key"`
+	Value string `example:"value" json:"value"`
+} // @Name KV
+
+// KV set request
+// @Description Request to set the value of the key
+type KVSetRequest struct {
+	Value string `example:"value" json:"value"`
+} // @Name KVSetRequest
+
+// Event
+// @Description Cluster event as seen by the server handling the request
+type Event struct {
+	// Sequential ID of the event, resets on the server restart
+	ID   uint64    `example:"42" json:"id"`
+	Type string    `enums:"leadership,membership,kv,failure,failover" example:"membership" json:"type"`
+	Time time.Time `example:"2025-01-01T00:00:00Z" json:"time"`
+	// Event details, e.g. the server ID, the action or the key
+	Data map[string]string `json:"data"`
+} // @Name Event
+
+// Audit record
+// @Description Record of the mutating API call. Each record contains the hash of the previous one,
+// @Description so the missing or modified records are detectable
+type AuditRecord struct {
+	Seq  uint64    `example:"42"                   json:"seq"`
+	Time time.Time `example:"2025-01-01T00:00:00Z" json:"time"`
+	// Name of the token or the client certificate the call was authenticated with
+	Actor     string `example:"admin"                                json:"actor"`
+	Role      string `enums:"read,operator,admin"                    example:"admin"   json:"role"`
+	Action    string `example:"raft.forget"                          json:"action"`
+	Target    string `example:"maf-2"                                json:"target"`
+	RequestID string `example:"0b3f9a4e-4c5d-4f5e-9a3b-2f1e0d9c8b7a" json:"requestId"`
+	Outcome   string `enums:"success,failure"                        example:"success" json:"outcome"`
+	// Error message if the call failed
+	Error string `example:"" json:"error"`
+	// Server which forwarded the record to the leader, empty if the leader served the call
+	Forwarder string `example:"maf-2"       json:"forwarder"`
+	PrevHash  string `example:"9f86...0f00" json:"prevHash"`
+	Hash      string `example:"6030...1a4b" json:"hash"`
+} // @Name AuditRecord
+
+// Audit log
+// @Description Audit records with the result of the chain verification
+type AuditLog struct {
+	Records []AuditRecord `json:"records"`
+	// False if any record is missing or modified
+	Intact bool `example:"true" json:"intact"`
+	// Sequence number of the first missing or modified record
+	BrokenAt uint64 `example:"0" json:"brokenAt"`
+	// Pass it as 'after' to get the next page, zero if it's the last one
+	Next uint64 `example:"100" json:"next"`
+} // @Name AuditLog
+
+// Audit append request
+// @Description Record of the call served by a follower, forwarded to the leader
+type AuditAppendRequest struct {
+	Time time.Time `example:"2025-01-01T00:00:00Z" json:"time"`
+	// Name of the token or the client certificate the call was authenticated with
+	Actor     string `example:"admin"                                json:"actor"     validate:"required"`
+	Role      string `enums:"read,operator,admin"                    example:"admin"  json:"role"`
+	Action    string `example:"raft.leave"                           json:"action"    validate:"required"`
+	Target    string `example:"maf-2"                                json:"target"`
+	RequestID string `example:"0b3f9a4e-4c5d-4f5e-9a3b-2f1e0d9c8b7a" json:"requestId"`
+	// Outcome of the call, the error message is set if it failed
+	Outcome string `enums:"success,failure" example:"success" json:"outcome" validate:"required,oneof=success failure"`
+	Error   string `example:""              json:"error"`
+} // @Name AuditAppendRequest
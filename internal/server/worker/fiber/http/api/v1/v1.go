@@ -0,0 +1,169 @@
+package v1
+
+import (
+	"context"
+	"embed"
+	"sync"
+	"time"
+
+	"github.com/gofiber/contrib/swagger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	serverAPI "github.com/weastur/maf/internal/server/worker/fiber/http/api"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	httpUtils "github.com/weastur/maf/internal/utils/http"
+	apiUtils "github.com/weastur/maf/internal/utils/http/api"
+	v1Utils "github.com/weastur/maf/internal/utils/http/api/v1"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+const (
+	Version                     = "v1"
+	consensusInstanceContextKey = apiUtils.UserContextKey("consensusInstance")
+	shutdownContextKey          = apiUtils.UserContextKey("shutdown")
+)
+
+type Consensus interface {
+	IsLeader() bool
+	Join(serverID, addr string, nonvoter bool, token string) error
+	CreateJoinToken(ttl time.Duration) (*raft.JoinToken, error)
+	Forget(serverID string) error
+	Promote(serverID string) error
+	Demote(serverID string) error
+	Leave() error
+	TransferLeadership(serverID string) error
+	GetInfo(verbose bool) (*raft.Info, error)
+	Get(key string) (string, bool)
+	Set(key, value string) error
+	Delete(key string) error
+	serverAPI.Auditor
+	SubscribeOnEvents(ch raft.EventsCh, lastEventID uint64) []*raft.Event
+	UnsubscribeFromEvents(ch raft.EventsCh)
+}
+
+type Validator interface {
+	Validate(data any) error
+}
+
+type APIV1 struct {
+	prefix    string
+	version   string
+	validator Validator
+}
+
+//go:embed swagger.json
+var swaggerJSON embed.FS
+
+var (
+	instance *APIV1
+	once     sync.Once
+)
+
+func Get() *APIV1 {
+	once.Do(func() {
+		instance = &APIV1{
+			version:   Version,
+			prefix:    "/" + Version,
+			validator: v1alphaUtils.NewXValidator(),
+		}
+	})
+
+	return instance
+}
+
+// @title MySQL auto failover server API
+// @version v1
+// @description Stable server API for MySQL auto failover. Generally must be called by CLI.
+// @description The outcome is reported by the HTTP status, the errors carry the machine-readable code
+// @contact.name Pavel Sapezhka
+// @contact.url weastur.com
+// @contact.email me@weastur.com
+// @license.name Mozilla Public License Version 2.0
+// @license.url https://www.mozilla.org/en-US/MPL/2.0/
+// @host 127.0.0.1:7080
+// @tag.name aux
+// @tag.description Auxiliary endpoints
+// @tag.name cluster
+// @tag.description Raft cluster and its servers
+// @tag.name kv
+// @tag.description Replicated key-value store
+// @tag.name audit
+// @tag.description Audit log of the mutating calls
+// @tag.name events
+// @tag.description Stream of the cluster events
+// @tag.name instances
+// @tag.description MySQL instances managed by the cluster, not implemented yet
+// @tag.name agents
+// @tag.description Agents running next to the MySQL instances, not implemented yet
+// @tag.name recoveries
+// @tag.description Failover recoveries, not implemented yet
+// @BasePath /api/v1
+// @accept json
+// @produce json
+// @schemes http https
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-Auth-Token
+// @description API token bound to a role: read, operator or admin. Optional for mapped client certs
+// @externalDocs.description Find out more about MAF on GitHub
+// @externalDocs.url https://github.com/weastur/maf/wiki
+func (api *APIV1) Init(
+	topRouter fiber.Router,
+	logger zerolog.Logger,
+	co Consensus,
+	auth *v1alphaUtils.Authenticator,
+	done <-chan struct{},
+) {
+	router := httpUtils.APIVersionGroup(topRouter, api.version)
+
+	swaggerContent, _ := swaggerJSON.ReadFile("swagger.json")
+	router.Use(swagger.New(swagger.Config{
+		Title:       "MySQL auto failover server API, version" + api.version,
+		BasePath:    httpUtils.APIPrefix + api.prefix,
+		FileContent: swaggerContent,
+		Path:        "docs",
+		CacheAge:    0,
+	}))
+
+	router.Use(func(c *fiber.Ctx) error {
+		ctx := context.WithValue(context.Background(), apiUtils.APIInstanceContextKey, api)
+		ctx = context.WithValue(ctx, consensusInstanceContextKey, co)
+		ctx = context.WithValue(ctx, shutdownContextKey, done)
+		ctx = logger.WithContext(ctx)
+		c.SetUserContext(ctx)
+
+		return c.Next()
+	})
+	router.Use(v1alphaUtils.AuthMiddleware(auth))
+
+	router.Get("/version", v1Utils.VersionHandler)
+
+	read := v1alphaUtils.RequireRole(v1alphaUtils.RoleRead)
+	operator := v1alphaUtils.RequireRole(v1alphaUtils.RoleOperator)
+	admin := v1alphaUtils.RequireRole(v1alphaUtils.RoleAdmin)
+	peer := v1alphaUtils.RequirePeer()
+
+	router.Get("/cluster", read, clusterGetHandler)
+	router.Post("/cluster/leave", admin, audit("raft.leave"), clusterLeaveHandler)
+	router.Post("/cluster/leadership-transfer", operator, audit("raft.leadership-transfer"), leadershipTransferHandler)
+	router.Post("/cluster/tokens", admin, audit("raft.token.create"), tokenCreateHandler)
+	router.Get("/cluster/servers", read, serverListHandler)
+	router.Post("/cluster/servers", operator, audit("raft.join"), serverJoinHandler)
+	router.Get("/cluster/servers/:id", read, serverGetHandler)
+	router.Delete("/cluster/servers/:id", admin, audit("raft.forget"), serverForgetHandler)
+	router.Post("/cluster/servers/:id/promote", operator, audit("raft.promote"), serverPromoteHandler)
+	router.Post("/cluster/servers/:id/demote", operator, audit("raft.demote"), serverDemoteHandler)
+	router.Get("/kv/:key", read, kvGetHandler)
+	router.Put("/kv/:key", operator, audit("kv.set"), kvSetHandler)
+	router.Delete("/kv/:key", operator, audit("kv.delete"), kvDeleteHandler)
+	router.Get("/audit", read, auditListHandler)
+	router.Post("/audit", peer, auditAppendHandler)
+	router.Get("/events", read, eventsHandler)
+	router.Get("/instances", read, instanceListHandler)
+	router.Get("/agents", read, agentListHandler)
+	router.Get("/recoveries", read, recoveryListHandler)
+}
+
+func (api *APIV1) ErrorHandler(c *fiber.Ctx, err error) error {
+	return v1Utils.WrapError(c, serverAPI.ClassifyError(err, unpackCtx(c).co))
+}
@@ -0,0 +1,201 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	httpUtils "github.com/weastur/maf/internal/utils/http"
+	apiUtils "github.com/weastur/maf/internal/utils/http/api"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+const requestID = "test-request-id"
+
+func getTestFiberApp() (*fiber.App, *MockConsensus) {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: httpUtils.ErrorHandler,
+	})
+	mockConsensus := new(MockConsensus)
+	mockValidator := new(MockValidator)
+	mockAPI := &APIV1{
+		version:   Version,
+		prefix:    "/" + Version,
+		validator: mockValidator,
+	}
+
+	mockValidator.On("Validate", mock.Anything).Return(nil).Once()
+	app.Use(func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		ctx = context.WithValue(ctx, consensusInstanceContextKey, mockConsensus)
+		ctx = context.WithValue(ctx, apiUtils.APIInstanceContextKey, mockAPI)
+		ctx = context.WithValue(ctx, apiUtils.RequestIDContextKey, requestID)
+		c.SetUserContext(ctx)
+
+		return c.Next()
+	})
+
+	return app, mockConsensus
+}
+
+func readResponse(t *testing.T, resp *http.Response) map[string]any {
+	t.Helper()
+
+	var response map[string]any
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(body, &response))
+
+	return response
+}
+
+func TestAPIV1_Init(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New(fiber.Config{ErrorHandler: httpUtils.ErrorHandler})
+	logger := zerolog.Nop()
+	mockConsensus := new(MockConsensus)
+
+	api := &APIV1{
+		version:   Version,
+		prefix:    "/" + Version,
+		validator: new(MockValidator),
+	}
+	auth, err := v1alphaUtils.NewAuthenticator(&v1alphaUtils.AuthConfig{Tokens: []v1alphaUtils.TokenConfig{
+		{Name: "reader", Role: "read", Token: "reader-token"},
+		{Name: "operator", Role: "operator", Token: "operator-token"},
+		{Name: "admin", Role: "admin", Token: "admin-token"},
+	}})
+	require.NoError(t, err)
+
+	api.Init(app.Group("/api"), logger, mockConsensus, auth, make(chan struct{}))
+
+	t.Run("Swagger Docs Endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := http.NewRequest(fiber.MethodGet, "/api/v1/docs", nil)
+		resp, _ := app.Test(req, -1)
+
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(body), "MySQL auto failover server API")
+	})
+
+	t.Run("Version Endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := http.NewRequest(fiber.MethodGet, "/api/v1/version", nil)
+		resp, _ := app.Test(req, -1)
+
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "v1", resp.Header.Get("X-API-Version"))
+		assert.Empty(t, resp.Header.Get("Deprecation"))
+		assert.Contains(t, readResponse(t, resp)["data"], "version")
+	})
+
+	t.Run("Unauthorized Access", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := http.NewRequest(fiber.MethodGet, "/api/v1/cluster", nil)
+		resp, _ := app.Test(req, -1)
+
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+		response := readResponse(t, resp)
+		assert.NotContains(t, response, "status")
+		assert.Equal(t, "unauthorized", response["error"].(map[string]any)["code"])
+	})
+
+	t.Run("Insufficient Role", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := http.NewRequest(fiber.MethodDelete, "/api/v1/cluster/servers/maf-2", nil)
+		req.Header.Set("X-Auth-Token", "reader-token")
+		resp, _ := app.Test(req, -1)
+
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, "forbidden", readResponse(t, resp)["error"].(map[string]any)["code"])
+		mockConsensus.AssertNotCalled(t, "Forget", mock.Anything)
+	})
+
+	t.Run("Leave Requires Admin", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := http.NewRequest(fiber.MethodPost, "/api/v1/cluster/leave", nil)
+		req.Header.Set("X-Auth-Token", "operator-token")
+		resp, _ := app.Test(req, -1)
+
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+		mockConsensus.AssertNotCalled(t, "Leave")
+	})
+	t.Run("Failover Resources Not Implemented", func(t *testing.T) {
+		t.Parallel()
+
+		for _, path := range []string{"/api/v1/instances", "/api/v1/agents", "/api/v1/recoveries"} {
+			req, _ := http.NewRequest(fiber.MethodGet, path, nil)
+			req.Header.Set("X-Auth-Token", "reader-token")
+			resp, _ := app.Test(req, -1)
+
+			assert.Equal(t, fiber.StatusNotImplemented, resp.StatusCode, path)
+			assert.Equal(t, "not_implemented", readResponse(t, resp)["error"].(map[string]any)["code"], path)
+		}
+	})
+
+	t.Run("Audit Append Requires Peer", func(t *testing.T) {
+		t.Parallel()
+
+		body := `{"actor": "admin", "action": "raft.leave", "outcome": "success"}`
+		req, _ := http.NewRequest(fiber.MethodPost, "/api/v1/audit", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Auth-Token", "admin-token")
+		resp, _ := app.Test(req, -1)
+
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, "forbidden", readResponse(t, resp)["error"].(map[string]any)["code"])
+		mockConsensus.AssertNotCalled(t, "AppendAudit", mock.Anything)
+	})
+}
+
+func TestAPIV1_Get(t *testing.T) {
+	t.Parallel()
+
+	instance := Get()
+
+	assert.Same(t, instance, Get(), "Get() should return the same instance")
+	assert.Equal(t, "v1", instance.version)
+	assert.Equal(t, "/v1", instance.prefix)
+}
+
+func TestAPIV1_ErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	app, mockConsensus := getTestFiberApp()
+	app.Get("/error", func(_ *fiber.Ctx) error {
+		return raft.ErrNotALeader
+	})
+
+	defer app.Shutdown()
+	mockConsensus.On("Get", "leaderAPIAddr").Return("http://10.0.0.1:7080", true).Once()
+
+	req, _ := http.NewRequest(fiber.MethodGet, "/error", nil)
+	resp, _ := app.Test(req, -1)
+
+	assert.Equal(t, fiber.StatusMisdirectedRequest, resp.StatusCode)
+	assert.Equal(t, map[string]any{
+		"error": map[string]any{
+			"code":    "not_leader",
+			"message": raft.ErrNotALeader.Error(),
+			"details": map[string]any{"leader": "http://10.0.0.1:7080"},
+		},
+	}, readResponse(t, resp))
+}
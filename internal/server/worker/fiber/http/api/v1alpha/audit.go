@@ -0,0 +1,151 @@
+//go:generate replacer
+package v1alpha
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jinzhu/copier"
+	serverAPI "github.com/weastur/maf/internal/server/worker/fiber/http/api"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+// audit writes the audit record of the call after the handler is done, whatever the outcome is.
+// If the record can't be written, the successful call is still reported as such, since its change
+// is already applied, but it's marked with the X-Audit-Failed header, logged and counted
+func audit(action string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		uCtx := unpackCtx(c)
+
+		if auditErr := uCtx.co.Audit(serverAPI.NewAuditEntry(c, action, uCtx.rid, err)); auditErr != nil {
+			serverAPI.ReportAuditFailure(&uCtx.logger, action, auditErr)
+
+			if err == nil {
+				c.Set(serverAPI.AuditFailedHeader, "true")
+			}
+		}
+
+		return err
+	}
+}
+
+// List audit records
+//
+// @Summary      List audit records
+// @Description  Return the page of the audit records of the mutating calls made since the given time.
+// @Description  The page is verified against the hash chain, see 'intact' and 'brokenAt'.
+// @Description  Pass 'next' as 'after' to get the next page
+// @Tags         audit
+// @Success      200 {object} Response{data=AuditListResponse} "Audit records"
+// @Router       /audit [get]
+// @Deprecated
+// @Param        since query string false "Return records made since this time, RFC 3339"
+// @Param        after query int false "Return records after this sequence number"
+// @Param        limit query int false "Maximum number of records, 100 by default, 1000 at most"
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1alpha"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func auditListHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	var since time.Time
+
+	if value := c.Query("since"); value != "" {
+		var err error
+
+		since, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			uCtx.logger.Error().Err(err).Msg("Failed to parse since")
+
+			return fmt.Errorf("failed to parse since: %w", err)
+		}
+	}
+
+	after, err := parseAuditQueryParam(c, "after", 64)
+	if err != nil {
+		return err
+	}
+
+	limit, err := parseAuditQueryParam(c, "limit", 31)
+	if err != nil {
+		return err
+	}
+
+	auditLog, err := uCtx.co.AuditLog(&raft.AuditQuery{Since: since, After: after, Limit: int(limit)})
+	if err != nil {
+		return err
+	}
+
+	data := &AuditListResponse{}
+	if err := copier.Copy(data, auditLog); err != nil {
+		return err
+	}
+
+	return v1alphaUtils.WrapResponse(c, v1alphaUtils.StatusSuccess, data, nil)
+}
+
+func parseAuditQueryParam(c *fiber.Ctx, name string, bitSize int) (uint64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, bitSize)
+	if err != nil {
+		unpackCtx(c).logger.Error().Err(err).Msgf("Failed to parse %s", name)
+
+		return 0, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	return parsed, nil
+}
+
+// Append audit record
+//
+// @Summary      Append audit record
+// @Description  Append the record of the call served by a follower. Only the leader accepts it,
+// @Description  the servers forward the records of the calls they serve here. Only the servers of the cluster
+// @Description  are allowed, the authenticated caller is recorded as the forwarder
+// @Tags         audit
+// @Param        request body AuditAppendRequest true "Audit append request"
+// @Success      200 {object} Response "Response with error details or success code"
+// @Router       /audit [post]
+// @Deprecated
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1alpha"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func auditAppendHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	appendReq := new(AuditAppendRequest)
+	if err := parseAndValidate(c, appendReq); err != nil {
+		return err
+	}
+
+	entry := &raft.AuditEntry{}
+	if err := copier.Copy(entry, appendReq); err != nil {
+		return err
+	}
+
+	// the peer is trusted to tell the actor, but not to hide itself
+	if identity, ok := v1alphaUtils.GetIdentity(c); ok {
+		entry.Forwarder = identity.Name
+	}
+
+	if err := uCtx.co.AppendAudit(entry); err != nil {
+		return err
+	}
+
+	return v1alphaUtils.WrapResponse(c, v1alphaUtils.StatusSuccess, nil, nil)
+}
@@ -0,0 +1,242 @@
+package v1alpha
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	serverAPI "github.com/weastur/maf/internal/server/worker/fiber/http/api"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+func TestAuditMiddleware(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", audit("raft.forget"), raftForgetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Forget", "server-1").Return(nil).Once()
+		mockConsensus.On("Audit", mock.MatchedBy(func(entry *raft.AuditEntry) bool {
+			return entry.Action == "raft.forget" &&
+				entry.Target == "server-1" &&
+				entry.Actor == serverAPI.AnonymousActor &&
+				entry.RequestID == requestID &&
+				entry.Outcome == raft.AuditOutcomeSuccess &&
+				entry.Error == ""
+		})).Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"serverId": "server-1"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("failure is audited and returned", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", audit("raft.forget"), raftForgetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Forget", "server-1").Return(errors.New("forget error")).Once()
+		mockConsensus.On("Audit", mock.MatchedBy(func(entry *raft.AuditEntry) bool {
+			return entry.Outcome == raft.AuditOutcomeFailure && entry.Error == "forget error"
+		})).Return(raft.ErrNotALeader).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"serverId": "server-1"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(body), "forget error")
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("audit failure doesn't fail the applied call", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", audit("raft.forget"), raftForgetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Forget", "server-1").Return(nil).Once()
+		mockConsensus.On("Audit", mock.Anything).Return(raft.ErrNoLeader).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"serverId": "server-1"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "true", resp.Header.Get(serverAPI.AuditFailedHeader))
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(body), `"status":"success"`)
+
+		mockConsensus.AssertExpectations(t)
+	})
+}
+
+func TestAuditListHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful list", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Get("/test", auditListHandler)
+
+		defer app.Shutdown()
+
+		since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		auditLog := &raft.AuditLog{
+			Records: []raft.AuditRecord{{
+				Seq: 1,
+				AuditEntry: raft.AuditEntry{
+					Time:    since,
+					Actor:   "admin",
+					Role:    "admin",
+					Action:  "raft.forget",
+					Target:  "server-1",
+					Outcome: raft.AuditOutcomeSuccess,
+				},
+				Hash: "abc",
+			}},
+			Intact: true,
+		}
+		mockConsensus.On("AuditLog", &raft.AuditQuery{Since: since, After: 10, Limit: 50}).Return(auditLog, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/test?since=2025-01-01T00:00:00Z&after=10&limit=50", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		var response struct {
+			Data AuditListResponse `json:"data"`
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(body, &response))
+		assert.True(t, response.Data.Intact)
+		require.Len(t, response.Data.Records, 1)
+		assert.Equal(t, "raft.forget", response.Data.Records[0].Action)
+		assert.Equal(t, "admin", response.Data.Records[0].Actor)
+		assert.Equal(t, "abc", response.Data.Records[0].Hash)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("invalid since", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Get("/test", auditListHandler)
+
+		defer app.Shutdown()
+
+		req, _ := http.NewRequest(http.MethodGet, "/test?since=yesterday", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(body), "failed to parse since")
+
+		mockConsensus.AssertNotCalled(t, "AuditLog", mock.Anything)
+	})
+}
+
+func TestAuditAppendHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful append", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", auditAppendHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("AppendAudit", mock.MatchedBy(func(entry *raft.AuditEntry) bool {
+			return entry.Actor == "admin" && entry.Action == "raft.leave" && entry.Outcome == raft.AuditOutcomeSuccess
+		})).Return(nil).Once()
+
+		body := `{"actor": "admin", "role": "admin", "action": "raft.leave", "outcome": "success"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("forwarder is stamped", func(t *testing.T) {
+		t.Parallel()
+
+		auth, err := v1alphaUtils.NewAuthenticator(&v1alphaUtils.AuthConfig{
+			Tokens:    []v1alphaUtils.TokenConfig{{Name: "maf-2", Role: "admin", Token: "server-token"}},
+			PeerToken: "server-token",
+		})
+		require.NoError(t, err)
+
+		app, mockConsensus := getTestFiberApp()
+		app.Use(v1alphaUtils.AuthMiddleware(auth))
+		app.Post("/test", v1alphaUtils.RequirePeer(), auditAppendHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("AppendAudit", mock.MatchedBy(func(entry *raft.AuditEntry) bool {
+			return entry.Actor == "admin" && entry.Forwarder == "maf-2"
+		})).Return(nil).Once()
+
+		body := `{"actor": "admin", "role": "admin", "action": "raft.leave", "outcome": "success"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Auth-Token", "server-token")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", auditAppendHandler)
+
+		defer app.Shutdown()
+
+		body := `{"actor": 123, "action": "raft.leave", "outcome": "success"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		mockConsensus.AssertNotCalled(t, "AppendAudit", mock.Anything)
+	})
+}
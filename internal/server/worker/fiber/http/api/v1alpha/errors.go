@@ -0,0 +1,12 @@
+package v1alpha
+
+import (
+	"github.com/gofiber/fiber/v2"
+	serverAPI "github.com/weastur/maf/internal/server/worker/fiber/http/api"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+// classifyError maps the consensus errors to the API error codes, the rest is classified by the common rules
+func classifyError(c *fiber.Ctx, err error) *v1alphaUtils.APIError {
+	return serverAPI.ClassifyError(err, unpackCtx(c).co)
+}
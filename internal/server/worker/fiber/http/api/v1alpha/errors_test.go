@@ -0,0 +1,85 @@
+package v1alpha
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	hraft "github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weastur/maf/internal/server/worker/raft"
+)
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		details map[string]any
+	}{
+		{"NotALeader", raft.ErrNotALeader, fiber.StatusMisdirectedRequest, "not_leader", map[string]any{
+			"leader": "http://10.0.0.1:7080",
+		}},
+		{"LeadershipLost", hraft.ErrLeadershipLost, fiber.StatusMisdirectedRequest, "not_leader", map[string]any{
+			"leader": "http://10.0.0.1:7080",
+		}},
+		{"InvalidJoinToken", raft.ErrInvalidJoinToken, fiber.StatusUnauthorized, "unauthorized", nil},
+		{"UnknownServer", fmt.Errorf("%w: maf-2", raft.ErrUnknownServer), fiber.StatusNotFound, "not_found", nil},
+		{"WildcardAddr", raft.ErrWildcardAddr, fiber.StatusBadRequest, "validation_failed", map[string]any{
+			"field": "addr",
+		}},
+		{"JoinTokenTTL", raft.ErrJoinTokenTTL, fiber.StatusBadRequest, "validation_failed", map[string]any{
+			"field": "ttl",
+		}},
+		{"ReservedKey", fmt.Errorf("%w: maf/x", raft.ErrReservedKey), fiber.StatusForbidden, "forbidden", map[string]any{
+			"field": "key",
+		}},
+		{"LeaveForbidden", raft.ErrLeaveForbidden, fiber.StatusForbidden, "forbidden", nil},
+		{"NotAVoter", raft.ErrNotAVoter, fiber.StatusConflict, "conflict", nil},
+		{"LeaveQuorum", raft.ErrLeaveQuorum, fiber.StatusConflict, "conflict", nil},
+		{"EnqueueTimeout", hraft.ErrEnqueueTimeout, fiber.StatusGatewayTimeout, "timeout", nil},
+		{"Unknown", errors.New("some error"), fiber.StatusInternalServerError, "internal", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			app, mockConsensus := getTestFiberApp()
+			app.Get("/test", func(_ *fiber.Ctx) error {
+				return tt.err
+			})
+
+			defer app.Shutdown()
+			mockConsensus.On("Get", LeaderAPIAddrKey).Return("http://10.0.0.1:7080", true).Maybe()
+
+			req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var response map[string]any
+
+			body, _ := io.ReadAll(resp.Body)
+			require.NoError(t, json.Unmarshal(body, &response))
+			assert.Equal(t, "error", response["status"])
+			assert.Equal(t, tt.code, response["code"])
+			assert.Equal(t, tt.err.Error(), response["error"])
+
+			if tt.details == nil {
+				assert.NotContains(t, response, "details")
+			} else {
+				assert.Equal(t, tt.details, response["details"])
+			}
+		})
+	}
+}
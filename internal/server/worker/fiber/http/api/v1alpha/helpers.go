@@ -7,6 +7,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog"
 	apiUtils "github.com/weastur/maf/internal/utils/http/api"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
 )
 
 const requestIDLogField = fiberzerolog.FieldRequestID
@@ -38,7 +39,9 @@ func parseAndValidate(c *fiber.Ctx, req any) error {
 	if err := c.BodyParser(req); err != nil {
 		uCtx.logger.Error().Err(err).Msg("Failed to parse request")
 
-		return fmt.Errorf("failed to parse request: %w", err)
+		return v1alphaUtils.NewAPIError(
+			v1alphaUtils.CodeValidationFailed, fmt.Errorf("failed to parse request: %w", err), nil,
+		)
 	}
 
 	if err := uCtx.api.validator.Validate(req); err != nil {
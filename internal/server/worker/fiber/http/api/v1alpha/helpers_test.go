@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog"
@@ -26,8 +27,42 @@ func (m *MockConsensus) IsLeader() bool {
 	return args.Bool(0)
 }
 
-func (m *MockConsensus) Join(serverID, addr string) error {
-	args := m.Called(serverID, addr)
+func (m *MockConsensus) Join(serverID, addr string, nonvoter bool, token string) error {
+	args := m.Called(serverID, addr, nonvoter, token)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) CreateJoinToken(ttl time.Duration) (*raft.JoinToken, error) {
+	args := m.Called(ttl)
+
+	if token, ok := args.Get(0).(*raft.JoinToken); ok {
+		return token, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockConsensus) Promote(serverID string) error {
+	args := m.Called(serverID)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) Demote(serverID string) error {
+	args := m.Called(serverID)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) Leave() error {
+	args := m.Called()
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) TransferLeadership(serverID string) error {
+	args := m.Called(serverID)
 
 	return args.Error(0)
 }
@@ -38,6 +73,28 @@ func (m *MockConsensus) Forget(serverID string) error {
 	return args.Error(0)
 }
 
+func (m *MockConsensus) Audit(entry *raft.AuditEntry) error {
+	args := m.Called(entry)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) AppendAudit(entry *raft.AuditEntry) error {
+	args := m.Called(entry)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) AuditLog(query *raft.AuditQuery) (*raft.AuditLog, error) {
+	args := m.Called(query)
+
+	if auditLog, ok := args.Get(0).(*raft.AuditLog); ok {
+		return auditLog, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
 func (m *MockConsensus) GetInfo(verbose bool) (*raft.Info, error) {
 	args := m.Called(verbose)
 
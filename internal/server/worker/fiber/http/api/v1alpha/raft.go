@@ -2,19 +2,25 @@
 package v1alpha
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/jinzhu/copier"
+	serverAPI "github.com/weastur/maf/internal/server/worker/fiber/http/api"
 	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
 )
 
 // Join to cluster
 //
 // @Summary      Join server to cluster
-// @Description  Join the server to the cluster. The server becomes voter in case of success
+// @Description  Join the server to the cluster. The server becomes voter in case of success,
+// @Description  or non-voter if requested. Non-voters replicate the log but don't take part in elections.
+// @Description  New servers must present a valid join token, see /raft/token
 // @Tags         raft
 // @Param        request body RaftJoinRequest true "Join request"
 // @Success      200 {object} Response "Response with error details or success code"
 // @Router       /raft/join [post]
+// @Deprecated
 // @Security     ApiKeyAuth
 // @Header       all {string} X-Request-ID "UUID of the request"
 // @Header       all {string} X-API-Version "API version, e.g. v1alpha"
@@ -29,7 +35,9 @@ func raftJoinHandler(c *fiber.Ctx) error {
 		return err
 	}
 
-	if err := uCtx.co.Join(joinReq.ServerID, joinReq.Addr); err != nil {
+	serverAPI.SetAuditTarget(c, joinReq.ServerID)
+
+	if err := uCtx.co.Join(joinReq.ServerID, joinReq.Addr, joinReq.Nonvoter, joinReq.Token); err != nil {
 		return err
 	}
 
@@ -45,6 +53,7 @@ func raftJoinHandler(c *fiber.Ctx) error {
 // @Param        request body RaftForgetRequest true "Forget request"
 // @Success      200 {object} Response "Response with error details or success code"
 // @Router       /raft/forget [post]
+// @Deprecated
 // @Security     ApiKeyAuth
 // @Header       all {string} X-Request-ID "UUID of the request"
 // @Header       all {string} X-API-Version "API version, e.g. v1alpha"
@@ -59,6 +68,8 @@ func raftForgetHandler(c *fiber.Ctx) error {
 		return err
 	}
 
+	serverAPI.SetAuditTarget(c, forgetReq.ServerID)
+
 	if err := uCtx.co.Forget(forgetReq.ServerID); err != nil {
 		return err
 	}
@@ -66,6 +77,164 @@ func raftForgetHandler(c *fiber.Ctx) error {
 	return v1alphaUtils.WrapResponse(c, v1alphaUtils.StatusSuccess, nil, nil)
 }
 
+// Promote the server
+//
+// @Summary      Promote the server to voter
+// @Description  Promote the non-voter server to voter. Promoting a voter is a no-op
+// @Tags         raft
+// @Param        request body RaftPromoteRequest true "Promote request"
+// @Success      200 {object} Response "Response with error details or success code"
+// @Router       /raft/promote [post]
+// @Deprecated
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1alpha"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func raftPromoteHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	promoteReq := new(RaftPromoteRequest)
+	if err := parseAndValidate(c, promoteReq); err != nil {
+		return err
+	}
+
+	serverAPI.SetAuditTarget(c, promoteReq.ServerID)
+
+	if err := uCtx.co.Promote(promoteReq.ServerID); err != nil {
+		return err
+	}
+
+	return v1alphaUtils.WrapResponse(c, v1alphaUtils.StatusSuccess, nil, nil)
+}
+
+// Demote the server
+//
+// @Summary      Demote the server to non-voter
+// @Description  Demote the voter server to non-voter. The server keeps replicating the log,
+// @Description  but doesn't take part in elections. Demoting a non-voter is a no-op
+// @Tags         raft
+// @Param        request body RaftDemoteRequest true "Demote request"
+// @Success      200 {object} Response "Response with error details or success code"
+// @Router       /raft/demote [post]
+// @Deprecated
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1alpha"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func raftDemoteHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	demoteReq := new(RaftDemoteRequest)
+	if err := parseAndValidate(c, demoteReq); err != nil {
+		return err
+	}
+
+	serverAPI.SetAuditTarget(c, demoteReq.ServerID)
+
+	if err := uCtx.co.Demote(demoteReq.ServerID); err != nil {
+		return err
+	}
+
+	return v1alphaUtils.WrapResponse(c, v1alphaUtils.StatusSuccess, nil, nil)
+}
+
+// Leave the cluster
+//
+// @Summary      Leave the cluster
+// @Description  Remove this server from the cluster, so it no longer counts toward the quorum.
+// @Description  Refused if the remaining voters can't keep the quorum. The server must be stopped afterwards.
+// @Description  Requires the admin role, like removing any other server
+// @Tags         raft
+// @Success      200 {object} Response "Response with error details or success code"
+// @Router       /raft/leave [post]
+// @Deprecated
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1alpha"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func raftLeaveHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	if err := uCtx.co.Leave(); err != nil {
+		return err
+	}
+
+	return v1alphaUtils.WrapResponse(c, v1alphaUtils.StatusSuccess, nil, nil)
+}
+
+// Transfer leadership
+//
+// @Summary      Transfer leadership
+// @Description  Transfer the leadership to the given voter, or to the most up-to-date voter
+// @Description  if the server ID is empty. Must be called on the leader
+// @Tags         raft
+// @Param        request body RaftLeadershipTransferRequest true "Leadership transfer request"
+// @Success      200 {object} Response "Response with error details or success code"
+// @Router       /raft/leadership-transfer [post]
+// @Deprecated
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1alpha"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func raftLeadershipTransferHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	transferReq := new(RaftLeadershipTransferRequest)
+	if err := parseAndValidate(c, transferReq); err != nil {
+		return err
+	}
+
+	serverAPI.SetAuditTarget(c, transferReq.ServerID)
+
+	if err := uCtx.co.TransferLeadership(transferReq.ServerID); err != nil {
+		return err
+	}
+
+	return v1alphaUtils.WrapResponse(c, v1alphaUtils.StatusSuccess, nil, nil)
+}
+
+// Create join token
+//
+// @Summary      Create join token
+// @Description  Issue a short-lived token the new servers must present to join the cluster.
+// @Description  The token can be used by several servers until it expires. Must be called on the leader
+// @Tags         raft
+// @Param        request body RaftTokenCreateRequest true "Token create request"
+// @Success      200 {object} Response{data=RaftTokenCreateResponse} "Issued join token"
+// @Router       /raft/token [post]
+// @Deprecated
+// @Security     ApiKeyAuth
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1alpha"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func raftTokenCreateHandler(c *fiber.Ctx) error {
+	uCtx := unpackCtx(c)
+
+	tokenReq := new(RaftTokenCreateRequest)
+	if err := parseAndValidate(c, tokenReq); err != nil {
+		return err
+	}
+
+	token, err := uCtx.co.CreateJoinToken(time.Duration(tokenReq.TTL) * time.Second)
+	if err != nil {
+		return err
+	}
+
+	data := &RaftTokenCreateResponse{Token: token.Token, ExpiresAt: token.ExpiresAt}
+
+	return v1alphaUtils.WrapResponse(c, v1alphaUtils.StatusSuccess, data, nil)
+}
+
 // Get raft info
 //
 // @Summary      Return raft info
@@ -73,6 +242,7 @@ func raftForgetHandler(c *fiber.Ctx) error {
 // @Tags         raft
 // @Success      200 {object} Response{data=RaftInfoResponse} "Raft cluster info"
 // @Router       /raft/info [get]
+// @Deprecated
 // @Param        include_stats query bool false "Include extended stats"
 // @Security     ApiKeyAuth
 // @Header       all {string} X-Request-ID "UUID of the request"
@@ -103,6 +273,7 @@ func raftInfoHandler(c *fiber.Ctx) error {
 // @Tags         raft
 // @Success      200 {object} Response{data=KVGetResponse} "KV get response"
 // @Router       /raft/kv/{key} [get]
+// @Deprecated
 // @Param        key path string true "Key to receive value"
 // @Security     ApiKeyAuth
 // @Header       all {string} X-Request-ID "UUID of the request"
@@ -123,11 +294,12 @@ func raftKVGetHandler(c *fiber.Ctx) error {
 // Set key/value in kv store
 //
 // @Summary      Set value for key
-// @Description  Set value in kv store for the key
+// @Description  Set value in kv store for the key. Must be called on the leader
 // @Tags         raft
 // @Param        request body KVSetRequest true "KV set request"
 // @Success      200 {object} Response "Response with error details or success code"
 // @Router       /raft/kv [post]
+// @Deprecated
 // @Security     ApiKeyAuth
 // @Header       all {string} X-Request-ID "UUID of the request"
 // @Header       all {string} X-API-Version "API version, e.g. v1alpha"
@@ -142,6 +314,8 @@ func raftKVSetHandler(c *fiber.Ctx) error {
 		return err
 	}
 
+	serverAPI.SetAuditTarget(c, setReq.Key)
+
 	if err := uCtx.co.Set(setReq.Key, setReq.Value); err != nil {
 		return err
 	}
@@ -152,10 +326,11 @@ func raftKVSetHandler(c *fiber.Ctx) error {
 // Delete value from kv store
 //
 // @Summary      Delete value for key
-// @Description  Delete value in kv store for the key
+// @Description  Delete value in kv store for the key. Must be called on the leader
 // @Tags         raft
 // @Success      200 {object} Response "Response with error details or success code"
 // @Router       /raft/kv/{key} [delete]
+// @Deprecated
 // @Param        key path string true "Key to delete value for"
 // @Security     ApiKeyAuth
 // @Header       all {string} X-Request-ID "UUID of the request"
@@ -167,6 +342,7 @@ func raftKVDeleteHandler(c *fiber.Ctx) error {
 	uCtx := unpackCtx(c)
 
 	key := c.Params("key")
+	serverAPI.SetAuditTarget(c, key)
 
 	if err := uCtx.co.Delete(key); err != nil {
 		return err
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
@@ -56,9 +58,9 @@ func TestRaftJoinHandler(t *testing.T) {
 		app.Post("/test", raftJoinHandler)
 
 		defer app.Shutdown()
-		mockConsensus.On("Join", "server-1", "127.0.0.1").Return(nil).Once()
+		mockConsensus.On("Join", "server-1", "127.0.0.1", false, "secret").Return(nil).Once()
 
-		body := `{"serverId": "server-1", "addr": "127.0.0.1"}`
+		body := `{"serverId": "server-1", "addr": "127.0.0.1", "token": "secret"}`
 		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
 
@@ -76,7 +78,7 @@ func TestRaftJoinHandler(t *testing.T) {
 		app.Post("/test", raftJoinHandler)
 
 		defer app.Shutdown()
-		mockConsensus.On("Join", "server-1", "127.0.0.1").Return(errors.New("join error")).Once()
+		mockConsensus.On("Join", "server-1", "127.0.0.1", false, "").Return(errors.New("join error")).Once()
 
 		reqBody := `{"serverId": "server-1", "addr": "127.0.0.1"}`
 		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(reqBody))
@@ -85,7 +87,7 @@ func TestRaftJoinHandler(t *testing.T) {
 		resp, err := app.Test(req)
 		require.NoError(t, err)
 
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 		respBody, _ := io.ReadAll(resp.Body)
 
 		var response map[string]any
@@ -112,7 +114,7 @@ func TestRaftJoinHandler(t *testing.T) {
 		resp, err := app.Test(req)
 		require.NoError(t, err)
 
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 		respBody, _ := io.ReadAll(resp.Body)
 
 		var response map[string]any
@@ -123,6 +125,67 @@ func TestRaftJoinHandler(t *testing.T) {
 	})
 }
 
+func TestRaftTokenCreateHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful create", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", raftTokenCreateHandler)
+
+		defer app.Shutdown()
+
+		expiresAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockConsensus.On("CreateJoinToken", 30*time.Minute).
+			Return(&raft.JoinToken{Token: "secret", ExpiresAt: expiresAt}, nil).
+			Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(`{"ttl": 1800}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		respBody, _ := io.ReadAll(resp.Body)
+
+		var response map[string]any
+		err = json.Unmarshal(respBody, &response)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"token": "secret", "expiresAt": "2025-01-01T00:00:00Z"}, response["data"])
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("error on create", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", raftTokenCreateHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("CreateJoinToken", time.Duration(0)).Return(nil, raft.ErrNotALeader).Once()
+		mockConsensus.On("Get", LeaderAPIAddrKey).Return("http://10.0.0.1:7080", true).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		respBody, _ := io.ReadAll(resp.Body)
+
+		var response map[string]any
+		err = json.Unmarshal(respBody, &response)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusMisdirectedRequest, resp.StatusCode)
+		assert.Equal(t, raft.ErrNotALeader.Error(), response["error"])
+		assert.Equal(t, "not_leader", response["code"])
+		assert.Equal(t, map[string]any{"leader": "http://10.0.0.1:7080"}, response["details"])
+
+		mockConsensus.AssertExpectations(t)
+	})
+}
+
 func TestRaftForgetHandler(t *testing.T) {
 	t.Parallel()
 
@@ -162,7 +225,7 @@ func TestRaftForgetHandler(t *testing.T) {
 		resp, err := app.Test(req)
 		require.NoError(t, err)
 
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 		respBody, _ := io.ReadAll(resp.Body)
 
 		var response map[string]any
@@ -189,7 +252,7 @@ func TestRaftForgetHandler(t *testing.T) {
 		resp, err := app.Test(req)
 		require.NoError(t, err)
 
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 		respBody, _ := io.ReadAll(resp.Body)
 
 		var response map[string]any
@@ -200,6 +263,226 @@ func TestRaftForgetHandler(t *testing.T) {
 	})
 }
 
+func TestRaftPromoteHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful promote", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", raftPromoteHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Promote", "server-1").Return(nil).Once()
+
+		body := `{"serverId": "server-1"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("error on promote", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", raftPromoteHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Promote", "server-1").Return(errors.New("promote error")).Once()
+
+		body := `{"serverId": "server-1"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		respBody, _ := io.ReadAll(resp.Body)
+
+		var response map[string]any
+		err = json.Unmarshal(respBody, &response)
+		require.NoError(t, err)
+		assert.Equal(t, "promote error", response["error"])
+
+		mockConsensus.AssertExpectations(t)
+	})
+}
+
+func TestRaftDemoteHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful demote", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", raftDemoteHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Demote", "server-1").Return(nil).Once()
+
+		body := `{"serverId": "server-1"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("error on demote", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", raftDemoteHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Demote", "server-1").Return(errors.New("demote error")).Once()
+
+		body := `{"serverId": "server-1"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		respBody, _ := io.ReadAll(resp.Body)
+
+		var response map[string]any
+		err = json.Unmarshal(respBody, &response)
+		require.NoError(t, err)
+		assert.Equal(t, "demote error", response["error"])
+
+		mockConsensus.AssertExpectations(t)
+	})
+}
+
+func TestRaftLeaveHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful leave", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", raftLeaveHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Leave").Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/test", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("error on leave", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", raftLeaveHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Leave").Return(raft.ErrLeaveQuorum).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/test", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		respBody, _ := io.ReadAll(resp.Body)
+
+		var response map[string]any
+		err = json.Unmarshal(respBody, &response)
+		require.NoError(t, err)
+		assert.Equal(t, raft.ErrLeaveQuorum.Error(), response["error"])
+
+		mockConsensus.AssertExpectations(t)
+	})
+}
+
+func TestRaftLeadershipTransferHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful transfer", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", raftLeadershipTransferHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("TransferLeadership", "server-2").Return(nil).Once()
+
+		body := `{"serverId": "server-2"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("transfer to any server", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", raftLeadershipTransferHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("TransferLeadership", "").Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("error on transfer", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", raftLeadershipTransferHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("TransferLeadership", "server-2").Return(raft.ErrNotALeader).Once()
+		mockConsensus.On("Get", LeaderAPIAddrKey).Return("", false).Once()
+
+		body := `{"serverId": "server-2"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		respBody, _ := io.ReadAll(resp.Body)
+
+		var response map[string]any
+		err = json.Unmarshal(respBody, &response)
+		require.NoError(t, err)
+		assert.Equal(t, raft.ErrNotALeader.Error(), response["error"])
+		assert.Equal(t, "not_leader", response["code"])
+		assert.NotContains(t, response, "details")
+
+		mockConsensus.AssertExpectations(t)
+	})
+}
+
 func TestRaftInfoHandler(t *testing.T) {
 	t.Parallel()
 
@@ -252,7 +535,7 @@ func TestRaftInfoHandler(t *testing.T) {
 		resp, err := app.Test(req)
 		require.NoError(t, err)
 
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 		respBody, _ := io.ReadAll(resp.Body)
 
 		var response map[string]any
@@ -339,7 +622,7 @@ func TestRaftKVSetHandler(t *testing.T) {
 		resp, err := app.Test(req)
 		require.NoError(t, err)
 
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 		respBody, _ := io.ReadAll(resp.Body)
 
 		var response map[string]any
@@ -351,6 +634,62 @@ func TestRaftKVSetHandler(t *testing.T) {
 		mockConsensus.AssertExpectations(t)
 	})
 
+	t.Run("reserved key", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", raftKVSetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Set", "maf/audit/head", "x").Return(fmt.Errorf("%w: maf/audit/head", raft.ErrReservedKey)).Once()
+
+		body := `{"key": "maf/audit/head", "value": "x"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+		respBody, _ := io.ReadAll(resp.Body)
+
+		var response map[string]any
+		err = json.Unmarshal(respBody, &response)
+		require.NoError(t, err)
+		assert.Equal(t, "forbidden", response["code"])
+
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("follower", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Post("/test", raftKVSetHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Set", "test-key", "test-value").Return(raft.ErrNotALeader).Once()
+		mockConsensus.On("Get", LeaderAPIAddrKey).Return("http://10.0.0.1:7080", true).Once()
+
+		body := `{"key": "test-key", "value": "test-value"}`
+		req, _ := http.NewRequest(http.MethodPost, "/test", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusMisdirectedRequest, resp.StatusCode)
+		respBody, _ := io.ReadAll(resp.Body)
+
+		var response map[string]any
+		err = json.Unmarshal(respBody, &response)
+		require.NoError(t, err)
+		assert.Equal(t, "not_leader", response["code"])
+		assert.Equal(t, map[string]any{"leader": "http://10.0.0.1:7080"}, response["details"])
+
+		mockConsensus.AssertExpectations(t)
+	})
+
 	t.Run("invalid body", func(t *testing.T) {
 		t.Parallel()
 
@@ -366,7 +705,7 @@ func TestRaftKVSetHandler(t *testing.T) {
 		resp, err := app.Test(req)
 		require.NoError(t, err)
 
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 		respBody, _ := io.ReadAll(resp.Body)
 
 		var response map[string]any
@@ -412,7 +751,7 @@ func TestRaftKVDeleteHandler(t *testing.T) {
 		resp, err := app.Test(req)
 		require.NoError(t, err)
 
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 		respBody, _ := io.ReadAll(resp.Body)
 
 		var response map[string]any
@@ -423,4 +762,30 @@ func TestRaftKVDeleteHandler(t *testing.T) {
 
 		mockConsensus.AssertExpectations(t)
 	})
+
+	t.Run("follower", func(t *testing.T) {
+		t.Parallel()
+
+		app, mockConsensus := getTestFiberApp()
+		app.Delete("/test/:key", raftKVDeleteHandler)
+
+		defer app.Shutdown()
+		mockConsensus.On("Delete", "test-key").Return(raft.ErrNotALeader).Once()
+		mockConsensus.On("Get", LeaderAPIAddrKey).Return("", false).Once()
+
+		req, _ := http.NewRequest(http.MethodDelete, "/test/test-key", nil)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusMisdirectedRequest, resp.StatusCode)
+		respBody, _ := io.ReadAll(resp.Body)
+
+		var response map[string]any
+		err = json.Unmarshal(respBody, &response)
+		require.NoError(t, err)
+		assert.Equal(t, "not_leader", response["code"])
+
+		mockConsensus.AssertExpectations(t)
+	})
 }
@@ -4,10 +4,12 @@ import (
 	"context"
 	"embed"
 	"sync"
+	"time"
 
 	"github.com/gofiber/contrib/swagger"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog"
+	serverAPI "github.com/weastur/maf/internal/server/worker/fiber/http/api"
 	"github.com/weastur/maf/internal/server/worker/raft"
 	httpUtils "github.com/weastur/maf/internal/utils/http"
 	apiUtils "github.com/weastur/maf/internal/utils/http/api"
@@ -15,17 +17,25 @@ import (
 )
 
 const (
+	Version                     = "v1alpha"
 	consensusInstanceContextKey = apiUtils.UserContextKey("consensusInstance")
+	LeaderAPIAddrKey            = serverAPI.LeaderAPIAddrKey
 )
 
 type Consensus interface {
 	IsLeader() bool
-	Join(serverID, addr string) error
+	Join(serverID, addr string, nonvoter bool, token string) error
+	CreateJoinToken(ttl time.Duration) (*raft.JoinToken, error)
 	Forget(serverID string) error
+	Promote(serverID string) error
+	Demote(serverID string) error
+	Leave() error
+	TransferLeadership(serverID string) error
 	GetInfo(verbose bool) (*raft.Info, error)
 	Get(key string) (string, bool)
 	Set(key, value string) error
 	Delete(key string) error
+	serverAPI.Auditor
 }
 
 type Validator interface {
@@ -49,8 +59,8 @@ var (
 func Get() *APIV1Alpha {
 	once.Do(func() {
 		instance = &APIV1Alpha{
-			version:   "v1alpha",
-			prefix:    "/v1alpha",
+			version:   Version,
+			prefix:    "/" + Version,
 			validator: v1alphaUtils.NewXValidator(),
 		}
 	})
@@ -60,7 +70,8 @@ func Get() *APIV1Alpha {
 
 // @title MySQL auto failover server API
 // @version v1alpha
-// @description Server API for MySQL auto failover. Generally must be called by CLI
+// @description Server API for MySQL auto failover. Generally must be called by CLI.
+// @description Deprecated in favor of v1, the responses carry the Deprecation header
 // @contact.name Pavel Sapezhka
 // @contact.url weastur.com
 // @contact.email me@weastur.com
@@ -71,6 +82,8 @@ func Get() *APIV1Alpha {
 // @tag.description Auxiliary endpoints
 // @tag.name raft
 // @tag.description Raft-related endpoints
+// @tag.name audit
+// @tag.description Audit log of the mutating calls
 // @BasePath /api/v1alpha
 // @accept json
 // @produce json
@@ -78,11 +91,17 @@ func Get() *APIV1Alpha {
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name X-Auth-Token
-// @description API key for the server. For now, only 'root' is allowed
+// @description API token bound to a role: read, operator or admin. Optional for mapped client certs
 // @externalDocs.description Find out more about MAF on GitHub
 // @externalDocs.url https://github.com/weastur/maf/wiki
-func (api *APIV1Alpha) Init(topRouter fiber.Router, logger zerolog.Logger, co Consensus) {
+func (api *APIV1Alpha) Init(
+	topRouter fiber.Router,
+	logger zerolog.Logger,
+	co Consensus,
+	auth *v1alphaUtils.Authenticator,
+) {
 	router := httpUtils.APIVersionGroup(topRouter, api.version)
+	router.Use(httpUtils.DeprecatedAPIVersion(serverAPI.SuccessorVersion))
 
 	swaggerContent, _ := swaggerJSON.ReadFile("swagger.json")
 	router.Use(swagger.New(swagger.Config{
@@ -101,18 +120,30 @@ func (api *APIV1Alpha) Init(topRouter fiber.Router, logger zerolog.Logger, co Co
 
 		return c.Next()
 	})
-	router.Use(v1alphaUtils.AuthMiddleware())
+	router.Use(v1alphaUtils.AuthMiddleware(auth))
 
 	router.Get("/version", v1alphaUtils.VersionHandler)
 
-	router.Post("/raft/join", raftJoinHandler)
-	router.Post("/raft/forget", raftForgetHandler)
-	router.Get("/raft/info", raftInfoHandler)
-	router.Get("/raft/kv/:key", raftKVGetHandler)
-	router.Post("/raft/kv", raftKVSetHandler)
-	router.Delete("/raft/kv/:key", raftKVDeleteHandler)
+	read := v1alphaUtils.RequireRole(v1alphaUtils.RoleRead)
+	operator := v1alphaUtils.RequireRole(v1alphaUtils.RoleOperator)
+	admin := v1alphaUtils.RequireRole(v1alphaUtils.RoleAdmin)
+	peer := v1alphaUtils.RequirePeer()
+
+	router.Post("/raft/join", operator, audit("raft.join"), raftJoinHandler)
+	router.Post("/raft/forget", admin, audit("raft.forget"), raftForgetHandler)
+	router.Post("/raft/promote", operator, audit("raft.promote"), raftPromoteHandler)
+	router.Post("/raft/demote", operator, audit("raft.demote"), raftDemoteHandler)
+	router.Post("/raft/leave", admin, audit("raft.leave"), raftLeaveHandler)
+	router.Post("/raft/leadership-transfer", operator, audit("raft.leadership-transfer"), raftLeadershipTransferHandler)
+	router.Post("/raft/token", admin, audit("raft.token.create"), raftTokenCreateHandler)
+	router.Get("/raft/info", read, raftInfoHandler)
+	router.Get("/raft/kv/:key", read, raftKVGetHandler)
+	router.Post("/raft/kv", operator, audit("kv.set"), raftKVSetHandler)
+	router.Delete("/raft/kv/:key", operator, audit("kv.delete"), raftKVDeleteHandler)
+	router.Get("/audit", read, auditListHandler)
+	router.Post("/audit", peer, auditAppendHandler)
 }
 
 func (api *APIV1Alpha) ErrorHandler(c *fiber.Ctx, err error) error {
-	return v1alphaUtils.ErrorHandler(c, err)
+	return v1alphaUtils.WrapError(c, classifyError(c, err))
 }
@@ -5,12 +5,15 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
 )
 
 func TestAPIV1Alpha_Init(t *testing.T) {
@@ -25,7 +28,13 @@ func TestAPIV1Alpha_Init(t *testing.T) {
 		prefix:    "/v1alpha",
 		validator: new(MockValidator),
 	}
-	api.Init(app.Group("/api"), logger, mockConsensus)
+	auth, err := v1alphaUtils.NewAuthenticator(&v1alphaUtils.AuthConfig{Tokens: []v1alphaUtils.TokenConfig{
+		{Name: "reader", Role: "read", Token: "reader-token"},
+		{Name: "admin", Role: "admin", Token: "admin-token"},
+	}})
+	require.NoError(t, err)
+
+	api.Init(app.Group("/api"), logger, mockConsensus, auth)
 
 	t.Run("Swagger Docs Endpoint", func(t *testing.T) {
 		t.Parallel()
@@ -55,7 +64,7 @@ func TestAPIV1Alpha_Init(t *testing.T) {
 		req, _ := http.NewRequest(fiber.MethodGet, "/api/v1alpha/protected", nil)
 		resp, _ := app.Test(req, -1)
 
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
 		body, _ := io.ReadAll(resp.Body)
 
 		var response map[string]any
@@ -69,13 +78,38 @@ func TestAPIV1Alpha_Init(t *testing.T) {
 		t.Parallel()
 
 		req, _ := http.NewRequest(fiber.MethodGet, "/api/v1alpha/invalid", nil)
-		req.Header.Set("X-Auth-Token", "root")
+		req.Header.Set("X-Auth-Token", "admin-token")
 		resp, _ := app.Test(req, -1)
 
 		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
 		body, _ := io.ReadAll(resp.Body)
 		assert.Contains(t, string(body), "Cannot GET /api/v1alpha/invalid")
 	})
+
+	t.Run("Insufficient Role", func(t *testing.T) {
+		t.Parallel()
+
+		req, _ := http.NewRequest(fiber.MethodPost, "/api/v1alpha/raft/forget", nil)
+		req.Header.Set("X-Auth-Token", "reader-token")
+		resp, _ := app.Test(req, -1)
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(body), "insufficient role: admin is required")
+		mockConsensus.AssertNotCalled(t, "Forget", mock.Anything)
+	})
+	t.Run("Audit Append Requires Peer", func(t *testing.T) {
+		t.Parallel()
+
+		reqBody := `{"actor": "admin", "action": "raft.leave", "outcome": "success"}`
+		req, _ := http.NewRequest(fiber.MethodPost, "/api/v1alpha/audit", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Auth-Token", "admin-token")
+		resp, _ := app.Test(req, -1)
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(body), "only the servers of the cluster are allowed")
+		mockConsensus.AssertNotCalled(t, "AppendAudit", mock.Anything)
+	})
 }
 
 func TestAPIV1Alpha_Get(t *testing.T) {
@@ -126,7 +160,7 @@ func TestAPIV1Alpha_ErrorHandler(t *testing.T) {
 		req, _ := http.NewRequest(fiber.MethodGet, "/error", nil)
 		resp, _ := app.Test(req, -1)
 
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 		body, _ := io.ReadAll(resp.Body)
 
 		var response map[string]any
@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	serverAPI "github.com/weastur/maf/internal/server/worker/fiber/http/api"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	grpcUtils "github.com/weastur/maf/internal/utils/grpc"
+	apiUtils "github.com/weastur/maf/internal/utils/http/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// audit writes the audit record of the finished call, the same as the HTTP API does.
+// It returns the error of the call, the failure to write the record is reported in the trailer
+func audit(ctx context.Context, co Consensus, action, target string, err error) error {
+	entry := &raft.AuditEntry{
+		Time:    time.Now().UTC(),
+		Actor:   serverAPI.AnonymousActor,
+		Action:  action,
+		Target:  target,
+		Outcome: raft.AuditOutcomeSuccess,
+	}
+
+	if identity, ok := grpcUtils.GetIdentity(ctx); ok {
+		entry.Actor = identity.Name
+		entry.Role = identity.Role.String()
+	}
+
+	entry.RequestID, _ = ctx.Value(apiUtils.RequestIDContextKey).(string)
+
+	if err != nil {
+		entry.Outcome = raft.AuditOutcomeFailure
+		entry.Error = err.Error()
+	}
+
+	if auditErr := co.Audit(entry); auditErr != nil {
+		serverAPI.ReportAuditFailure(zerolog.Ctx(ctx), action, auditErr)
+
+		if err == nil {
+			_ = grpc.SetTrailer(ctx, metadata.Pairs(strings.ToLower(serverAPI.AuditFailedHeader), "true"))
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,45 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	apiV1 "github.com/weastur/maf/internal/utils/grpc/api/v1"
+)
+
+type clusterService struct {
+	apiV1.UnimplementedClusterServer
+
+	co Consensus
+}
+
+func (s *clusterService) GetInfo(ctx context.Context, req *apiV1.GetInfoRequest) (*apiV1.GetInfoResponse, error) {
+	logger := zerolog.Ctx(ctx)
+
+	coInfo, err := s.co.GetInfo(req.GetIncludeStats())
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get raft info")
+
+		return nil, statusError(err, s.co)
+	}
+
+	resp := &apiV1.GetInfoResponse{
+		State:     coInfo.State,
+		Addr:      coInfo.Addr,
+		Advertise: coInfo.Advertise,
+		Id:        coInfo.ID,
+		Servers:   make([]*apiV1.Server, 0, len(coInfo.Servers)),
+		Stats:     coInfo.Stats,
+	}
+
+	for _, srv := range coInfo.Servers {
+		resp.Servers = append(resp.Servers, &apiV1.Server{
+			Id:       srv.ID,
+			Address:  srv.Address,
+			Suffrage: srv.Suffrage,
+			Leader:   srv.Leader,
+		})
+	}
+
+	return resp, nil
+}
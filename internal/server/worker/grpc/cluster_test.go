@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	apiV1 "github.com/weastur/maf/internal/utils/grpc/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClusterService_GetInfo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		_, mockConsensus, conn := startTestServer(t)
+		mockConsensus.On("GetInfo", true).Return(&raft.Info{
+			State:     "Leader",
+			Addr:      "0.0.0.0:7081",
+			Advertise: "10.0.0.1:7081",
+			ID:        "maf-1",
+			Servers:   []raft.Server{{ID: "maf-1", Address: "10.0.0.1:7081", Suffrage: "Voter", Leader: true}},
+			Stats:     raft.Stats{"term": "2"},
+		}, nil)
+
+		resp, err := apiV1.NewClusterClient(conn).GetInfo(
+			withToken("read-token"), &apiV1.GetInfoRequest{IncludeStats: true},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Leader", resp.GetState())
+		assert.Equal(t, "maf-1", resp.GetId())
+		assert.Equal(t, "10.0.0.1:7081", resp.GetAdvertise())
+		require.Len(t, resp.GetServers(), 1)
+		assert.True(t, resp.GetServers()[0].GetLeader())
+		assert.Equal(t, "Voter", resp.GetServers()[0].GetSuffrage())
+		assert.Equal(t, map[string]string{"term": "2"}, resp.GetStats())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+
+		_, mockConsensus, conn := startTestServer(t)
+		mockConsensus.On("GetInfo", false).Return(nil, errors.New("raft is not ready"))
+
+		_, err := apiV1.NewClusterClient(conn).GetInfo(withToken("read-token"), &apiV1.GetInfoRequest{})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		t.Parallel()
+
+		_, mockConsensus, conn := startTestServer(t)
+
+		_, err := apiV1.NewClusterClient(conn).GetInfo(context.Background(), &apiV1.GetInfoRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockConsensus.AssertNotCalled(t, "GetInfo", false)
+	})
+}
@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	serverAPI "github.com/weastur/maf/internal/server/worker/fiber/http/api"
+	v1 "github.com/weastur/maf/internal/server/worker/fiber/http/api/v1"
+	"github.com/weastur/maf/internal/utils/certs"
+	grpcUtils "github.com/weastur/maf/internal/utils/grpc"
+	apiV1 "github.com/weastur/maf/internal/utils/grpc/api/v1"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+	"github.com/weastur/maf/internal/utils/logging"
+	"google.golang.org/grpc"
+)
+
+// Consensus is the same as the one behind the HTTP API, so the behaviour is identical across the transports
+type Consensus = v1.Consensus
+
+type Config struct {
+	Addr            string
+	CertFile        string
+	KeyFile         string
+	ClientCertFile  string
+	ShutdownTimeout time.Duration
+	Auth            *v1alphaUtils.Authenticator
+}
+
+type Sentry interface {
+	Recover()
+}
+
+type GRPC struct {
+	config *Config
+	server *grpc.Server
+	certs  *certs.Store
+	co     Consensus
+	logger zerolog.Logger
+	sentry Sentry
+	// streamsDone ends the watch streams, which would block the graceful shutdown otherwise
+	streamsDone chan struct{}
+}
+
+var methodRoles = grpcUtils.MethodRoles{
+	apiV1.Cluster_GetInfo_FullMethodName: v1alphaUtils.RoleRead,
+	apiV1.KV_Get_FullMethodName:          v1alphaUtils.RoleRead,
+	apiV1.KV_Set_FullMethodName:          v1alphaUtils.RoleOperator,
+	apiV1.KV_Delete_FullMethodName:       v1alphaUtils.RoleOperator,
+	apiV1.KV_Watch_FullMethodName:        v1alphaUtils.RoleRead,
+}
+
+func New(config *Config, co Consensus, sentry Sentry) (*GRPC, error) {
+	log.Trace().Msg("Configuring grpc worker")
+
+	g := &GRPC{
+		config:      config,
+		co:          co,
+		logger:      log.With().Str(logging.ComponentCtxKey, "grpc").Logger(),
+		sentry:      sentry,
+		streamsDone: make(chan struct{}),
+	}
+
+	store, err := certs.NewServerStore(config.CertFile, config.KeyFile, config.ClientCertFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certs: %w", err)
+	}
+
+	g.certs = store
+	g.server = grpcUtils.NewServer(&grpcUtils.Config{
+		Certs: store,
+		Auth:  config.Auth,
+		Roles: methodRoles,
+	}, g.logger)
+	apiV1.RegisterClusterServer(g.server, &clusterService{co: co})
+	apiV1.RegisterKVServer(g.server, &kvService{co: co, done: g.streamsDone})
+
+	return g, nil
+}
+
+// statusError maps the consensus errors the same way the HTTP API does
+func statusError(err error, co Consensus) error {
+	if err == nil {
+		return nil
+	}
+
+	return grpcUtils.Error(serverAPI.ClassifyError(err, co))
+}
+
+func (g *GRPC) Run(wg *sync.WaitGroup) {
+	g.logger.Info().Msg("Running")
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer g.sentry.Recover()
+
+		if err := grpcUtils.Serve(g.server, g.logger, g.config.Addr); err != nil {
+			g.logger.Error().Err(err).Msg("failed to listen")
+		}
+	}()
+}
+
+func (g *GRPC) Stop() {
+	g.logger.Info().Msg("Stopping")
+
+	close(g.streamsDone)
+
+	if err := grpcUtils.Stop(g.server, g.config.ShutdownTimeout); err != nil {
+		g.logger.Error().Err(err).Msg("failed to shutdown grpc server")
+	}
+
+	if g.certs != nil {
+		g.certs.Close()
+	}
+}
@@ -0,0 +1,265 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	grpcUtils "github.com/weastur/maf/internal/utils/grpc"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testBufSize = 1024 * 1024
+
+type MockConsensus struct {
+	mock.Mock
+}
+
+func (m *MockConsensus) IsLeader() bool {
+	args := m.Called()
+
+	return args.Bool(0)
+}
+
+func (m *MockConsensus) Join(serverID, addr string, nonvoter bool, token string) error {
+	args := m.Called(serverID, addr, nonvoter, token)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) CreateJoinToken(ttl time.Duration) (*raft.JoinToken, error) {
+	args := m.Called(ttl)
+
+	if token, ok := args.Get(0).(*raft.JoinToken); ok {
+		return token, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockConsensus) Forget(serverID string) error {
+	args := m.Called(serverID)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) Promote(serverID string) error {
+	args := m.Called(serverID)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) Demote(serverID string) error {
+	args := m.Called(serverID)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) Leave() error {
+	args := m.Called()
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) TransferLeadership(serverID string) error {
+	args := m.Called(serverID)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) GetInfo(verbose bool) (*raft.Info, error) {
+	args := m.Called(verbose)
+
+	if info, ok := args.Get(0).(*raft.Info); ok {
+		return info, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockConsensus) Get(key string) (string, bool) {
+	args := m.Called(key)
+
+	return args.String(0), args.Bool(1)
+}
+
+func (m *MockConsensus) Set(key, value string) error {
+	args := m.Called(key, value)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) Delete(key string) error {
+	args := m.Called(key)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) Audit(entry *raft.AuditEntry) error {
+	args := m.Called(entry)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) AppendAudit(entry *raft.AuditEntry) error {
+	args := m.Called(entry)
+
+	return args.Error(0)
+}
+
+func (m *MockConsensus) AuditLog(query *raft.AuditQuery) (*raft.AuditLog, error) {
+	args := m.Called(query)
+
+	if auditLog, ok := args.Get(0).(*raft.AuditLog); ok {
+		return auditLog, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockConsensus) SubscribeOnEvents(ch raft.EventsCh, lastEventID uint64) []*raft.Event {
+	args := m.Called(ch, lastEventID)
+
+	if events, ok := args.Get(0).([]*raft.Event); ok {
+		return events
+	}
+
+	return nil
+}
+
+func (m *MockConsensus) UnsubscribeFromEvents(ch raft.EventsCh) {
+	m.Called(ch)
+}
+
+type MockSentry struct {
+	mock.Mock
+}
+
+func (m *MockSentry) Recover() {
+	m.Called()
+}
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	log.Logger = log.Output(zerolog.Nop())
+
+	os.Exit(m.Run())
+}
+
+func newTestAuthenticator(t *testing.T) *v1alphaUtils.Authenticator {
+	t.Helper()
+
+	auth, err := v1alphaUtils.NewAuthenticator(&v1alphaUtils.AuthConfig{Tokens: []v1alphaUtils.TokenConfig{
+		{Name: "reader", Role: "read", Token: "read-token"},
+		{Name: "operator", Role: "operator", Token: "operator-token"},
+	}})
+	require.NoError(t, err)
+
+	return auth
+}
+
+// startTestServer serves the gRPC API in memory and returns the client connection to it
+func startTestServer(t *testing.T) (*GRPC, *MockConsensus, *grpc.ClientConn) {
+	t.Helper()
+
+	mockConsensus := new(MockConsensus)
+
+	g, err := New(&Config{ShutdownTimeout: time.Second, Auth: newTestAuthenticator(t)}, mockConsensus, new(MockSentry))
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(testBufSize)
+
+	go func() {
+		_ = g.server.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		g.server.Stop()
+	})
+
+	return g, mockConsensus, conn
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), grpcUtils.AuthMetadataKey, token)
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful initialization", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := New(&Config{Addr: "127.0.0.1:0"}, new(MockConsensus), new(MockSentry))
+		require.NoError(t, err)
+
+		assert.NotNil(t, g.server)
+		assert.Contains(t, g.server.GetServiceInfo(), "maf.v1.Cluster")
+		assert.Contains(t, g.server.GetServiceInfo(), "maf.v1.KV")
+		assert.NotContains(t, g.server.GetServiceInfo(), "maf.v1.Agent")
+	})
+
+	t.Run("invalid cert", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(&Config{CertFile: "missing.pem", KeyFile: "missing.pem"}, new(MockConsensus), new(MockSentry))
+		require.Error(t, err)
+	})
+}
+
+func TestGRPC_Run(t *testing.T) {
+	t.Parallel()
+
+	mockSentry := new(MockSentry)
+	mockSentry.On("Recover").Return()
+
+	g, err := New(&Config{Addr: "invalid_address"}, new(MockConsensus), mockSentry)
+	require.NoError(t, err)
+
+	wg := &sync.WaitGroup{}
+
+	assert.NotPanics(t, func() {
+		g.Run(wg)
+		wg.Wait()
+	}, "Run should not panic even if Listen returns an error")
+
+	mockSentry.AssertCalled(t, "Recover")
+}
+
+func TestGRPC_Stop(t *testing.T) {
+	t.Parallel()
+
+	g, err := New(&Config{ShutdownTimeout: time.Second}, new(MockConsensus), new(MockSentry))
+	require.NoError(t, err)
+
+	g.Stop()
+
+	select {
+	case <-g.streamsDone:
+	default:
+		t.Fatal("expected streams to be ended on shutdown")
+	}
+}
@@ -0,0 +1,114 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+	v1 "github.com/weastur/maf/internal/server/worker/fiber/http/api/v1"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	grpcUtils "github.com/weastur/maf/internal/utils/grpc"
+	apiV1 "github.com/weastur/maf/internal/utils/grpc/api/v1"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const watchBuffer = 64
+
+var kvActions = map[string]apiV1.Action{
+	raft.OpType(raft.OpSet).String():    apiV1.Action_ACTION_SET,
+	raft.OpType(raft.OpDelete).String(): apiV1.Action_ACTION_DELETE,
+}
+
+type kvService struct {
+	apiV1.UnimplementedKVServer
+
+	co   Consensus
+	done <-chan struct{}
+}
+
+func (s *kvService) Get(_ context.Context, req *apiV1.GetRequest) (*apiV1.GetResponse, error) {
+	value, ok := s.co.Get(req.GetKey())
+	if !ok {
+		return nil, grpcUtils.Error(
+			v1alphaUtils.NewAPIError(v1alphaUtils.CodeNotFound, fmt.Errorf("%w: %s", v1.ErrKeyNotFound, req.GetKey()), nil),
+		)
+	}
+
+	return &apiV1.GetResponse{Key: req.GetKey(), Value: value}, nil
+}
+
+func (s *kvService) Set(ctx context.Context, req *apiV1.SetRequest) (*apiV1.SetResponse, error) {
+	err := audit(ctx, s.co, "kv.set", req.GetKey(), s.co.Set(req.GetKey(), req.GetValue()))
+
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to set key")
+
+		return nil, statusError(err, s.co)
+	}
+
+	return &apiV1.SetResponse{}, nil
+}
+
+func (s *kvService) Delete(ctx context.Context, req *apiV1.DeleteRequest) (*apiV1.DeleteResponse, error) {
+	err := audit(ctx, s.co, "kv.delete", req.GetKey(), s.co.Delete(req.GetKey()))
+
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to delete key")
+
+		return nil, statusError(err, s.co)
+	}
+
+	return &apiV1.DeleteResponse{}, nil
+}
+
+// Watch streams the KV events of the local server until the client goes away or the server shuts down
+func (s *kvService) Watch(req *apiV1.WatchRequest, stream grpc.ServerStreamingServer[apiV1.WatchResponse]) error {
+	logger := zerolog.Ctx(stream.Context())
+
+	ch := make(raft.EventsCh, watchBuffer)
+	replay := s.co.SubscribeOnEvents(ch, req.GetLastEventId())
+
+	defer s.co.UnsubscribeFromEvents(ch)
+
+	logger.Debug().Msgf("Watching keys with prefix %q, %d to replay", req.GetPrefix(), len(replay))
+
+	for _, event := range replay {
+		if err := sendChange(stream, req.GetPrefix(), event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case event := <-ch:
+			if err := sendChange(stream, req.GetPrefix(), event); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			logger.Debug().Msg("Watch is closed by the client")
+
+			return nil
+		case <-s.done:
+			logger.Debug().Msg("Server is shutting down, closing watch")
+
+			return nil
+		}
+	}
+}
+
+func sendChange(stream grpc.ServerStreamingServer[apiV1.WatchResponse], prefix string, event *raft.Event) error {
+	if event.Type != raft.EventKV || !strings.HasPrefix(event.Data[raft.EventDataKey], prefix) {
+		return nil
+	}
+
+	return stream.Send(&apiV1.WatchResponse{
+		Id:     event.ID,
+		Action: kvActions[event.Data[raft.EventDataAction]],
+		Key:    event.Data[raft.EventDataKey],
+		Value:  event.Data[raft.EventDataValue],
+		Time:   timestamppb.New(event.Time),
+	})
+}
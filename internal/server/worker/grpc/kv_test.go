@@ -0,0 +1,210 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	serverAPI "github.com/weastur/maf/internal/server/worker/fiber/http/api"
+	"github.com/weastur/maf/internal/server/worker/raft"
+	apiV1 "github.com/weastur/maf/internal/utils/grpc/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestKVService_Get(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Found", func(t *testing.T) {
+		t.Parallel()
+
+		_, mockConsensus, conn := startTestServer(t)
+		mockConsensus.On("Get", "key").Return("value", true)
+
+		resp, err := apiV1.NewKVClient(conn).Get(withToken("read-token"), &apiV1.GetRequest{Key: "key"})
+		require.NoError(t, err)
+		assert.Equal(t, "key", resp.GetKey())
+		assert.Equal(t, "value", resp.GetValue())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		_, mockConsensus, conn := startTestServer(t)
+		mockConsensus.On("Get", "key").Return("", false)
+
+		_, err := apiV1.NewKVClient(conn).Get(withToken("read-token"), &apiV1.GetRequest{Key: "key"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, "key is not found: key", status.Convert(err).Message())
+	})
+}
+
+func TestKVService_Set(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		_, mockConsensus, conn := startTestServer(t)
+		mockConsensus.On("Set", "key", "value").Return(nil)
+		mockConsensus.On("Audit", mock.MatchedBy(func(entry *raft.AuditEntry) bool {
+			return entry.Actor == "operator" &&
+				entry.Role == "operator" &&
+				entry.Action == "kv.set" &&
+				entry.Target == "key" &&
+				entry.RequestID != "" &&
+				entry.Outcome == raft.AuditOutcomeSuccess
+		})).Return(nil).Once()
+
+		_, err := apiV1.NewKVClient(conn).Set(withToken("operator-token"), &apiV1.SetRequest{Key: "key", Value: "value"})
+		require.NoError(t, err)
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("NotLeader", func(t *testing.T) {
+		t.Parallel()
+
+		_, mockConsensus, conn := startTestServer(t)
+		mockConsensus.On("Set", "key", "value").Return(raft.ErrNotALeader)
+		mockConsensus.On("Get", serverAPI.LeaderAPIAddrKey).Return("http://10.0.0.1:7080", true)
+		mockConsensus.On("Audit", mock.MatchedBy(func(entry *raft.AuditEntry) bool {
+			return entry.Outcome == raft.AuditOutcomeFailure
+		})).Return(nil).Once()
+
+		_, err := apiV1.NewKVClient(conn).Set(withToken("operator-token"), &apiV1.SetRequest{Key: "key", Value: "value"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		details := status.Convert(err).Details()
+		require.Len(t, details, 1)
+
+		apiErr, ok := details[0].(*apiV1.Error)
+		require.True(t, ok)
+		assert.Equal(t, "not_leader", apiErr.GetCode())
+		assert.Equal(t, map[string]string{"leader": "http://10.0.0.1:7080"}, apiErr.GetDetails())
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("InsufficientRole", func(t *testing.T) {
+		t.Parallel()
+
+		_, mockConsensus, conn := startTestServer(t)
+
+		_, err := apiV1.NewKVClient(conn).Set(withToken("read-token"), &apiV1.SetRequest{Key: "key", Value: "value"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockConsensus.AssertNotCalled(t, "Set", "key", "value")
+	})
+
+	t.Run("ReservedKey", func(t *testing.T) {
+		t.Parallel()
+
+		_, mockConsensus, conn := startTestServer(t)
+		mockConsensus.On("Set", "maf/audit/head", "value").Return(fmt.Errorf("%w: maf/audit/head", raft.ErrReservedKey))
+		mockConsensus.On("Audit", mock.Anything).Return(nil).Once()
+
+		_, err := apiV1.NewKVClient(conn).Set(
+			withToken("operator-token"), &apiV1.SetRequest{Key: "maf/audit/head", Value: "value"},
+		)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestKVService_Delete(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		_, mockConsensus, conn := startTestServer(t)
+		mockConsensus.On("Delete", "key").Return(nil)
+		mockConsensus.On("Audit", mock.MatchedBy(func(entry *raft.AuditEntry) bool {
+			return entry.Action == "kv.delete" && entry.Target == "key"
+		})).Return(nil).Once()
+
+		_, err := apiV1.NewKVClient(conn).Delete(withToken("operator-token"), &apiV1.DeleteRequest{Key: "key"})
+		require.NoError(t, err)
+		mockConsensus.AssertExpectations(t)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+
+		_, mockConsensus, conn := startTestServer(t)
+		mockConsensus.On("Delete", "key").Return(errors.New("apply failed"))
+		mockConsensus.On("Audit", mock.Anything).Return(nil).Once()
+
+		_, err := apiV1.NewKVClient(conn).Delete(withToken("operator-token"), &apiV1.DeleteRequest{Key: "key"})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("AuditFailure", func(t *testing.T) {
+		t.Parallel()
+
+		_, mockConsensus, conn := startTestServer(t)
+		mockConsensus.On("Delete", "key").Return(nil)
+		mockConsensus.On("Audit", mock.Anything).Return(raft.ErrNoLeader).Once()
+
+		var trailer metadata.MD
+
+		_, err := apiV1.NewKVClient(conn).Delete(
+			withToken("operator-token"), &apiV1.DeleteRequest{Key: "key"}, grpc.Trailer(&trailer),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"true"}, trailer.Get(serverAPI.AuditFailedHeader))
+	})
+}
+
+func TestKVService_Watch(t *testing.T) {
+	t.Parallel()
+
+	eventTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	replay := []*raft.Event{
+		{ID: 2, Type: raft.EventMembership, Time: eventTime, Data: map[string]string{"serverId": "maf-2"}},
+		{ID: 3, Type: raft.EventKV, Time: eventTime, Data: map[string]string{
+			raft.EventDataAction: "set", raft.EventDataKey: "app/key", raft.EventDataValue: "value",
+		}},
+		{ID: 4, Type: raft.EventKV, Time: eventTime, Data: map[string]string{
+			raft.EventDataAction: "delete", raft.EventDataKey: "other",
+		}},
+		{ID: 5, Type: raft.EventKV, Time: eventTime, Data: map[string]string{
+			raft.EventDataAction: "delete", raft.EventDataKey: "app/key",
+		}},
+	}
+
+	g, mockConsensus, conn := startTestServer(t)
+	mockConsensus.On("SubscribeOnEvents", mock.Anything, uint64(1)).Return(replay).Once()
+	mockConsensus.On("UnsubscribeFromEvents", mock.Anything).Once()
+
+	stream, err := apiV1.NewKVClient(conn).Watch(
+		withToken("read-token"), &apiV1.WatchRequest{Prefix: "app/", LastEventId: 1},
+	)
+	require.NoError(t, err)
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), first.GetId())
+	assert.Equal(t, apiV1.Action_ACTION_SET, first.GetAction())
+	assert.Equal(t, "app/key", first.GetKey())
+	assert.Equal(t, "value", first.GetValue())
+	assert.Equal(t, eventTime, first.GetTime().AsTime())
+
+	second, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), second.GetId())
+	assert.Equal(t, apiV1.Action_ACTION_DELETE, second.GetAction())
+
+	close(g.streamsDone)
+
+	_, err = stream.Recv()
+	require.ErrorIs(t, err, io.EOF)
+
+	assert.Eventually(t, func() bool {
+		return mockConsensus.AssertExpectations(new(testing.T))
+	}, time.Second, 10*time.Millisecond)
+}
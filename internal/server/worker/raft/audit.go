@@ -0,0 +1,287 @@
+package raft
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	hraft "github.com/hashicorp/raft"
+)
+
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+
+	auditHeadKey      = ReservedPrefix + "audit/head"
+	auditRecordPrefix = ReservedPrefix + "audit/records/"
+	// auditMaxRecords is the retention of the audit log. It must be the same on all the servers,
+	// the FSM drops the oldest record on every append above it
+	auditMaxRecords = 10000
+
+	DefaultAuditPageLimit = 100
+	MaxAuditPageLimit     = 1000
+)
+
+// AuditEntry describes a single mutating call. Sequence number and hashes are assigned by the FSM,
+// so all the servers build exactly the same chain.
+type AuditEntry struct {
+	Time      time.Time `json:"time"`
+	Actor     string    `json:"actor"`
+	Role      string    `json:"role"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target,omitempty"`
+	RequestID string    `json:"requestId,omitempty"`
+	Outcome   string    `json:"outcome"`
+	Error     string    `json:"error,omitempty"`
+	// Forwarder is the server which forwarded the entry to the leader, empty if the leader served the call
+	Forwarder string `json:"forwarder,omitempty"`
+}
+
+type AuditRecord struct {
+	Seq uint64 `json:"seq"`
+	AuditEntry
+	PrevHash string `json:"prevHash"`
+	Hash     string `json:"hash"`
+}
+
+// AuditQuery selects the page of the audit log: the records made since the given time,
+// after the given sequence number, at most Limit of them
+type AuditQuery struct {
+	Since time.Time
+	After uint64
+	Limit int
+}
+
+// AuditLog is the verified page of the audit log. Intact is false if any record of the page is missing or modified,
+// or doesn't match its neighbours, BrokenAt points to the first such record. Next is the After of the next page,
+// zero if it's the last one.
+type AuditLog struct {
+	Records  []AuditRecord
+	Intact   bool
+	BrokenAt uint64
+	Next     uint64
+}
+
+type auditHead struct {
+	Seq  uint64 `json:"seq"`
+	Hash string `json:"hash"`
+	// First is the oldest record kept, the older ones are dropped by the retention.
+	// Heads written before the retention have no first, so zero means one
+	First uint64 `json:"first,omitempty"`
+}
+
+func (h *auditHead) first() uint64 {
+	return max(h.First, 1)
+}
+
+func auditRecordKey(seq uint64) string {
+	return fmt.Sprintf("%s%020d", auditRecordPrefix, seq)
+}
+
+func (ar *AuditRecord) computeHash() string {
+	unhashed := *ar
+	unhashed.Hash = ""
+
+	data, err := json.Marshal(&unhashed)
+	if err != nil {
+		panic("failed to marshal audit record")
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func readAuditHead(storage Storage) (*auditHead, error) {
+	head := &auditHead{}
+
+	if value, ok := storage.Get(auditHeadKey); ok {
+		if err := json.Unmarshal([]byte(value), head); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrAuditLogCorrupted, err)
+		}
+	}
+
+	return head, nil
+}
+
+// appendAuditRecord is called by the FSM only, so the appends are serialized by the raft log.
+// A corrupted head is the same on all the servers, so skipping the record keeps them consistent.
+func appendAuditRecord(storage Storage, value string) error {
+	var entry AuditEntry
+	if err := json.Unmarshal([]byte(value), &entry); err != nil {
+		panic("failed to unmarshal audit entry")
+	}
+
+	head, err := readAuditHead(storage)
+	if err != nil {
+		return err
+	}
+
+	record := &AuditRecord{Seq: head.Seq + 1, AuditEntry: entry, PrevHash: head.Hash}
+	record.Hash = record.computeHash()
+
+	recordData, err := json.Marshal(record)
+	if err != nil {
+		panic("failed to marshal audit record")
+	}
+
+	first := head.first()
+	if record.Seq-first >= auditMaxRecords {
+		storage.Delete(auditRecordKey(first))
+
+		first++
+	}
+
+	headData, err := json.Marshal(&auditHead{Seq: record.Seq, Hash: record.Hash, First: first})
+	if err != nil {
+		panic("failed to marshal audit head")
+	}
+
+	storage.Set(auditRecordKey(record.Seq), string(recordData))
+	storage.Set(auditHeadKey, string(headData))
+
+	return nil
+}
+
+func readAuditRecord(storage Storage, seq uint64) (*AuditRecord, bool) {
+	value, ok := storage.Get(auditRecordKey(seq))
+	if !ok {
+		return nil, false
+	}
+
+	var record AuditRecord
+	if err := json.Unmarshal([]byte(value), &record); err != nil {
+		return nil, false
+	}
+
+	return &record, true
+}
+
+// Audit appends the entry to the audit log. Followers forward the entry to the leader,
+// so the calls they serve and the calls giving up the leadership are recorded as well
+func (r *Raft) Audit(entry *AuditEntry) error {
+	err := r.AppendAudit(entry)
+	if !errors.Is(err, ErrNotALeader) && !errors.Is(err, hraft.ErrNotLeader) {
+		return err
+	}
+
+	return r.forwardAudit(entry)
+}
+
+// AppendAudit appends the entry to the audit log. Must be called on the leader
+func (r *Raft) AppendAudit(entry *AuditEntry) error {
+	if !r.IsLeader() {
+		return ErrNotALeader
+	}
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	entry.Time = entry.Time.UTC()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	return r.applyCommand(OpAudit, "", string(data))
+}
+
+// forwardAudit sends the entry through the peers until the leader accepts it
+func (r *Raft) forwardAudit(entry *AuditEntry) error {
+	for _, peer := range r.peers() {
+		api := r.getAPIClient(peer)
+		err := api.AuditAppend(entry)
+		api.Close()
+
+		if err == nil {
+			return nil
+		}
+
+		r.logger.Debug().Err(err).Msgf("Failed to forward audit record for %s through peer %s", entry.Action, peer)
+	}
+
+	r.logger.Warn().Msgf("No leader accepted audit record for %s", entry.Action)
+
+	return ErrNoLeader
+}
+
+// AuditLog returns the page of the records from the local storage.
+// The page is verified along with its links to the records around it, not the whole chain.
+func (r *Raft) AuditLog(query *AuditQuery) (*AuditLog, error) {
+	head, err := readAuditHead(r.storage)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("Failed to read audit log")
+
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultAuditPageLimit
+	}
+
+	limit = min(limit, MaxAuditPageLimit)
+
+	auditLog := &AuditLog{Records: make([]AuditRecord, 0), Intact: true}
+
+	broken := func(seq uint64) {
+		if !auditLog.Intact {
+			return
+		}
+
+		r.logger.Warn().Msgf("Audit log is broken at record %d", seq)
+
+		auditLog.Intact = false
+		auditLog.BrokenAt = seq
+	}
+
+	start := max(head.first(), query.After+1)
+
+	// The oldest record kept links to the dropped one, so there is nothing to check its link against
+	prevHash, anchored := "", start == 1
+
+	if start > head.first() {
+		anchored = true
+
+		if prev, ok := readAuditRecord(r.storage, start-1); ok {
+			prevHash = prev.Hash
+		}
+	}
+
+	for seq := start; seq <= head.Seq; seq++ {
+		record, ok := readAuditRecord(r.storage, seq)
+		if !ok {
+			broken(seq)
+
+			continue
+		}
+
+		if record.Seq != seq || (anchored && record.PrevHash != prevHash) || record.Hash != record.computeHash() {
+			broken(seq)
+		}
+
+		prevHash, anchored = record.Hash, true
+
+		// The record after the full page is read only to check the link, it's returned on the next page
+		if len(auditLog.Records) == limit {
+			auditLog.Next = seq - 1
+
+			break
+		}
+
+		if !record.Time.Before(query.Since) {
+			auditLog.Records = append(auditLog.Records, *record)
+		}
+	}
+
+	if auditLog.Next == 0 && head.Seq >= start && prevHash != head.Hash {
+		broken(head.Seq)
+	}
+
+	return auditLog, nil
+}
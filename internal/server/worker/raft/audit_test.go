@@ -0,0 +1,334 @@
+package raft
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	hraft "github.com/hashicorp/raft"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func appendTestAuditRecords(t *testing.T, storage Storage, start time.Time, count int) {
+	t.Helper()
+
+	for i := range count {
+		data, err := json.Marshal(&AuditEntry{
+			Time:    start.Add(time.Duration(i) * time.Minute),
+			Actor:   "admin",
+			Role:    "admin",
+			Action:  "raft.forget",
+			Target:  "maf-2",
+			Outcome: AuditOutcomeSuccess,
+		})
+		require.NoError(t, err)
+		require.NoError(t, appendAuditRecord(storage, string(data)))
+	}
+}
+
+func TestAppendAuditRecord(t *testing.T) {
+	t.Parallel()
+
+	storage := NewSafeStorage()
+	appendTestAuditRecords(t, storage, time.Now().UTC(), 2)
+
+	head, err := readAuditHead(storage)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), head.Seq)
+
+	var first, second AuditRecord
+
+	value, _ := storage.Get(auditRecordKey(1))
+	require.NoError(t, json.Unmarshal([]byte(value), &first))
+	value, _ = storage.Get(auditRecordKey(2))
+	require.NoError(t, json.Unmarshal([]byte(value), &second))
+
+	assert.Empty(t, first.PrevHash)
+	assert.Equal(t, first.Hash, second.PrevHash)
+	assert.Equal(t, second.Hash, head.Hash)
+	assert.Equal(t, first.computeHash(), first.Hash)
+}
+
+func TestAppendAuditRecordRetention(t *testing.T) {
+	t.Parallel()
+
+	storage := NewSafeStorage()
+	appendTestAuditRecords(t, storage, time.Now().UTC(), auditMaxRecords+2)
+
+	head, err := readAuditHead(storage)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(auditMaxRecords+2), head.Seq)
+	assert.Equal(t, uint64(3), head.First)
+	assert.Equal(t, auditMaxRecords+1, storage.Len(), "expected records within the retention and the head")
+
+	_, ok := storage.Get(auditRecordKey(2))
+	assert.False(t, ok)
+
+	raft := &Raft{storage: storage, logger: log.Logger}
+
+	auditLog, err := raft.AuditLog(&AuditQuery{})
+	require.NoError(t, err)
+	assert.True(t, auditLog.Intact)
+	assert.Equal(t, uint64(3), auditLog.Records[0].Seq)
+}
+
+func TestAppendAuditRecordCorruptedHead(t *testing.T) {
+	t.Parallel()
+
+	storage := NewSafeStorage()
+	storage.Set(auditHeadKey, "garbage")
+
+	err := appendAuditRecord(storage, `{"action":"raft.join"}`)
+	require.ErrorIs(t, err, ErrAuditLogCorrupted)
+
+	_, ok := storage.Get(auditRecordKey(1))
+	assert.False(t, ok)
+}
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Leader", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockRaft.On("State").Return(hraft.Leader)
+		mockApply(mockRaft)
+
+		raft := &Raft{raftInstance: mockRaft, logger: log.Logger}
+
+		require.NoError(t, raft.Audit(&AuditEntry{Action: "raft.join", Outcome: AuditOutcomeSuccess}))
+
+		commands := appliedCommands(mockRaft)
+		require.Len(t, commands, 1)
+		assert.Equal(t, OpType(OpAudit), commands[0].Op)
+
+		var entry AuditEntry
+		require.NoError(t, json.Unmarshal([]byte(commands[0].Value), &entry))
+		assert.Equal(t, "raft.join", entry.Action)
+		assert.False(t, entry.Time.IsZero())
+	})
+
+	t.Run("ForwardedToLeader", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockRaft.On("State").Return(hraft.Follower)
+
+		entry := &AuditEntry{Action: "raft.leave", Outcome: AuditOutcomeSuccess}
+
+		follower, leader := new(MockAPIClient), new(MockAPIClient)
+		follower.On("AuditAppend", entry).Return(errors.New("not a leader")).Once()
+		follower.On("Close").Return(nil)
+		leader.On("AuditAppend", entry).Return(nil).Once()
+		leader.On("Close").Return(nil)
+
+		clients := map[string]APIClient{"http://node2": follower, "http://node3": leader}
+		raft := &Raft{
+			config:       &Config{Peers: []string{"http://node2", "http://node3"}},
+			raftInstance: mockRaft,
+			logger:       log.Logger,
+			getAPIClient: func(peer string) APIClient {
+				return clients[peer]
+			},
+		}
+
+		require.NoError(t, raft.Audit(entry))
+		mockRaft.AssertNotCalled(t, "Apply")
+		follower.AssertExpectations(t)
+		leader.AssertExpectations(t)
+	})
+
+	t.Run("NoLeader", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockRaft.On("State").Return(hraft.Follower)
+
+		mockAPIClient := new(MockAPIClient)
+		mockAPIClient.On("AuditAppend", mock.Anything).Return(errors.New("connection refused"))
+		mockAPIClient.On("Close").Return(nil)
+
+		raft := &Raft{
+			config:       &Config{Peers: []string{"http://node2"}},
+			raftInstance: mockRaft,
+			logger:       log.Logger,
+			getAPIClient: func(_ string) APIClient {
+				return mockAPIClient
+			},
+		}
+
+		require.ErrorIs(t, raft.Audit(&AuditEntry{Action: "raft.join"}), ErrNoLeader)
+	})
+}
+
+func TestAppendAudit(t *testing.T) {
+	t.Parallel()
+
+	mockRaft := new(MockHRaft)
+	mockRaft.On("State").Return(hraft.Follower)
+
+	raft := &Raft{raftInstance: mockRaft, logger: log.Logger}
+
+	require.ErrorIs(t, raft.AppendAudit(&AuditEntry{Action: "raft.join"}), ErrNotALeader)
+	mockRaft.AssertNotCalled(t, "Apply")
+}
+
+func TestAuditLog(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		raft := &Raft{storage: NewSafeStorage(), logger: log.Logger}
+
+		auditLog, err := raft.AuditLog(&AuditQuery{})
+		require.NoError(t, err)
+		assert.True(t, auditLog.Intact)
+		assert.Empty(t, auditLog.Records)
+	})
+
+	t.Run("Since", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewSafeStorage()
+		appendTestAuditRecords(t, storage, start, 3)
+		raft := &Raft{storage: storage, logger: log.Logger}
+
+		auditLog, err := raft.AuditLog(&AuditQuery{Since: start.Add(time.Minute)})
+		require.NoError(t, err)
+		assert.True(t, auditLog.Intact)
+		require.Len(t, auditLog.Records, 2)
+		assert.Equal(t, uint64(2), auditLog.Records[0].Seq)
+		assert.Equal(t, uint64(3), auditLog.Records[1].Seq)
+	})
+
+	t.Run("Modified", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewSafeStorage()
+		appendTestAuditRecords(t, storage, start, 3)
+
+		var record AuditRecord
+
+		value, _ := storage.Get(auditRecordKey(2))
+		require.NoError(t, json.Unmarshal([]byte(value), &record))
+		record.Actor = "someone-else"
+		data, _ := json.Marshal(&record)
+		storage.Set(auditRecordKey(2), string(data))
+
+		raft := &Raft{storage: storage, logger: log.Logger}
+
+		auditLog, err := raft.AuditLog(&AuditQuery{})
+		require.NoError(t, err)
+		assert.False(t, auditLog.Intact)
+		assert.Equal(t, uint64(2), auditLog.BrokenAt)
+		assert.Len(t, auditLog.Records, 3)
+	})
+
+	t.Run("Gap", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewSafeStorage()
+		appendTestAuditRecords(t, storage, start, 3)
+		storage.Delete(auditRecordKey(2))
+
+		raft := &Raft{storage: storage, logger: log.Logger}
+
+		auditLog, err := raft.AuditLog(&AuditQuery{})
+		require.NoError(t, err)
+		assert.False(t, auditLog.Intact)
+		assert.Equal(t, uint64(2), auditLog.BrokenAt)
+		assert.Len(t, auditLog.Records, 2)
+	})
+
+	t.Run("TruncatedTail", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewSafeStorage()
+		appendTestAuditRecords(t, storage, start, 3)
+
+		head, err := readAuditHead(storage)
+		require.NoError(t, err)
+
+		storage.Delete(auditRecordKey(3))
+		head.Seq = 2
+		data, _ := json.Marshal(head)
+		storage.Set(auditHeadKey, string(data))
+
+		raft := &Raft{storage: storage, logger: log.Logger}
+
+		auditLog, err := raft.AuditLog(&AuditQuery{})
+		require.NoError(t, err)
+		assert.False(t, auditLog.Intact)
+		assert.Equal(t, uint64(2), auditLog.BrokenAt)
+	})
+
+	t.Run("Pages", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewSafeStorage()
+		appendTestAuditRecords(t, storage, start, 5)
+		raft := &Raft{storage: storage, logger: log.Logger}
+
+		auditLog, err := raft.AuditLog(&AuditQuery{Limit: 2})
+		require.NoError(t, err)
+		assert.True(t, auditLog.Intact)
+		require.Len(t, auditLog.Records, 2)
+		assert.Equal(t, uint64(2), auditLog.Next)
+
+		auditLog, err = raft.AuditLog(&AuditQuery{After: auditLog.Next, Limit: 2})
+		require.NoError(t, err)
+		assert.True(t, auditLog.Intact)
+		require.Len(t, auditLog.Records, 2)
+		assert.Equal(t, uint64(3), auditLog.Records[0].Seq)
+		assert.Equal(t, uint64(4), auditLog.Next)
+
+		auditLog, err = raft.AuditLog(&AuditQuery{After: auditLog.Next, Limit: 2})
+		require.NoError(t, err)
+		assert.True(t, auditLog.Intact)
+		require.Len(t, auditLog.Records, 1)
+		assert.Zero(t, auditLog.Next)
+	})
+
+	t.Run("PageLinkBroken", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewSafeStorage()
+		appendTestAuditRecords(t, storage, start, 3)
+
+		// The record after the page is rewritten with the valid hash, but it no longer links to the page
+		record, ok := readAuditRecord(storage, 3)
+		require.True(t, ok)
+		record.PrevHash = "forged"
+		record.Hash = record.computeHash()
+		data, _ := json.Marshal(record)
+		storage.Set(auditRecordKey(3), string(data))
+
+		raft := &Raft{storage: storage, logger: log.Logger}
+
+		auditLog, err := raft.AuditLog(&AuditQuery{Limit: 2})
+		require.NoError(t, err)
+		assert.False(t, auditLog.Intact)
+		assert.Equal(t, uint64(3), auditLog.BrokenAt)
+		assert.Len(t, auditLog.Records, 2)
+	})
+
+	t.Run("CorruptedHead", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewSafeStorage()
+		storage.Set(auditHeadKey, "garbage")
+
+		raft := &Raft{storage: storage, logger: log.Logger}
+
+		_, err := raft.AuditLog(&AuditQuery{})
+		require.ErrorIs(t, err, ErrAuditLogCorrupted)
+	})
+}
@@ -0,0 +1,466 @@
+package raft
+
+import (
+	"encoding/json"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	hraft "github.com/hashicorp/raft"
+)
+
+const (
+	autopilotInterval        = 5 * time.Second
+	autopilotObservationsCap = 64
+	// Servers which asked to join as voters while autopilot is enabled are added as non-voters first
+	// and marked with this key in the replicated storage, so any leader can promote them later.
+	autopilotStagingPrefix = ReservedPrefix + "autopilot/staging/"
+)
+
+type AutopilotConfig struct {
+	Enabled                 bool
+	CleanupDeadServers      bool
+	DeadServerThreshold     time.Duration
+	ServerStabilizationTime time.Duration
+	MaxTrailingLogs         uint64
+}
+
+// autopilot keeps the leader view of the servers health.
+type autopilot struct {
+	mu           sync.Mutex
+	config       *AutopilotConfig
+	observations chan hraft.Observation
+	observer     *hraft.Observer
+	failingSince map[hraft.ServerID]time.Time
+	healthySince map[hraft.ServerID]time.Time
+}
+
+type serverHealth struct {
+	failingSince time.Time
+	lastIndex    uint64
+	statsKnown   bool
+}
+
+type peerInfo struct {
+	ID        string `json:"id"`
+	Stats     Stats  `json:"stats"`
+	lastIndex uint64
+}
+
+func (r *Raft) autopilotEnabled() bool {
+	return r.autopilot != nil
+}
+
+func (r *Raft) initAutopilot() {
+	if r.config.Autopilot == nil || !r.config.Autopilot.Enabled {
+		r.logger.Info().Msg("Autopilot is disabled")
+
+		return
+	}
+
+	r.logger.Trace().Msg("Initializing autopilot")
+
+	observations := make(chan hraft.Observation, autopilotObservationsCap)
+	r.autopilot = &autopilot{
+		config:       r.config.Autopilot,
+		observations: observations,
+		observer: hraft.NewObserver(observations, false, func(o *hraft.Observation) bool {
+			switch o.Data.(type) {
+			case hraft.FailedHeartbeatObservation, hraft.ResumedHeartbeatObservation:
+				return true
+			default:
+				return false
+			}
+		}),
+		failingSince: make(map[hraft.ServerID]time.Time),
+		healthySince: make(map[hraft.ServerID]time.Time),
+	}
+	r.raftInstance.RegisterObserver(r.autopilot.observer)
+}
+
+func (r *Raft) runAutopilot() {
+	if !r.autopilotEnabled() {
+		return
+	}
+
+	r.logger.Info().Msg("Running autopilot")
+
+	go func() {
+		ticker := time.NewTicker(autopilotInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case o := <-r.autopilot.observations:
+				r.observeHeartbeat(o)
+			case <-ticker.C:
+				if r.IsLeader() {
+					r.autopilotTick(time.Now())
+				} else {
+					r.autopilot.reset()
+				}
+			case <-r.done:
+				r.logger.Info().Msg("Stopping autopilot")
+				r.raftInstance.DeregisterObserver(r.autopilot.observer)
+
+				return
+			}
+		}
+	}()
+}
+
+func (a *autopilot) reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	clear(a.failingSince)
+	clear(a.healthySince)
+}
+
+// forget drops the health of the removed server
+func (a *autopilot) forget(serverID hraft.ServerID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.failingSince, serverID)
+	delete(a.healthySince, serverID)
+}
+
+func (a *autopilot) isFailing(serverID hraft.ServerID) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, ok := a.failingSince[serverID]
+
+	return ok
+}
+
+func (r *Raft) observeHeartbeat(o hraft.Observation) {
+	r.autopilot.mu.Lock()
+	defer r.autopilot.mu.Unlock()
+
+	switch data := o.Data.(type) {
+	case hraft.FailedHeartbeatObservation:
+		if _, ok := r.autopilot.failingSince[data.PeerID]; ok {
+			return
+		}
+
+		since := data.LastContact
+		if since.IsZero() {
+			since = time.Now()
+		}
+
+		r.logger.Warn().Msgf("Autopilot: server %s stopped responding to heartbeats", data.PeerID)
+		r.autopilot.failingSince[data.PeerID] = since
+	case hraft.ResumedHeartbeatObservation:
+		r.logger.Info().Msgf("Autopilot: server %s resumed responding to heartbeats", data.PeerID)
+		delete(r.autopilot.failingSince, data.PeerID)
+	}
+}
+
+// autopilotTick polls the servers and decides on their removal and promotion under the lock,
+// while the slow calls, e.g. to the peers and to the raft log, are made without it
+func (r *Raft) autopilotTick(now time.Time) {
+	cfgFuture := r.raftInstance.GetConfiguration()
+	if err := cfgFuture.Error(); err != nil {
+		r.logger.Error().Err(err).Msg("Autopilot: failed to get raft configuration")
+
+		return
+	}
+
+	servers := cfgFuture.Configuration().Servers
+	indexes := r.pollServerIndexes(servers)
+
+	leaderIndex, err := strconv.ParseUint(r.raftInstance.Stats()["last_log_index"], 10, 64)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("Autopilot: failed to get leader last log index")
+
+		return
+	}
+
+	r.autopilot.mu.Lock()
+	health := r.collectHealth(servers, indexes)
+
+	var dead []hraft.Server
+	if r.autopilot.config.CleanupDeadServers {
+		dead = r.deadServers(servers, health, now)
+	}
+
+	stable := r.stableServers(servers, health, leaderIndex, now)
+	r.autopilot.mu.Unlock()
+
+	r.removeDeadServers(dead, health)
+	r.promoteStableServers(stable)
+}
+
+func (r *Raft) collectHealth(
+	servers []hraft.Server,
+	indexes map[hraft.ServerID]uint64,
+) map[hraft.ServerID]*serverHealth {
+	health := make(map[hraft.ServerID]*serverHealth, len(servers))
+
+	for _, srv := range servers {
+		if srv.ID == hraft.ServerID(r.config.NodeID) {
+			continue
+		}
+
+		h := &serverHealth{failingSince: r.autopilot.failingSince[srv.ID]}
+		h.lastIndex, h.statsKnown = indexes[srv.ID]
+		health[srv.ID] = h
+	}
+
+	return health
+}
+
+// pollServerIndexes asks the servers of the raft configuration for their raft stats, so the leader can find out
+// how far behind the followers are. The peers report their IDs, the servers missing from the peers list are
+// reached by their raft host. Unreachable servers are skipped, heartbeats are the source of truth for their liveness.
+func (r *Raft) pollServerIndexes(servers []hraft.Server) map[hraft.ServerID]uint64 {
+	indexes := make(map[hraft.ServerID]uint64, len(servers))
+
+	for _, peer := range r.peers() {
+		if info, ok := r.pollPeerInfo(peer); ok {
+			indexes[hraft.ServerID(info.ID)] = info.lastIndex
+		}
+	}
+
+	for _, srv := range servers {
+		if _, ok := indexes[srv.ID]; ok || srv.ID == hraft.ServerID(r.config.NodeID) {
+			continue
+		}
+
+		addr := r.serverAPIAddr(srv)
+		if addr == "" {
+			continue
+		}
+
+		if info, ok := r.pollPeerInfo(addr); ok && info.ID == string(srv.ID) {
+			indexes[srv.ID] = info.lastIndex
+		}
+	}
+
+	return indexes
+}
+
+func (r *Raft) pollPeerInfo(peer string) (*peerInfo, bool) {
+	api := r.getAPIClient(peer)
+	data, err := api.RaftInfo(true)
+	api.Close()
+
+	if err != nil {
+		r.logger.Debug().Err(err).Msgf("Autopilot: failed to get stats from peer %s", peer)
+
+		return nil, false
+	}
+
+	info, err := decodePeerInfo(data)
+	if err != nil {
+		r.logger.Debug().Err(err).Msgf("Autopilot: failed to decode stats from peer %s", peer)
+
+		return nil, false
+	}
+
+	info.lastIndex, err = strconv.ParseUint(info.Stats["last_log_index"], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	return info, true
+}
+
+// serverAPIAddr assumes the server serves the API with the same scheme and port as this one, on its raft host
+func (r *Raft) serverAPIAddr(srv hraft.Server) string {
+	if r.config.APIAdvertise == "" {
+		return ""
+	}
+
+	apiURL, err := url.Parse(r.config.APIAdvertise)
+	if err != nil || apiURL.Port() == "" {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(string(srv.Address))
+	if err != nil {
+		return ""
+	}
+
+	apiURL.Host = net.JoinHostPort(host, apiURL.Port())
+
+	return apiURL.String()
+}
+
+func decodePeerInfo(data any) (*peerInfo, error) {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var info peerInfo
+	if err := json.Unmarshal(dataBytes, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// deadServers returns the servers failing longer than the threshold, if removing them keeps the quorum
+func (r *Raft) deadServers(
+	servers []hraft.Server,
+	health map[hraft.ServerID]*serverHealth,
+	now time.Time,
+) []hraft.Server {
+	voters := 0
+	deadVoters := 0
+	dead := make([]hraft.Server, 0)
+
+	for _, srv := range servers {
+		if srv.Suffrage == hraft.Voter {
+			voters++
+		}
+
+		h, ok := health[srv.ID]
+		if !ok || h.failingSince.IsZero() || now.Sub(h.failingSince) < r.autopilot.config.DeadServerThreshold {
+			continue
+		}
+
+		if srv.Suffrage == hraft.Voter {
+			deadVoters++
+		}
+
+		dead = append(dead, srv)
+	}
+
+	// Removing the failed voters must leave the healthy ones in the majority,
+	// otherwise the cluster is already in trouble and needs an operator
+	if deadVoters > 0 && deadVoters*2 >= voters {
+		r.logger.Warn().Msgf(
+			"Autopilot: %d of %d voters are dead, refusing to remove them as it may break the quorum",
+			deadVoters,
+			voters,
+		)
+
+		return nil
+	}
+
+	return dead
+}
+
+func (r *Raft) removeDeadServers(dead []hraft.Server, health map[hraft.ServerID]*serverHealth) {
+	for _, srv := range dead {
+		r.logger.Warn().Msgf(
+			"Autopilot: removing server %s at %s, failed since %s",
+			srv.ID,
+			srv.Address,
+			health[srv.ID].failingSince.Format(time.RFC3339),
+		)
+
+		if err := r.raftInstance.RemoveServer(srv.ID, 0, 0).Error(); err != nil {
+			r.logger.Err(err).Msgf("Autopilot: failed to remove server %s", srv.ID)
+
+			continue
+		}
+
+		r.publishMembership(membershipActionRemove, srv)
+		r.autopilot.forget(srv.ID)
+
+		if err := r.unstage(srv.ID); err != nil {
+			r.logger.Err(err).Msgf("Autopilot: failed to clear staging mark of server %s", srv.ID)
+		}
+	}
+}
+
+// stableServers tracks how long the servers are healthy and returns the staged ones stable long enough to promote
+func (r *Raft) stableServers(
+	servers []hraft.Server,
+	health map[hraft.ServerID]*serverHealth,
+	leaderIndex uint64,
+	now time.Time,
+) []hraft.Server {
+	stabilization := r.autopilot.config.ServerStabilizationTime
+	stable := make([]hraft.Server, 0)
+
+	for _, srv := range servers {
+		h, ok := health[srv.ID]
+		if !ok {
+			continue
+		}
+
+		caughtUp := h.statsKnown && h.lastIndex+r.autopilot.config.MaxTrailingLogs >= leaderIndex
+		if !h.failingSince.IsZero() || !caughtUp {
+			delete(r.autopilot.healthySince, srv.ID)
+
+			continue
+		}
+
+		if _, ok := r.autopilot.healthySince[srv.ID]; !ok {
+			r.autopilot.healthySince[srv.ID] = now
+		}
+
+		if srv.Suffrage == hraft.Voter {
+			continue
+		}
+
+		stagedAt, staged := r.stagedAt(srv.ID)
+		if !staged || now.Sub(stagedAt) < stabilization || now.Sub(r.autopilot.healthySince[srv.ID]) < stabilization {
+			continue
+		}
+
+		stable = append(stable, srv)
+	}
+
+	return stable
+}
+
+func (r *Raft) promoteStableServers(stable []hraft.Server) {
+	for _, srv := range stable {
+		r.logger.Info().Msgf("Autopilot: server %s is stable and caught up, promoting to voter", srv.ID)
+
+		if err := r.raftInstance.AddVoter(srv.ID, srv.Address, 0, 0).Error(); err != nil {
+			r.logger.Err(err).Msgf("Autopilot: failed to promote server %s", srv.ID)
+
+			continue
+		}
+
+		r.publishMembership(membershipActionPromote, hraft.Server{ID: srv.ID, Address: srv.Address, Suffrage: hraft.Voter})
+
+		if err := r.unstage(srv.ID); err != nil {
+			r.logger.Err(err).Msgf("Autopilot: failed to clear staging mark of server %s", srv.ID)
+		}
+	}
+}
+
+func (r *Raft) stage(serverID hraft.ServerID) error {
+	r.logger.Info().Msgf("Autopilot: staging %s, it will be promoted to voter once stable", serverID)
+
+	return r.applyCommand(OpSet, autopilotStagingPrefix+string(serverID), time.Now().UTC().Format(time.RFC3339))
+}
+
+func (r *Raft) unstage(serverID hraft.ServerID) error {
+	if !r.autopilotEnabled() {
+		return nil
+	}
+
+	if _, ok := r.storage.Get(autopilotStagingPrefix + string(serverID)); !ok {
+		return nil
+	}
+
+	return r.applyCommand(OpDelete, autopilotStagingPrefix+string(serverID), "")
+}
+
+func (r *Raft) stagedAt(serverID hraft.ServerID) (time.Time, bool) {
+	value, ok := r.storage.Get(autopilotStagingPrefix + string(serverID))
+	if !ok {
+		return time.Time{}, false
+	}
+
+	stagedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		r.logger.Warn().Err(err).Msgf("Autopilot: invalid staging mark of server %s", serverID)
+
+		return time.Time{}, false
+	}
+
+	return stagedAt, true
+}
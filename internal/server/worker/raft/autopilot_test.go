@@ -0,0 +1,298 @@
+package raft
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	hraft "github.com/hashicorp/raft"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testAutopilotConfig = &AutopilotConfig{
+	Enabled:                 true,
+	CleanupDeadServers:      true,
+	DeadServerThreshold:     time.Minute,
+	ServerStabilizationTime: 10 * time.Second,
+	MaxTrailingLogs:         10,
+}
+
+func newAutopilotRaft(mockRaft *MockHRaft, peers map[string]any) *Raft {
+	peerURLs := make([]string, 0, len(peers))
+	for peer := range peers {
+		peerURLs = append(peerURLs, peer)
+	}
+
+	return &Raft{
+		config:       &Config{NodeID: "node1", Peers: peerURLs, Autopilot: testAutopilotConfig},
+		raftInstance: mockRaft,
+		storage:      NewSafeStorage(),
+		logger:       log.Logger,
+		autopilot: &autopilot{
+			config:       testAutopilotConfig,
+			failingSince: make(map[hraft.ServerID]time.Time),
+			healthySince: make(map[hraft.ServerID]time.Time),
+		},
+		getAPIClient: func(peer string) APIClient {
+			mockAPIClient := new(MockAPIClient)
+			mockAPIClient.On("Close").Return(nil)
+
+			if info, ok := peers[peer].(error); ok {
+				mockAPIClient.On("RaftInfo", true).Return(nil, info)
+			} else {
+				mockAPIClient.On("RaftInfo", true).Return(peers[peer], nil)
+			}
+
+			return mockAPIClient
+		},
+	}
+}
+
+func peerStats(id, lastIndex string) map[string]any {
+	return map[string]any{"id": id, "stats": map[string]any{"last_log_index": lastIndex}}
+}
+
+func mockConfiguration(mockRaft *MockHRaft, servers ...hraft.Server) {
+	mockConfigFuture := new(MockConfigurationFuture)
+	mockConfigFuture.On("Error").Return(nil)
+	mockConfigFuture.On("Configuration").Return(hraft.Configuration{Servers: servers})
+	mockRaft.On("GetConfiguration").Return(mockConfigFuture)
+}
+
+func TestObserveHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	raft := newAutopilotRaft(new(MockHRaft), nil)
+	lastContact := time.Now().Add(-time.Minute)
+
+	raft.observeHeartbeat(hraft.Observation{
+		Data: hraft.FailedHeartbeatObservation{PeerID: "node2", LastContact: lastContact},
+	})
+	raft.observeHeartbeat(hraft.Observation{
+		Data: hraft.FailedHeartbeatObservation{PeerID: "node2", LastContact: time.Now()},
+	})
+	assert.Equal(t, lastContact, raft.autopilot.failingSince["node2"], "expected first failure to be kept")
+
+	raft.observeHeartbeat(hraft.Observation{Data: hraft.ResumedHeartbeatObservation{PeerID: "node2"}})
+	assert.NotContains(t, raft.autopilot.failingSince, hraft.ServerID("node2"))
+}
+
+func TestAutopilotCleanupDeadServers(t *testing.T) {
+	t.Parallel()
+
+	servers := []hraft.Server{
+		{ID: "node1", Address: "127.0.0.1:7081", Suffrage: hraft.Voter},
+		{ID: "node2", Address: "127.0.0.1:7082", Suffrage: hraft.Voter},
+		{ID: "node3", Address: "127.0.0.1:7083", Suffrage: hraft.Voter},
+	}
+
+	t.Run("RemovesDeadServer", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockIndexFuture := new(MockIndexFuture)
+		mockIndexFuture.On("Error").Return(nil)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On("Stats").Return(map[string]string{"last_log_index": "100"})
+		mockRaft.On("RemoveServer", hraft.ServerID("node3"), uint64(0), time.Duration(0)).Return(mockIndexFuture)
+
+		now := time.Now()
+		raft := newAutopilotRaft(mockRaft, map[string]any{
+			"http://node2": peerStats("node2", "100"),
+			"http://node3": errors.New("connection refused"),
+		})
+		raft.autopilot.failingSince["node3"] = now.Add(-2 * time.Minute)
+
+		raft.autopilotTick(now)
+		mockRaft.AssertExpectations(t)
+		assert.NotContains(t, raft.autopilot.failingSince, hraft.ServerID("node3"))
+	})
+
+	t.Run("KeepsRecentlyFailedServer", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On("Stats").Return(map[string]string{"last_log_index": "100"})
+
+		now := time.Now()
+		raft := newAutopilotRaft(mockRaft, nil)
+		raft.autopilot.failingSince["node3"] = now.Add(-30 * time.Second)
+
+		raft.autopilotTick(now)
+		mockRaft.AssertNotCalled(t, "RemoveServer", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("RefusesToBreakQuorum", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On("Stats").Return(map[string]string{"last_log_index": "100"})
+
+		now := time.Now()
+		raft := newAutopilotRaft(mockRaft, nil)
+		raft.autopilot.failingSince["node2"] = now.Add(-2 * time.Minute)
+		raft.autopilot.failingSince["node3"] = now.Add(-2 * time.Minute)
+
+		raft.autopilotTick(now)
+		mockRaft.AssertNotCalled(t, "RemoveServer", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAutopilotPromoteStableServers(t *testing.T) {
+	t.Parallel()
+
+	servers := []hraft.Server{
+		{ID: "node1", Address: "127.0.0.1:7081", Suffrage: hraft.Voter},
+		{ID: "node2", Address: "127.0.0.1:7082", Suffrage: hraft.Nonvoter},
+	}
+
+	t.Run("PromotesCaughtUpServer", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockIndexFuture := new(MockIndexFuture)
+		mockApplyFuture := new(MockApplyFuture)
+		mockIndexFuture.On("Error").Return(nil)
+		mockApplyFuture.On("Error").Return(nil)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On("Stats").Return(map[string]string{"last_log_index": "100"})
+		mockRaft.On("Apply", mock.Anything, cmdTimeout).Return(mockApplyFuture)
+		mockRaft.On(
+			"AddVoter",
+			hraft.ServerID("node2"),
+			hraft.ServerAddress("127.0.0.1:7082"),
+			uint64(0),
+			time.Duration(0),
+		).Return(mockIndexFuture)
+
+		now := time.Now()
+		raft := newAutopilotRaft(mockRaft, map[string]any{"http://node2": peerStats("node2", "95")})
+		raft.storage.Set(autopilotStagingPrefix+"node2", now.Add(-time.Minute).Format(time.RFC3339))
+		raft.autopilot.healthySince["node2"] = now.Add(-time.Minute)
+
+		raft.autopilotTick(now)
+		mockRaft.AssertExpectations(t)
+	})
+
+	t.Run("PromotesServerMissingFromPeers", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockIndexFuture := new(MockIndexFuture)
+		mockApplyFuture := new(MockApplyFuture)
+		mockIndexFuture.On("Error").Return(nil)
+		mockApplyFuture.On("Error").Return(nil)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On("Stats").Return(map[string]string{"last_log_index": "100"})
+		mockRaft.On("Apply", mock.Anything, cmdTimeout).Return(mockApplyFuture)
+		mockRaft.On(
+			"AddVoter",
+			hraft.ServerID("node2"),
+			hraft.ServerAddress("127.0.0.1:7082"),
+			uint64(0),
+			time.Duration(0),
+		).Return(mockIndexFuture)
+
+		now := time.Now()
+		raft := newAutopilotRaft(mockRaft, map[string]any{"https://127.0.0.1:7080": peerStats("node2", "95")})
+		raft.config.Peers = nil
+		raft.config.APIAdvertise = "https://10.0.0.1:7080"
+		raft.storage.Set(autopilotStagingPrefix+"node2", now.Add(-time.Minute).Format(time.RFC3339))
+		raft.autopilot.healthySince["node2"] = now.Add(-time.Minute)
+
+		raft.autopilotTick(now)
+		mockRaft.AssertExpectations(t)
+	})
+
+	t.Run("WaitsForStabilization", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On("Stats").Return(map[string]string{"last_log_index": "100"})
+
+		now := time.Now()
+		raft := newAutopilotRaft(mockRaft, map[string]any{"http://node2": peerStats("node2", "100")})
+		raft.storage.Set(autopilotStagingPrefix+"node2", now.Add(-time.Minute).Format(time.RFC3339))
+
+		raft.autopilotTick(now)
+		mockRaft.AssertNotCalled(t, "AddVoter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		assert.Equal(t, now, raft.autopilot.healthySince["node2"])
+	})
+
+	t.Run("SkipsLaggingServer", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On("Stats").Return(map[string]string{"last_log_index": "100"})
+
+		now := time.Now()
+		raft := newAutopilotRaft(mockRaft, map[string]any{"http://node2": peerStats("node2", "50")})
+		raft.storage.Set(autopilotStagingPrefix+"node2", now.Add(-time.Minute).Format(time.RFC3339))
+		raft.autopilot.healthySince["node2"] = now.Add(-time.Minute)
+
+		raft.autopilotTick(now)
+		mockRaft.AssertNotCalled(t, "AddVoter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		assert.NotContains(t, raft.autopilot.healthySince, hraft.ServerID("node2"))
+	})
+
+	t.Run("SkipsExplicitNonvoter", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On("Stats").Return(map[string]string{"last_log_index": "100"})
+
+		now := time.Now()
+		raft := newAutopilotRaft(mockRaft, map[string]any{"http://node2": peerStats("node2", "100")})
+		raft.autopilot.healthySince["node2"] = now.Add(-time.Minute)
+
+		raft.autopilotTick(now)
+		mockRaft.AssertNotCalled(t, "AddVoter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestServerAPIAddr(t *testing.T) {
+	t.Parallel()
+
+	srv := hraft.Server{ID: "node2", Address: "10.0.0.2:7081"}
+
+	raft := &Raft{config: &Config{APIAdvertise: "https://10.0.0.1:7080"}}
+	assert.Equal(t, "https://10.0.0.2:7080", raft.serverAPIAddr(srv))
+
+	raft.config.APIAdvertise = ""
+	assert.Empty(t, raft.serverAPIAddr(srv), "expected no address without the advertised API address")
+}
+
+func TestJoinWithAutopilot(t *testing.T) {
+	t.Parallel()
+
+	mockRaft := new(MockHRaft)
+	mockIndexFuture := new(MockIndexFuture)
+	mockApplyFuture := new(MockApplyFuture)
+	mockIndexFuture.On("Error").Return(nil)
+	mockApplyFuture.On("Error").Return(nil)
+	mockConfiguration(mockRaft)
+	mockRaft.On("State").Return(hraft.Leader)
+	mockRaft.On("Apply", mock.Anything, cmdTimeout).Return(mockApplyFuture)
+	mockRaft.On(
+		"AddNonvoter",
+		hraft.ServerID("node2"),
+		hraft.ServerAddress("127.0.0.1:8081"),
+		uint64(0),
+		time.Duration(0),
+	).Return(mockIndexFuture)
+
+	raft := newAutopilotRaft(mockRaft, nil)
+	setJoinToken(raft.storage, testJoinToken, time.Now().Add(time.Hour))
+
+	require.NoError(t, raft.Join("node2", "127.0.0.1:8081", false, testJoinToken))
+	mockRaft.AssertExpectations(t)
+	mockRaft.AssertNotCalled(t, "AddVoter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -12,14 +12,16 @@ var ErrInvalidOpType = errors.New("invalid operation type")
 const (
 	OpSet = iota
 	OpDelete
+	// OpAudit appends the audit entry from the value to the hash-chained audit log
+	OpAudit
 )
 
 func (op OpType) String() string {
-	if op < OpSet || op > OpDelete {
+	if op < OpSet || op > OpAudit {
 		return ""
 	}
 
-	return [...]string{"set", "delete"}[op]
+	return [...]string{"set", "delete", "audit"}[op]
 }
 
 type Command struct {
@@ -37,7 +39,7 @@ func makeCommand(op OpType, key, value string) *Command {
 }
 
 func (c *Command) MarshalJSON() ([]byte, error) {
-	if c.Op < OpSet || c.Op > OpDelete {
+	if c.Op < OpSet || c.Op > OpAudit {
 		return nil, ErrInvalidOpType
 	}
 
@@ -16,6 +16,7 @@ func TestOpTypeString(t *testing.T) {
 	}{
 		{OpSet, "set"},
 		{OpDelete, "delete"},
+		{OpAudit, "audit"},
 		{OpType(999), ""}, // Invalid OpType
 	}
 
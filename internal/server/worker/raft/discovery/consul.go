@@ -0,0 +1,86 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"resty.dev/v3"
+)
+
+const (
+	consulTimeout     = 5 * time.Second
+	consulTokenHeader = "X-Consul-Token"
+	consulCatalogPath = "/v1/catalog/service/"
+)
+
+type ConsulConfig struct {
+	// Address of the Consul HTTP API, e.g. http://127.0.0.1:8500
+	Addr    string
+	Service string
+	Tag     string
+	Token   string
+}
+
+type consulCatalogService struct {
+	Address        string `json:"Address"`
+	ServiceAddress string `json:"ServiceAddress"`
+	ServicePort    uint16 `json:"ServicePort"`
+}
+
+type Consul struct {
+	config  *ConsulConfig
+	scheme  string
+	rclient *resty.Client
+}
+
+func NewConsul(config *ConsulConfig, scheme string) *Consul {
+	rclient := resty.New().SetTimeout(consulTimeout).SetBaseURL(config.Addr)
+	if config.Token != "" {
+		rclient.SetHeader(consulTokenHeader, config.Token)
+	}
+
+	return &Consul{
+		config:  config,
+		scheme:  scheme,
+		rclient: rclient,
+	}
+}
+
+// Discover lists the instances of the service registered in the Consul catalog.
+// Service address is preferred, node address is used if the service doesn't define one.
+func (c *Consul) Discover(ctx context.Context) ([]string, error) {
+	req := c.rclient.R().
+		SetContext(ctx).
+		SetResult(&[]consulCatalogService{})
+	if c.config.Tag != "" {
+		req.SetQueryParam("tag", c.config.Tag)
+	}
+
+	res, err := req.Get(consulCatalogPath + c.config.Service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consul catalog: %w", err)
+	}
+
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to query consul catalog: %w: %d", ErrBadStatusCode, res.StatusCode())
+	}
+
+	services, ok := res.Result().(*[]consulCatalogService)
+	if !ok || len(*services) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoPeersFound, c.config.Service)
+	}
+
+	peers := make([]string, 0, len(*services))
+
+	for _, service := range *services {
+		host := service.ServiceAddress
+		if host == "" {
+			host = service.Address
+		}
+
+		peers = append(peers, makePeerURL(c.scheme, host, service.ServicePort))
+	}
+
+	return peers, nil
+}
@@ -0,0 +1,67 @@
+package discovery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsulDiscover(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/catalog/service/maf", r.URL.Path)
+			assert.Equal(t, "server", r.URL.Query().Get("tag"))
+			assert.Equal(t, "secret", r.Header.Get(consulTokenHeader))
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[
+				{"Address": "10.0.0.1", "ServiceAddress": "", "ServicePort": 7080},
+				{"Address": "10.0.0.2", "ServiceAddress": "10.1.0.2", "ServicePort": 7081}
+			]`))
+		}))
+		defer server.Close()
+
+		consul := NewConsul(&ConsulConfig{Addr: server.URL, Service: "maf", Tag: "server", Token: "secret"}, "http")
+
+		peers, err := consul.Discover(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"http://10.0.0.1:7080", "http://10.1.0.2:7081"}, peers)
+	})
+
+	t.Run("NoInstances", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[]`))
+		}))
+		defer server.Close()
+
+		consul := NewConsul(&ConsulConfig{Addr: server.URL, Service: "maf"}, "http")
+
+		_, err := consul.Discover(context.Background())
+		require.ErrorIs(t, err, ErrNoPeersFound)
+	})
+
+	t.Run("BadStatusCode", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		consul := NewConsul(&ConsulConfig{Addr: server.URL, Service: "maf"}, "http")
+
+		_, err := consul.Discover(context.Background())
+		require.ErrorIs(t, err, ErrBadStatusCode)
+	})
+}
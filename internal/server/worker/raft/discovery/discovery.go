@@ -0,0 +1,50 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	ProviderStatic = "static"
+	ProviderDNS    = "dns"
+	ProviderConsul = "consul"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown discovery provider")
+	ErrNoPeersFound    = errors.New("no peers found")
+	ErrBadStatusCode   = errors.New("bad status code")
+)
+
+// Provider resolves the API addresses of the maf servers. It's called on every retry,
+// so the result reflects the current state of the server tier.
+type Provider interface {
+	Discover(ctx context.Context) ([]string, error)
+}
+
+type Config struct {
+	Provider string
+	Peers    []string
+	Scheme   string
+	DNS      *DNSConfig
+	Consul   *ConsulConfig
+}
+
+func New(config *Config) (Provider, error) {
+	switch config.Provider {
+	case ProviderStatic, "":
+		return NewStatic(config.Peers), nil
+	case ProviderDNS:
+		return NewDNS(config.DNS, config.Scheme), nil
+	case ProviderConsul:
+		return NewConsul(config.Consul, config.Scheme), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, config.Provider)
+	}
+}
+
+func makePeerURL(scheme, host string, port uint16) string {
+	return fmt.Sprintf("%s://%s", scheme, joinHostPort(host, port))
+}
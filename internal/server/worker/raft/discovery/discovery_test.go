@@ -0,0 +1,72 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		config   *Config
+		expected Provider
+		err      error
+	}{
+		{
+			name:     "default",
+			config:   &Config{Peers: []string{"http://127.0.0.1:7080"}},
+			expected: &Static{},
+		},
+		{
+			name:     "static",
+			config:   &Config{Provider: ProviderStatic},
+			expected: &Static{},
+		},
+		{
+			name:     "dns",
+			config:   &Config{Provider: ProviderDNS, DNS: &DNSConfig{Name: "maf.service.consul"}},
+			expected: &DNS{},
+		},
+		{
+			name:     "consul",
+			config:   &Config{Provider: ProviderConsul, Consul: &ConsulConfig{Service: "maf"}},
+			expected: &Consul{},
+		},
+		{
+			name:   "unknown",
+			config: &Config{Provider: "etcd"},
+			err:    ErrUnknownProvider,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(tt.config)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.IsType(t, tt.expected, provider)
+		})
+	}
+}
+
+func TestStaticDiscover(t *testing.T) {
+	t.Parallel()
+
+	peers := []string{"http://127.0.0.1:7080", "http://127.0.0.1:7081"}
+
+	discovered, err := NewStatic(peers).Discover(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, peers, discovered)
+}
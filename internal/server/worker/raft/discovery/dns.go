@@ -0,0 +1,77 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	RecordA   = "a"
+	RecordSRV = "srv"
+)
+
+type DNSConfig struct {
+	// Name to resolve. For SRV records it's the full record name, e.g. _maf._tcp.service.consul
+	Name string
+	// Record type to look up, a or srv
+	Record string
+	// Port of the API, only used for A records. SRV records carry the port themselves
+	Port uint16
+}
+
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+type DNS struct {
+	config   *DNSConfig
+	scheme   string
+	resolver Resolver
+}
+
+func NewDNS(config *DNSConfig, scheme string) *DNS {
+	return &DNS{
+		config:   config,
+		scheme:   scheme,
+		resolver: net.DefaultResolver,
+	}
+}
+
+func (d *DNS) Discover(ctx context.Context) ([]string, error) {
+	var peers []string
+
+	switch strings.ToLower(d.config.Record) {
+	case RecordSRV:
+		_, records, err := d.resolver.LookupSRV(ctx, "", "", d.config.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lookup SRV records of %s: %w", d.config.Name, err)
+		}
+
+		for _, record := range records {
+			peers = append(peers, makePeerURL(d.scheme, strings.TrimSuffix(record.Target, "."), record.Port))
+		}
+	default:
+		addrs, err := d.resolver.LookupHost(ctx, d.config.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lookup %s: %w", d.config.Name, err)
+		}
+
+		for _, addr := range addrs {
+			peers = append(peers, makePeerURL(d.scheme, addr, d.config.Port))
+		}
+	}
+
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoPeersFound, d.config.Name)
+	}
+
+	return peers, nil
+}
+
+func joinHostPort(host string, port uint16) string {
+	return net.JoinHostPort(host, strconv.FormatUint(uint64(port), 10))
+}
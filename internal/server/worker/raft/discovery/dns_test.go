@@ -0,0 +1,93 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockResolver struct {
+	mock.Mock
+}
+
+func (m *MockResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	args := m.Called(ctx, host)
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	args := m.Called(ctx, service, proto, name)
+
+	return args.String(0), args.Get(1).([]*net.SRV), args.Error(2)
+}
+
+func TestDNSDiscover(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ARecords", func(t *testing.T) {
+		t.Parallel()
+
+		resolver := new(MockResolver)
+		resolver.On("LookupHost", mock.Anything, "maf.service.consul").
+			Return([]string{"10.0.0.1", "fd00::1"}, nil)
+
+		dns := NewDNS(&DNSConfig{Name: "maf.service.consul", Record: RecordA, Port: 7080}, "https")
+		dns.resolver = resolver
+
+		peers, err := dns.Discover(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://10.0.0.1:7080", "https://[fd00::1]:7080"}, peers)
+	})
+
+	t.Run("SRVRecords", func(t *testing.T) {
+		t.Parallel()
+
+		resolver := new(MockResolver)
+		resolver.On("LookupSRV", mock.Anything, "", "", "_maf._tcp.service.consul").
+			Return("", []*net.SRV{
+				{Target: "maf-1.node.consul.", Port: 7080},
+				{Target: "maf-2.node.consul.", Port: 7081},
+			}, nil)
+
+		dns := NewDNS(&DNSConfig{Name: "_maf._tcp.service.consul", Record: "SRV"}, "http")
+		dns.resolver = resolver
+
+		peers, err := dns.Discover(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"http://maf-1.node.consul:7080", "http://maf-2.node.consul:7081"}, peers)
+	})
+
+	t.Run("LookupError", func(t *testing.T) {
+		t.Parallel()
+
+		resolver := new(MockResolver)
+		resolver.On("LookupHost", mock.Anything, "maf.service.consul").
+			Return([]string{}, errors.New("no such host"))
+
+		dns := NewDNS(&DNSConfig{Name: "maf.service.consul", Port: 7080}, "http")
+		dns.resolver = resolver
+
+		_, err := dns.Discover(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("NoRecords", func(t *testing.T) {
+		t.Parallel()
+
+		resolver := new(MockResolver)
+		resolver.On("LookupSRV", mock.Anything, "", "", "_maf._tcp.service.consul").
+			Return("", []*net.SRV{}, nil)
+
+		dns := NewDNS(&DNSConfig{Name: "_maf._tcp.service.consul", Record: RecordSRV}, "http")
+		dns.resolver = resolver
+
+		_, err := dns.Discover(context.Background())
+		require.ErrorIs(t, err, ErrNoPeersFound)
+	})
+}
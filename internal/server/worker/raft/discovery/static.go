@@ -0,0 +1,15 @@
+package discovery
+
+import "context"
+
+type Static struct {
+	peers []string
+}
+
+func NewStatic(peers []string) *Static {
+	return &Static{peers: peers}
+}
+
+func (s *Static) Discover(_ context.Context) ([]string, error) {
+	return s.peers, nil
+}
@@ -2,4 +2,18 @@ package raft
 
 import "errors"
 
-var ErrNotALeader = errors.New("not a leader")
+var (
+	ErrNotALeader     = errors.New("not a leader")
+	ErrWildcardAddr   = errors.New("address must not be a wildcard")
+	ErrUnknownServer  = errors.New("server is not a member of the cluster")
+	ErrNotAVoter      = errors.New("server is not a voter")
+	ErrLeaveQuorum    = errors.New("leaving would break the quorum")
+	ErrNoLeader       = errors.New("no leader is reachable through the peers")
+	ErrLeaveForbidden = errors.New("server API token is not allowed to remove servers")
+	ErrReservedKey    = errors.New("key is reserved for the internal state")
+
+	ErrInvalidJoinToken = errors.New("invalid or expired join token")
+	ErrJoinTokenTTL     = errors.New("join token TTL exceeds the maximum")
+
+	ErrAuditLogCorrupted = errors.New("audit log is corrupted")
+)
@@ -0,0 +1,248 @@
+package raft
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	hraft "github.com/hashicorp/raft"
+)
+
+const (
+	// eventsBacklog is the number of the recent events kept to resume the streams of the reconnected clients
+	eventsBacklog           = 1024
+	eventsObservationsCap   = 64
+	eventDataServerID       = "serverId"
+	eventDataAddress        = "address"
+	eventDataSuffrage       = "suffrage"
+	eventDataLeader         = "leader"
+	eventDataLastContact    = "lastContact"
+	membershipActionJoin    = "join"
+	membershipActionRemove  = "remove"
+	membershipActionPromote = "promote"
+	membershipActionDemote  = "demote"
+	failureActionFailed     = "failed"
+	failureActionResumed    = "resumed"
+)
+
+// Keys of the event data read by the APIs, e.g. to watch the KV changes
+const (
+	EventDataAction = "action"
+	EventDataKey    = "key"
+	EventDataValue  = "value"
+)
+
+type EventType string
+
+const (
+	// EventLeadership is published when the local server gains or loses the leadership
+	EventLeadership EventType = "leadership"
+	// EventMembership is published by the leader when a server joins, leaves or changes its suffrage
+	EventMembership EventType = "membership"
+	// EventKV is published on every server when the replicated key is set or deleted
+	EventKV EventType = "kv"
+	// EventFailure is published by the leader when a server stops or resumes responding to heartbeats
+	EventFailure EventType = "failure"
+	// EventFailover is published for every step of the MySQL failover
+	EventFailover EventType = "failover"
+)
+
+// Event is the cluster event as seen by the local server. IDs grow monotonically within the server lifetime.
+type Event struct {
+	ID   uint64            `json:"id"`
+	Type EventType         `json:"type"`
+	Time time.Time         `json:"time"`
+	Data map[string]string `json:"data"`
+}
+
+type EventsCh chan *Event
+
+// eventBus fans the events out to the subscribers, keeping the recent ones to replay.
+// Slow subscribers lose the events, the gap is visible by the IDs.
+type eventBus struct {
+	mu          sync.Mutex
+	lastID      uint64
+	backlog     []*Event
+	subscribers map[EventsCh]struct{}
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{
+		backlog:     make([]*Event, 0, eventsBacklog),
+		subscribers: make(map[EventsCh]struct{}),
+	}
+}
+
+func (b *eventBus) publish(eventType EventType, data map[string]string) *Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := &Event{
+		ID:   b.lastID,
+		Type: eventType,
+		Time: time.Now().UTC(),
+		Data: data,
+	}
+
+	if len(b.backlog) == eventsBacklog {
+		b.backlog = append(b.backlog[:0], b.backlog[1:]...)
+	}
+
+	b.backlog = append(b.backlog, event)
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+
+	return event
+}
+
+// subscribe registers the channel and returns the buffered events newer than lastEventID.
+// The unknown ID, e.g. issued before the restart, replays the whole backlog.
+func (b *eventBus) subscribe(ch EventsCh, lastEventID uint64) []*Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers[ch] = struct{}{}
+
+	if lastEventID == 0 {
+		return nil
+	}
+
+	if lastEventID > b.lastID {
+		lastEventID = 0
+	}
+
+	replay := make([]*Event, 0)
+
+	for _, event := range b.backlog {
+		if event.ID > lastEventID {
+			replay = append(replay, event)
+		}
+	}
+
+	return replay
+}
+
+func (b *eventBus) unsubscribe(ch EventsCh) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subscribers, ch)
+}
+
+// SubscribeOnEvents registers the channel for the cluster events. Pass the ID of the last received event
+// to get the missed ones, which are still buffered, or 0 to get only the new events.
+func (r *Raft) SubscribeOnEvents(ch EventsCh, lastEventID uint64) []*Event {
+	r.logger.Trace().Msgf("Registering events channel, last event ID %d", lastEventID)
+
+	return r.events.subscribe(ch, lastEventID)
+}
+
+func (r *Raft) UnsubscribeFromEvents(ch EventsCh) {
+	r.logger.Trace().Msg("Unregistering events channel")
+
+	r.events.unsubscribe(ch)
+}
+
+func (r *Raft) publishEvent(eventType EventType, data map[string]string) {
+	if r.events == nil {
+		return
+	}
+
+	event := r.events.publish(eventType, data)
+
+	r.logger.Debug().Msgf("Published %s event %d", event.Type, event.ID)
+}
+
+func (r *Raft) publishLeadership(isLeader bool) {
+	if r.events == nil {
+		return
+	}
+
+	r.publishEvent(EventLeadership, map[string]string{
+		eventDataServerID: r.config.NodeID,
+		eventDataLeader:   strconv.FormatBool(isLeader),
+	})
+}
+
+// publishMembership reports the change of the server, the suffrage is omitted for the removed ones
+func (r *Raft) publishMembership(action string, srv hraft.Server) {
+	data := map[string]string{
+		EventDataAction:   action,
+		eventDataServerID: string(srv.ID),
+	}
+
+	if srv.Address != "" {
+		data[eventDataAddress] = string(srv.Address)
+	}
+
+	if action != membershipActionRemove {
+		data[eventDataSuffrage] = srv.Suffrage.String()
+	}
+
+	r.publishEvent(EventMembership, data)
+}
+
+// watchEvents turns the heartbeat observations of the leader into the failure events.
+func (r *Raft) watchEvents() {
+	observations := make(chan hraft.Observation, eventsObservationsCap)
+	observer := hraft.NewObserver(observations, false, func(o *hraft.Observation) bool {
+		switch o.Data.(type) {
+		case hraft.FailedHeartbeatObservation, hraft.ResumedHeartbeatObservation:
+			return true
+		default:
+			return false
+		}
+	})
+	r.raftInstance.RegisterObserver(observer)
+
+	go func() {
+		failing := make(map[hraft.ServerID]struct{})
+
+		for {
+			select {
+			case o := <-observations:
+				r.observeFailure(o, failing)
+			case <-r.done:
+				r.logger.Info().Msg("Stopping events watcher")
+				r.raftInstance.DeregisterObserver(observer)
+
+				return
+			}
+		}
+	}()
+}
+
+// observeFailure publishes only the first failed heartbeat of the server, raft reports every retry
+func (r *Raft) observeFailure(o hraft.Observation, failing map[hraft.ServerID]struct{}) {
+	switch data := o.Data.(type) {
+	case hraft.FailedHeartbeatObservation:
+		if _, ok := failing[data.PeerID]; ok {
+			return
+		}
+
+		failing[data.PeerID] = struct{}{}
+
+		eventData := map[string]string{
+			EventDataAction:   failureActionFailed,
+			eventDataServerID: string(data.PeerID),
+		}
+		if !data.LastContact.IsZero() {
+			eventData[eventDataLastContact] = data.LastContact.UTC().Format(time.RFC3339)
+		}
+
+		r.publishEvent(EventFailure, eventData)
+	case hraft.ResumedHeartbeatObservation:
+		delete(failing, data.PeerID)
+
+		r.publishEvent(EventFailure, map[string]string{
+			EventDataAction:   failureActionResumed,
+			eventDataServerID: string(data.PeerID),
+		})
+	}
+}
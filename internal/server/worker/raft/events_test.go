@@ -0,0 +1,202 @@
+package raft
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	hraft "github.com/hashicorp/raft"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Publish", func(t *testing.T) {
+		t.Parallel()
+
+		bus := newEventBus()
+		ch := make(EventsCh, 2)
+
+		assert.Empty(t, bus.subscribe(ch, 0))
+
+		bus.publish(EventKV, map[string]string{EventDataKey: "key1"})
+		bus.publish(EventKV, map[string]string{EventDataKey: "key2"})
+
+		first := <-ch
+		second := <-ch
+
+		assert.Equal(t, uint64(1), first.ID)
+		assert.Equal(t, EventKV, first.Type)
+		assert.Equal(t, "key1", first.Data[EventDataKey])
+		assert.Equal(t, uint64(2), second.ID)
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		t.Parallel()
+
+		bus := newEventBus()
+
+		for range 3 {
+			bus.publish(EventKV, nil)
+		}
+
+		replay := bus.subscribe(make(EventsCh), 1)
+		require.Len(t, replay, 2)
+		assert.Equal(t, uint64(2), replay[0].ID)
+		assert.Equal(t, uint64(3), replay[1].ID)
+
+		assert.Empty(t, bus.subscribe(make(EventsCh), 3))
+	})
+
+	t.Run("ResumeUnknownID", func(t *testing.T) {
+		t.Parallel()
+
+		bus := newEventBus()
+		bus.publish(EventKV, nil)
+		bus.publish(EventKV, nil)
+
+		assert.Len(t, bus.subscribe(make(EventsCh), 42), 2)
+	})
+
+	t.Run("BacklogOverflow", func(t *testing.T) {
+		t.Parallel()
+
+		bus := newEventBus()
+
+		for range eventsBacklog + 10 {
+			bus.publish(EventKV, nil)
+		}
+
+		replay := bus.subscribe(make(EventsCh), 1)
+		require.Len(t, replay, eventsBacklog)
+		assert.Equal(t, uint64(11), replay[0].ID)
+	})
+
+	t.Run("SlowSubscriber", func(t *testing.T) {
+		t.Parallel()
+
+		bus := newEventBus()
+		ch := make(EventsCh, 1)
+		bus.subscribe(ch, 0)
+
+		bus.publish(EventKV, nil)
+		bus.publish(EventKV, nil)
+
+		assert.Equal(t, uint64(1), (<-ch).ID)
+		assert.Empty(t, ch)
+	})
+
+	t.Run("Unsubscribe", func(t *testing.T) {
+		t.Parallel()
+
+		bus := newEventBus()
+		ch := make(EventsCh, 1)
+		bus.subscribe(ch, 0)
+		bus.unsubscribe(ch)
+
+		bus.publish(EventKV, nil)
+
+		assert.Empty(t, ch)
+	})
+}
+
+func TestPublishMembership(t *testing.T) {
+	t.Parallel()
+
+	raft := &Raft{
+		logger: log.Logger,
+		events: newEventBus(),
+	}
+	ch := make(EventsCh, 2)
+	raft.SubscribeOnEvents(ch, 0)
+
+	raft.publishMembership(membershipActionJoin, hraft.Server{ID: "node2", Address: "10.0.0.2:7081"})
+	raft.publishMembership(membershipActionRemove, hraft.Server{ID: "node3"})
+
+	assert.Equal(t, map[string]string{
+		EventDataAction:   membershipActionJoin,
+		eventDataServerID: "node2",
+		eventDataAddress:  "10.0.0.2:7081",
+		eventDataSuffrage: "Voter",
+	}, (<-ch).Data)
+	assert.Equal(t, map[string]string{
+		EventDataAction:   membershipActionRemove,
+		eventDataServerID: "node3",
+	}, (<-ch).Data)
+}
+
+func TestPublishLeadership(t *testing.T) {
+	t.Parallel()
+
+	raft := &Raft{
+		config: &Config{NodeID: "node1"},
+		logger: log.Logger,
+		events: newEventBus(),
+	}
+	ch := make(EventsCh, 1)
+	raft.SubscribeOnEvents(ch, 0)
+
+	raft.broadcastLeadershipChange(true)
+
+	event := <-ch
+	assert.Equal(t, EventLeadership, event.Type)
+	assert.Equal(t, map[string]string{eventDataServerID: "node1", eventDataLeader: "true"}, event.Data)
+}
+
+func TestObserveFailure(t *testing.T) {
+	t.Parallel()
+
+	raft := &Raft{
+		logger: log.Logger,
+		events: newEventBus(),
+	}
+	ch := make(EventsCh, 3)
+	raft.SubscribeOnEvents(ch, 0)
+
+	failing := make(map[hraft.ServerID]struct{})
+	lastContact := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	raft.observeFailure(hraft.Observation{
+		Data: hraft.FailedHeartbeatObservation{PeerID: "node2", LastContact: lastContact},
+	}, failing)
+	raft.observeFailure(hraft.Observation{
+		Data: hraft.FailedHeartbeatObservation{PeerID: "node2", LastContact: lastContact},
+	}, failing)
+	raft.observeFailure(hraft.Observation{Data: hraft.ResumedHeartbeatObservation{PeerID: "node2"}}, failing)
+
+	require.Len(t, ch, 2)
+	assert.Equal(t, map[string]string{
+		EventDataAction:      failureActionFailed,
+		eventDataServerID:    "node2",
+		eventDataLastContact: "2025-01-01T00:00:00Z",
+	}, (<-ch).Data)
+	assert.Equal(t, map[string]string{
+		EventDataAction:   failureActionResumed,
+		eventDataServerID: "node2",
+	}, (<-ch).Data)
+	assert.Empty(t, failing)
+}
+
+func TestFSMPublishesKVEvents(t *testing.T) {
+	t.Parallel()
+
+	bus := newEventBus()
+	ch := make(EventsCh, 2)
+	bus.subscribe(ch, 0)
+
+	fsm := NewFSM(NewSafeStorage())
+	fsm.events = bus
+
+	for _, cmd := range []*Command{makeCommand(OpSet, "key", "value"), makeCommand(OpDelete, "key", "")} {
+		data, err := json.Marshal(cmd)
+		require.NoError(t, err)
+
+		fsm.Apply(&hraft.Log{Data: data})
+	}
+
+	assert.Equal(t, map[string]string{EventDataAction: "set", EventDataKey: "key", EventDataValue: "value"}, (<-ch).Data)
+	assert.Equal(t, map[string]string{EventDataAction: "delete", EventDataKey: "key"}, (<-ch).Data)
+}
@@ -15,12 +15,14 @@ type Storage interface {
 	Get(key string) (string, bool)
 	Set(key, value string)
 	Delete(key string)
+	Len() int
 	Snapshot() Mapping
 	Restore(data Mapping)
 }
 
 type FSM struct {
 	storage Storage
+	events  *eventBus
 	logger  zerolog.Logger
 }
 
@@ -45,8 +47,14 @@ func (f *FSM) Apply(rlog *raft.Log) any {
 	switch cmd.Op {
 	case OpSet:
 		f.storage.Set(cmd.Key, cmd.Value)
+		f.publish(&cmd)
 	case OpDelete:
 		f.storage.Delete(cmd.Key)
+		f.publish(&cmd)
+	case OpAudit:
+		if err := appendAuditRecord(f.storage, cmd.Value); err != nil {
+			f.logger.Error().Err(err).Msg("Failed to append audit record")
+		}
 	default:
 		panic("unrecognized command " + cmd.Op.String())
 	}
@@ -54,6 +62,24 @@ func (f *FSM) Apply(rlog *raft.Log) any {
 	return nil
 }
 
+// publish reports the applied KV change, if the FSM is attached to the event bus.
+// The changes of the internal state are not KV events, they are reported by their own events if any.
+func (f *FSM) publish(cmd *Command) {
+	if f.events == nil || IsReservedKey(cmd.Key) {
+		return
+	}
+
+	data := map[string]string{
+		EventDataAction: cmd.Op.String(),
+		EventDataKey:    cmd.Key,
+	}
+	if cmd.Op == OpSet {
+		data[EventDataValue] = cmd.Value
+	}
+
+	f.events.publish(EventKV, data)
+}
+
 func (f *FSM) Snapshot() (raft.FSMSnapshot, error) {
 	f.logger.Trace().Msg("Creating snapshot")
 
@@ -30,6 +30,12 @@ func (m *MockStorage) Delete(key string) {
 	m.Called(key)
 }
 
+func (m *MockStorage) Len() int {
+	args := m.Called()
+
+	return args.Int(0)
+}
+
 func (m *MockStorage) Snapshot() Mapping {
 	args := m.Called()
 
@@ -106,6 +112,38 @@ func TestFSM_Apply(t *testing.T) {
 	})
 }
 
+func TestFSM_ApplyAudit(t *testing.T) {
+	t.Parallel()
+
+	storage := NewSafeStorage()
+	fsm := NewFSM(storage)
+
+	data, _ := json.Marshal(&Command{Op: OpAudit, Value: `{"action":"raft.join","outcome":"success"}`})
+	fsm.Apply(&raft.Log{Data: data})
+
+	value, ok := storage.Get(auditRecordKey(1))
+	require.True(t, ok)
+	assert.Contains(t, value, `"action":"raft.join"`)
+}
+
+func TestFSM_PublishSkipsReservedKeys(t *testing.T) {
+	t.Parallel()
+
+	fsm := NewFSM(NewSafeStorage())
+	fsm.events = newEventBus()
+
+	ch := make(EventsCh, 2)
+	fsm.events.subscribe(ch, 0)
+
+	for _, key := range []string{joinTokenPrefix + "abc", "key1"} {
+		data, _ := json.Marshal(&Command{Op: OpSet, Key: key, Value: "value"})
+		fsm.Apply(&raft.Log{Data: data})
+	}
+
+	require.Len(t, ch, 1)
+	assert.Equal(t, "key1", (<-ch).Data[EventDataKey])
+}
+
 func TestFSM_Snapshot(t *testing.T) {
 	t.Parallel()
 
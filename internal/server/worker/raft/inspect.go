@@ -0,0 +1,310 @@
+package raft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	hraft "github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	snapshotsDir      = "snapshots"
+	snapshotMetaFile  = "meta.json"
+	snapshotTmpSuffix = ".tmp"
+	dbLockTimeout     = time.Second
+)
+
+var (
+	ErrNoRaftDB       = errors.New("raft database not found in data directory")
+	ErrRaftDBLocked   = errors.New("raft database is locked, make sure the server is stopped")
+	keyCurrentTerm    = []byte("CurrentTerm")
+	keyLastVoteTerm   = []byte("LastVoteTerm")
+	keyLastVoteCand   = []byte("LastVoteCand")
+	errUndecodableLog = errors.New("failed to decode log entry")
+)
+
+type SnapshotInfo struct {
+	ID                 string   `json:"id"`
+	Index              uint64   `json:"index"`
+	Term               uint64   `json:"term"`
+	Size               int64    `json:"size"`
+	ConfigurationIndex uint64   `json:"configurationIndex"`
+	Configuration      []Server `json:"configuration"`
+}
+
+type LogEntry struct {
+	Index         uint64    `json:"index"`
+	Term          uint64    `json:"term"`
+	Type          string    `json:"type"`
+	AppendedAt    time.Time `json:"appendedAt"`
+	Command       *Command  `json:"command,omitempty"`
+	Configuration []Server  `json:"configuration,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+type Inspection struct {
+	Datadir       string         `json:"datadir"`
+	CurrentTerm   uint64         `json:"currentTerm"`
+	LastVoteTerm  uint64         `json:"lastVoteTerm"`
+	LastVoteCand  string         `json:"lastVoteCand"`
+	FirstIndex    uint64         `json:"firstIndex"`
+	LastIndex     uint64         `json:"lastIndex"`
+	LastTerm      uint64         `json:"lastTerm"`
+	Configuration []Server       `json:"configuration"`
+	Snapshots     []SnapshotInfo `json:"snapshots"`
+	Logs          []LogEntry     `json:"logs,omitempty"`
+}
+
+// Inspect reads the raft data directory of a stopped server without modifying it.
+// If tail is positive, only the last tail log entries are decoded; negative tail skips the logs.
+func Inspect(datadir string, tail int) (*Inspection, error) {
+	dbPath := filepath.Join(datadir, dbName)
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoRaftDB, dbPath)
+	}
+
+	store, err := raftboltdb.New(raftboltdb.Options{
+		Path:        dbPath,
+		BoltOptions: &bbolt.Options{ReadOnly: true, Timeout: dbLockTimeout},
+	})
+	if err != nil {
+		if errors.Is(err, bbolt.ErrTimeout) {
+			return nil, ErrRaftDBLocked
+		}
+
+		return nil, fmt.Errorf("failed to open raft database: %w", err)
+	}
+	defer store.Close()
+
+	inspection := &Inspection{
+		Datadir:       datadir,
+		Configuration: make([]Server, 0),
+		Snapshots:     make([]SnapshotInfo, 0),
+	}
+
+	if err := inspectStableStore(store, inspection); err != nil {
+		return nil, err
+	}
+
+	inspection.Snapshots, err = readSnapshots(datadir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(inspection.Snapshots) > 0 {
+		inspection.Configuration = inspection.Snapshots[0].Configuration
+	}
+
+	if err := inspectLogStore(store, inspection, tail); err != nil {
+		return nil, err
+	}
+
+	// the log may be compacted up to the latest snapshot, it holds the last index and term then
+	if len(inspection.Snapshots) > 0 && inspection.Snapshots[0].Index > inspection.LastIndex {
+		inspection.LastIndex = inspection.Snapshots[0].Index
+		inspection.LastTerm = inspection.Snapshots[0].Term
+	}
+
+	return inspection, nil
+}
+
+func inspectStableStore(store hraft.StableStore, inspection *Inspection) error {
+	var err error
+
+	if inspection.CurrentTerm, err = store.GetUint64(keyCurrentTerm); err != nil &&
+		!errors.Is(err, raftboltdb.ErrKeyNotFound) {
+		return fmt.Errorf("failed to read current term: %w", err)
+	}
+
+	if inspection.LastVoteTerm, err = store.GetUint64(keyLastVoteTerm); err != nil &&
+		!errors.Is(err, raftboltdb.ErrKeyNotFound) {
+		return fmt.Errorf("failed to read last vote term: %w", err)
+	}
+
+	cand, err := store.Get(keyLastVoteCand)
+	if err != nil && !errors.Is(err, raftboltdb.ErrKeyNotFound) {
+		return fmt.Errorf("failed to read last vote candidate: %w", err)
+	}
+
+	inspection.LastVoteCand = string(cand)
+
+	return nil
+}
+
+// inspectLogStore decodes only the printed entries. The older ones are read back from the end
+// until the latest configuration is found, so a long log is not decoded as a whole.
+func inspectLogStore(store hraft.LogStore, inspection *Inspection, tail int) error {
+	var err error
+
+	if inspection.FirstIndex, err = store.FirstIndex(); err != nil {
+		return fmt.Errorf("failed to read first index: %w", err)
+	}
+
+	if inspection.LastIndex, err = store.LastIndex(); err != nil {
+		return fmt.Errorf("failed to read last index: %w", err)
+	}
+
+	if inspection.LastIndex == 0 {
+		return nil
+	}
+
+	var last hraft.Log
+	if err := store.GetLog(inspection.LastIndex, &last); err != nil {
+		return fmt.Errorf("failed to read log %d: %w", inspection.LastIndex, err)
+	}
+
+	inspection.LastTerm = last.Term
+
+	printedFrom := inspection.FirstIndex
+
+	switch {
+	case tail < 0:
+		printedFrom = inspection.LastIndex + 1
+	case tail > 0 && uint64(tail) <= inspection.LastIndex-inspection.FirstIndex:
+		printedFrom = inspection.LastIndex - uint64(tail) + 1
+	}
+
+	// the configuration of the snapshot is newer than the logs up to its index
+	var configIndex uint64
+	if len(inspection.Snapshots) > 0 {
+		configIndex = inspection.Snapshots[0].ConfigurationIndex
+	}
+
+	configFound := false
+	entries := make([]LogEntry, 0, inspection.LastIndex+1-printedFrom)
+
+	for idx := inspection.LastIndex; idx >= inspection.FirstIndex; idx-- {
+		printed := idx >= printedFrom
+		if !printed && (configFound || idx <= configIndex) {
+			break
+		}
+
+		var rlog hraft.Log
+		if err := store.GetLog(idx, &rlog); err != nil {
+			return fmt.Errorf("failed to read log %d: %w", idx, err)
+		}
+
+		if !printed && rlog.Type != hraft.LogConfiguration {
+			continue
+		}
+
+		entry := decodeLog(&rlog)
+		if !configFound && entry.Configuration != nil && idx > configIndex {
+			inspection.Configuration = entry.Configuration
+			configFound = true
+		}
+
+		if printed {
+			entries = append(entries, entry)
+		}
+	}
+
+	if tail >= 0 {
+		slices.Reverse(entries)
+		inspection.Logs = entries
+	}
+
+	return nil
+}
+
+func decodeLog(rlog *hraft.Log) (entry LogEntry) {
+	entry = LogEntry{
+		Index:      rlog.Index,
+		Term:       rlog.Term,
+		Type:       rlog.Type.String(),
+		AppendedAt: rlog.AppendedAt,
+	}
+
+	switch rlog.Type { //nolint:exhaustive
+	case hraft.LogCommand:
+		cmd := &Command{}
+		if err := json.Unmarshal(rlog.Data, cmd); err != nil {
+			entry.Error = fmt.Errorf("%w: %w", errUndecodableLog, err).Error()
+
+			return entry
+		}
+
+		entry.Command = cmd
+	case hraft.LogConfiguration:
+		defer func() {
+			if r := recover(); r != nil {
+				entry.Configuration = nil
+				entry.Error = fmt.Sprintf("%s: %v", errUndecodableLog, r)
+			}
+		}()
+
+		entry.Configuration = convertServers(hraft.DecodeConfiguration(rlog.Data).Servers)
+	}
+
+	return entry
+}
+
+func readSnapshots(datadir string) ([]SnapshotInfo, error) {
+	snapshots := make([]SnapshotInfo, 0)
+
+	dirs, err := os.ReadDir(filepath.Join(datadir, snapshotsDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return snapshots, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots directory: %w", err)
+	}
+
+	for _, dir := range dirs {
+		if !dir.IsDir() || strings.HasSuffix(dir.Name(), snapshotTmpSuffix) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(datadir, snapshotsDir, dir.Name(), snapshotMetaFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot %s metadata: %w", dir.Name(), err)
+		}
+
+		var meta hraft.SnapshotMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return nil, fmt.Errorf("failed to decode snapshot %s metadata: %w", dir.Name(), err)
+		}
+
+		snapshots = append(snapshots, SnapshotInfo{
+			ID:                 meta.ID,
+			Index:              meta.Index,
+			Term:               meta.Term,
+			Size:               meta.Size,
+			ConfigurationIndex: meta.ConfigurationIndex,
+			Configuration:      convertServers(meta.Configuration.Servers),
+		})
+	}
+
+	// Newest first, the same order the snapshot store uses
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].Term != snapshots[j].Term {
+			return snapshots[i].Term > snapshots[j].Term
+		}
+
+		return snapshots[i].Index > snapshots[j].Index
+	})
+
+	return snapshots, nil
+}
+
+func convertServers(servers []hraft.Server) []Server {
+	result := make([]Server, 0, len(servers))
+
+	for _, srv := range servers {
+		result = append(result, Server{
+			ID:       string(srv.ID),
+			Address:  string(srv.Address),
+			Suffrage: srv.Suffrage.String(),
+		})
+	}
+
+	return result
+}
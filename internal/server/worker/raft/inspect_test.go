@@ -0,0 +1,214 @@
+package raft
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"testing"
+
+	hraft "github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfiguration = hraft.Configuration{
+	Servers: []hraft.Server{
+		{ID: "node1", Address: "127.0.0.1:7081", Suffrage: hraft.Voter},
+		{ID: "node2", Address: "127.0.0.1:7082", Suffrage: hraft.Voter},
+	},
+}
+
+func writeDatadir(t *testing.T, logs []*hraft.Log) string {
+	t.Helper()
+
+	datadir := t.TempDir()
+
+	store, err := raftboltdb.NewBoltStore(filepath.Join(datadir, dbName))
+	require.NoError(t, err)
+
+	require.NoError(t, store.StoreLogs(logs))
+	require.NoError(t, store.SetUint64(keyCurrentTerm, 2))
+	require.NoError(t, store.Set(keyLastVoteCand, []byte("127.0.0.1:7081")))
+	require.NoError(t, store.Close())
+
+	snapshots, err := hraft.NewFileSnapshotStore(datadir, 1, io.Discard)
+	require.NoError(t, err)
+
+	_, transport := hraft.NewInmemTransport("")
+
+	sink, err := snapshots.Create(hraft.SnapshotVersionMax, 3, 2, testConfiguration, 1, transport)
+	require.NoError(t, err)
+	_, err = sink.Write([]byte(`{"key":"value"}`))
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+
+	return datadir
+}
+
+func commandLog(t *testing.T, index, term uint64, key, value string) *hraft.Log {
+	t.Helper()
+
+	cmd, err := json.Marshal(makeCommand(OpSet, key, value))
+	require.NoError(t, err)
+
+	return &hraft.Log{Index: index, Term: term, Type: hraft.LogCommand, Data: cmd}
+}
+
+func prepareDatadir(t *testing.T) string {
+	t.Helper()
+
+	return writeDatadir(t, []*hraft.Log{
+		{Index: 1, Term: 1, Type: hraft.LogConfiguration, Data: hraft.EncodeConfiguration(testConfiguration)},
+		{Index: 2, Term: 2, Type: hraft.LogNoop},
+		commandLog(t, 3, 2, "key", "value"),
+		{Index: 4, Term: 2, Type: hraft.LogCommand, Data: []byte("garbage")},
+	})
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MissingDatabase", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Inspect(t.TempDir(), 0)
+		require.ErrorIs(t, err, ErrNoRaftDB)
+	})
+
+	t.Run("AllLogs", func(t *testing.T) {
+		t.Parallel()
+
+		datadir := prepareDatadir(t)
+
+		inspection, err := Inspect(datadir, 0)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(2), inspection.CurrentTerm)
+		assert.Equal(t, "127.0.0.1:7081", inspection.LastVoteCand)
+		assert.Equal(t, uint64(1), inspection.FirstIndex)
+		assert.Equal(t, uint64(4), inspection.LastIndex)
+		assert.Equal(t, uint64(2), inspection.LastTerm)
+		assert.Len(t, inspection.Configuration, 2)
+		assert.Equal(t, "node2", inspection.Configuration[1].ID)
+		assert.Equal(t, "Voter", inspection.Configuration[1].Suffrage)
+
+		require.Len(t, inspection.Snapshots, 1)
+		assert.Equal(t, uint64(3), inspection.Snapshots[0].Index)
+		assert.Equal(t, uint64(2), inspection.Snapshots[0].Term)
+		assert.Len(t, inspection.Snapshots[0].Configuration, 2)
+
+		require.Len(t, inspection.Logs, 4)
+		assert.Equal(t, "LogConfiguration", inspection.Logs[0].Type)
+		assert.Len(t, inspection.Logs[0].Configuration, 2)
+		assert.Equal(t, "LogNoop", inspection.Logs[1].Type)
+		require.NotNil(t, inspection.Logs[2].Command)
+		assert.Equal(t, "key", inspection.Logs[2].Command.Key)
+		assert.Equal(t, "value", inspection.Logs[2].Command.Value)
+		assert.Nil(t, inspection.Logs[3].Command)
+		assert.NotEmpty(t, inspection.Logs[3].Error)
+	})
+
+	t.Run("TailLogs", func(t *testing.T) {
+		t.Parallel()
+
+		datadir := prepareDatadir(t)
+
+		inspection, err := Inspect(datadir, 2)
+		require.NoError(t, err)
+		require.Len(t, inspection.Logs, 2)
+		assert.Equal(t, uint64(3), inspection.Logs[0].Index)
+		assert.Len(t, inspection.Configuration, 2, "expected configuration to be read from the skipped logs")
+	})
+
+	t.Run("SkipLogs", func(t *testing.T) {
+		t.Parallel()
+
+		datadir := prepareDatadir(t)
+
+		inspection, err := Inspect(datadir, -1)
+		require.NoError(t, err)
+		assert.Nil(t, inspection.Logs)
+		assert.Equal(t, uint64(4), inspection.LastIndex)
+	})
+
+	t.Run("CompactedLog", func(t *testing.T) {
+		t.Parallel()
+
+		datadir := writeDatadir(t, []*hraft.Log{
+			{Index: 1, Term: 1, Type: hraft.LogConfiguration, Data: hraft.EncodeConfiguration(testConfiguration)},
+		})
+
+		inspection, err := Inspect(datadir, 0)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), inspection.LastIndex)
+		assert.Equal(t, uint64(2), inspection.LastTerm, "expected the term of the snapshot")
+		require.Len(t, inspection.Logs, 1)
+	})
+
+	t.Run("LockedDatabase", func(t *testing.T) {
+		t.Parallel()
+
+		datadir := prepareDatadir(t)
+
+		store, err := raftboltdb.NewBoltStore(filepath.Join(datadir, dbName))
+		require.NoError(t, err)
+
+		defer store.Close()
+
+		_, err = Inspect(datadir, 0)
+		require.ErrorIs(t, err, ErrRaftDBLocked)
+	})
+}
+
+type countingLogStore struct {
+	hraft.LogStore
+
+	reads int
+}
+
+func (s *countingLogStore) GetLog(index uint64, log *hraft.Log) error {
+	s.reads++
+
+	return s.LogStore.GetLog(index, log)
+}
+
+func TestInspectLogStore(t *testing.T) {
+	t.Parallel()
+
+	inmem := hraft.NewInmemStore()
+	logs := []*hraft.Log{
+		{Index: 1, Term: 1, Type: hraft.LogConfiguration, Data: hraft.EncodeConfiguration(testConfiguration)},
+	}
+
+	for idx := uint64(2); idx <= 100; idx++ {
+		logs = append(logs, commandLog(t, idx, 2, "key", "value"))
+	}
+
+	require.NoError(t, inmem.StoreLogs(logs))
+
+	t.Run("TailStopsAtSnapshotConfiguration", func(t *testing.T) {
+		t.Parallel()
+
+		store := &countingLogStore{LogStore: inmem}
+		inspection := &Inspection{Snapshots: []SnapshotInfo{{Index: 98, ConfigurationIndex: 98}}}
+
+		require.NoError(t, inspectLogStore(store, inspection, 2))
+		require.Len(t, inspection.Logs, 2)
+		assert.Equal(t, uint64(99), inspection.Logs[0].Index)
+		assert.Equal(t, uint64(100), inspection.Logs[1].Index)
+		assert.Equal(t, uint64(2), inspection.LastTerm)
+		assert.Equal(t, 3, store.reads, "expected only the last and the printed entries to be read")
+	})
+
+	t.Run("SkipLogsFindsConfiguration", func(t *testing.T) {
+		t.Parallel()
+
+		store := &countingLogStore{LogStore: inmem}
+		inspection := &Inspection{}
+
+		require.NoError(t, inspectLogStore(store, inspection, -1))
+		assert.Nil(t, inspection.Logs)
+		assert.Len(t, inspection.Configuration, 2)
+	})
+}
@@ -0,0 +1,118 @@
+package raft
+
+import (
+	"fmt"
+	"time"
+
+	hraft "github.com/hashicorp/raft"
+)
+
+const preferredLeaderInterval = 10 * time.Second
+
+// TransferLeadership hands the leadership over to the given server,
+// or to the most up-to-date voter if the server ID is empty.
+func (r *Raft) TransferLeadership(serverID string) error {
+	r.logger.Trace().Msgf("Transfer leadership to %q", serverID)
+
+	if !r.IsLeader() {
+		r.logger.Warn().Msg("I'm not a leader, can't proceed with leadership transfer")
+
+		return ErrNotALeader
+	}
+
+	if serverID == "" {
+		if err := r.raftInstance.LeadershipTransfer().Error(); err != nil {
+			r.logger.Err(err).Msg("Failed to transfer leadership")
+
+			return fmt.Errorf("failed to transfer leadership: %w", err)
+		}
+
+		r.logger.Info().Msg("Successfully transferred leadership")
+
+		return nil
+	}
+
+	if serverID == r.config.NodeID {
+		r.logger.Info().Msg("I'm already the leader, ignoring leadership transfer request")
+
+		return nil
+	}
+
+	srv, err := r.findServer(serverID)
+	if err != nil {
+		return err
+	}
+
+	if srv.Suffrage != hraft.Voter {
+		return fmt.Errorf("%w: %s", ErrNotAVoter, serverID)
+	}
+
+	return r.transferLeadershipTo(srv)
+}
+
+func (r *Raft) transferLeadershipTo(srv *hraft.Server) error {
+	if err := r.raftInstance.LeadershipTransferToServer(srv.ID, srv.Address).Error(); err != nil {
+		r.logger.Err(err).Msgf("Failed to transfer leadership to %s", srv.ID)
+
+		return fmt.Errorf("failed to transfer leadership to %s: %w", srv.ID, err)
+	}
+
+	r.logger.Info().Msgf("Successfully transferred leadership to %s", srv.ID)
+
+	return nil
+}
+
+// runPreferredLeader periodically moves the leadership to the preferred server, once it's a healthy voter.
+// It's a no-op on the preferred server itself and on the followers.
+func (r *Raft) runPreferredLeader() {
+	if r.config.PreferredLeader == "" || r.config.PreferredLeader == r.config.NodeID {
+		return
+	}
+
+	r.logger.Info().Msgf("Preferred leader is %s", r.config.PreferredLeader)
+
+	go func() {
+		ticker := time.NewTicker(preferredLeaderInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if r.IsLeader() {
+					r.handOverToPreferredLeader()
+				}
+			case <-r.done:
+				r.logger.Info().Msg("Stopping preferred leader monitoring")
+
+				return
+			}
+		}
+	}()
+}
+
+func (r *Raft) handOverToPreferredLeader() {
+	srv, err := r.findServer(r.config.PreferredLeader)
+	if err != nil {
+		r.logger.Debug().Err(err).Msg("Preferred leader is not available")
+
+		return
+	}
+
+	if srv.Suffrage != hraft.Voter {
+		r.logger.Debug().Msgf("Preferred leader %s is not a voter yet", srv.ID)
+
+		return
+	}
+
+	if r.autopilotEnabled() && r.autopilot.isFailing(srv.ID) {
+		r.logger.Debug().Msgf("Preferred leader %s is failing, keeping the leadership", srv.ID)
+
+		return
+	}
+
+	r.logger.Info().Msgf("Handing the leadership over to the preferred leader %s", srv.ID)
+
+	if err := r.transferLeadershipTo(srv); err != nil {
+		r.logger.Warn().Err(err).Msg("Failed to hand the leadership over to the preferred leader")
+	}
+}
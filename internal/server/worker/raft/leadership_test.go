@@ -0,0 +1,162 @@
+package raft
+
+import (
+	"testing"
+	"time"
+
+	hraft "github.com/hashicorp/raft"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferLeadership(t *testing.T) {
+	t.Parallel()
+
+	servers := []hraft.Server{
+		{ID: "node1", Address: "127.0.0.1:7081", Suffrage: hraft.Voter},
+		{ID: "node2", Address: "127.0.0.1:7082", Suffrage: hraft.Voter},
+		{ID: "node3", Address: "127.0.0.1:7083", Suffrage: hraft.Nonvoter},
+	}
+
+	newLeader := func(mockRaft *MockHRaft) *Raft {
+		mockRaft.On("State").Return(hraft.Leader)
+
+		return &Raft{config: &Config{NodeID: "node1"}, raftInstance: mockRaft, logger: log.Logger}
+	}
+
+	t.Run("AnyServer", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockFuture := new(MockFuture)
+		mockFuture.On("Error").Return(nil)
+		mockRaft.On("LeadershipTransfer").Return(mockFuture)
+
+		require.NoError(t, newLeader(mockRaft).TransferLeadership(""))
+		mockRaft.AssertExpectations(t)
+	})
+
+	t.Run("ToServer", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockFuture := new(MockFuture)
+		mockFuture.On("Error").Return(nil)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On(
+			"LeadershipTransferToServer",
+			hraft.ServerID("node2"),
+			hraft.ServerAddress("127.0.0.1:7082"),
+		).Return(mockFuture)
+
+		require.NoError(t, newLeader(mockRaft).TransferLeadership("node2"))
+		mockRaft.AssertExpectations(t)
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+
+		require.NoError(t, newLeader(mockRaft).TransferLeadership("node1"))
+		mockRaft.AssertNotCalled(t, "LeadershipTransferToServer", mock.Anything, mock.Anything)
+	})
+
+	t.Run("UnknownServer", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfiguration(mockRaft, servers...)
+
+		require.ErrorIs(t, newLeader(mockRaft).TransferLeadership("node4"), ErrUnknownServer)
+	})
+
+	t.Run("Nonvoter", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfiguration(mockRaft, servers...)
+
+		require.ErrorIs(t, newLeader(mockRaft).TransferLeadership("node3"), ErrNotAVoter)
+	})
+
+	t.Run("NotALeader", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockRaft.On("State").Return(hraft.Follower)
+
+		raft := &Raft{config: &Config{NodeID: "node1"}, raftInstance: mockRaft, logger: log.Logger}
+
+		require.ErrorIs(t, raft.TransferLeadership("node2"), ErrNotALeader)
+	})
+}
+
+func TestHandOverToPreferredLeader(t *testing.T) {
+	t.Parallel()
+
+	servers := []hraft.Server{
+		{ID: "node1", Address: "127.0.0.1:7081", Suffrage: hraft.Voter},
+		{ID: "node2", Address: "127.0.0.1:7082", Suffrage: hraft.Voter},
+		{ID: "node3", Address: "127.0.0.1:7083", Suffrage: hraft.Nonvoter},
+	}
+
+	newLeader := func(mockRaft *MockHRaft, preferred string) *Raft {
+		return &Raft{
+			config:       &Config{NodeID: "node1", PreferredLeader: preferred},
+			raftInstance: mockRaft,
+			logger:       log.Logger,
+		}
+	}
+
+	t.Run("HealthyVoter", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockFuture := new(MockFuture)
+		mockFuture.On("Error").Return(nil)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On(
+			"LeadershipTransferToServer",
+			hraft.ServerID("node2"),
+			hraft.ServerAddress("127.0.0.1:7082"),
+		).Return(mockFuture)
+
+		newLeader(mockRaft, "node2").handOverToPreferredLeader()
+		mockRaft.AssertExpectations(t)
+	})
+
+	t.Run("FailingVoter", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfiguration(mockRaft, servers...)
+
+		raft := newLeader(mockRaft, "node2")
+		raft.autopilot = &autopilot{failingSince: map[hraft.ServerID]time.Time{"node2": time.Now()}}
+
+		raft.handOverToPreferredLeader()
+		mockRaft.AssertNotCalled(t, "LeadershipTransferToServer", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Nonvoter", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfiguration(mockRaft, servers...)
+
+		newLeader(mockRaft, "node3").handOverToPreferredLeader()
+		mockRaft.AssertNotCalled(t, "LeadershipTransferToServer", mock.Anything, mock.Anything)
+	})
+
+	t.Run("UnknownServer", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfiguration(mockRaft, servers...)
+
+		newLeader(mockRaft, "node4").handOverToPreferredLeader()
+		mockRaft.AssertNotCalled(t, "LeadershipTransferToServer", mock.Anything, mock.Anything)
+	})
+}
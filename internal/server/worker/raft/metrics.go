@@ -0,0 +1,247 @@
+package raft
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/VictoriaMetrics/metrics"
+	gometrics "github.com/hashicorp/go-metrics/compat"
+	hraft "github.com/hashicorp/raft"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	metricsInterval    = 10 * time.Second
+	metricsServiceName = "maf"
+)
+
+var initMetricsSinkOnce sync.Once
+
+// metricsSink bridges the go-metrics emitted by hashicorp/raft into the VictoriaMetrics registry,
+// so they are exposed on /metrics along with the rest. Timers are reported in milliseconds.
+type metricsSink struct {
+	set *metrics.Set
+}
+
+func newMetricsSink(set *metrics.Set) *metricsSink {
+	return &metricsSink{set: set}
+}
+
+// initMetricsSink installs the sink globally, as raft reports to the global go-metrics instance.
+func initMetricsSink() {
+	initMetricsSinkOnce.Do(func() {
+		conf := gometrics.DefaultConfig(metricsServiceName)
+		conf.EnableHostname = false
+		conf.EnableRuntimeMetrics = false
+
+		if _, err := gometrics.NewGlobal(conf, newMetricsSink(metrics.GetDefaultSet())); err != nil {
+			log.Warn().Err(err).Msg("Failed to configure raft metrics")
+		}
+	})
+}
+
+func (s *metricsSink) SetGauge(key []string, val float32) {
+	s.SetGaugeWithLabels(key, val, nil)
+}
+
+func (s *metricsSink) SetGaugeWithLabels(key []string, val float32, labels []gometrics.Label) {
+	s.set.GetOrCreateGauge(metricName(key, "", labels), nil).Set(float64(val))
+}
+
+func (s *metricsSink) EmitKey(key []string, val float32) {
+	s.SetGauge(key, val)
+}
+
+func (s *metricsSink) IncrCounter(key []string, val float32) {
+	s.IncrCounterWithLabels(key, val, nil)
+}
+
+func (s *metricsSink) IncrCounterWithLabels(key []string, val float32, labels []gometrics.Label) {
+	s.set.GetOrCreateFloatCounter(metricName(key, "_total", labels)).Add(float64(val))
+}
+
+func (s *metricsSink) AddSample(key []string, val float32) {
+	s.AddSampleWithLabels(key, val, nil)
+}
+
+func (s *metricsSink) AddSampleWithLabels(key []string, val float32, labels []gometrics.Label) {
+	s.set.GetOrCreateHistogram(metricName(key, "", labels)).Update(float64(val))
+}
+
+// metricName converts the go-metrics key, e.g. [maf raft commitTime], to the Prometheus name maf_raft_commit_time.
+func metricName(key []string, suffix string, labels []gometrics.Label) string {
+	var name strings.Builder
+
+	for i, part := range key {
+		if i > 0 {
+			name.WriteByte('_')
+		}
+
+		name.WriteString(snakeCase(part))
+	}
+
+	name.WriteString(suffix)
+
+	if len(labels) == 0 {
+		return name.String()
+	}
+
+	name.WriteByte('{')
+
+	for i, label := range labels {
+		if i > 0 {
+			name.WriteByte(',')
+		}
+
+		fmt.Fprintf(&name, "%s=%q", snakeCase(label.Name), label.Value)
+	}
+
+	name.WriteByte('}')
+
+	return name.String()
+}
+
+func snakeCase(s string) string {
+	var out strings.Builder
+
+	prevLower := false
+
+	for _, r := range s {
+		switch {
+		case unicode.IsUpper(r):
+			if prevLower {
+				out.WriteByte('_')
+			}
+
+			out.WriteRune(unicode.ToLower(r))
+
+			prevLower = false
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			out.WriteRune(r)
+
+			prevLower = true
+		default:
+			out.WriteByte('_')
+
+			prevLower = false
+		}
+	}
+
+	return out.String()
+}
+
+// initMetrics registers the gauges read from the raft state on every scrape.
+func (r *Raft) initMetrics() {
+	r.logger.Trace().Msg("Initializing metrics")
+
+	initMetricsSink()
+
+	r.metrics = metrics.NewSet()
+	r.metrics.NewGauge("maf_raft_leader", func() float64 {
+		if r.IsLeader() {
+			return 1
+		}
+
+		return 0
+	})
+
+	for name, stat := range map[string]string{
+		"maf_raft_term":                "term",
+		"maf_raft_commit_index":        "commit_index",
+		"maf_raft_applied_index":       "applied_index",
+		"maf_raft_last_log_index":      "last_log_index",
+		"maf_raft_last_snapshot_index": "last_snapshot_index",
+	} {
+		r.metrics.NewGauge(name, func() float64 {
+			return r.statValue(stat)
+		})
+	}
+
+	r.metrics.NewGauge("maf_fsm_keys", func() float64 {
+		return float64(r.storage.Len())
+	})
+
+	metrics.RegisterSet(r.metrics)
+}
+
+func (r *Raft) statValue(stat string) float64 {
+	value, err := strconv.ParseFloat(r.raftInstance.Stats()[stat], 64)
+	if err != nil {
+		return 0
+	}
+
+	return value
+}
+
+// runMetrics periodically updates the replication lag of the peers, in log entries. Only the leader reports it.
+func (r *Raft) runMetrics() {
+	go func() {
+		ticker := time.NewTicker(metricsInterval)
+		defer ticker.Stop()
+
+		lagGauges := make(map[string]struct{})
+
+		for {
+			select {
+			case <-ticker.C:
+				if r.IsLeader() {
+					lagGauges = r.updateReplicationLag(lagGauges)
+				} else {
+					lagGauges = r.clearReplicationLag(lagGauges)
+				}
+			case <-r.done:
+				r.logger.Info().Msg("Stopping metrics")
+				metrics.UnregisterSet(r.metrics, true)
+
+				return
+			}
+		}
+	}()
+}
+
+func (r *Raft) updateReplicationLag(previous map[string]struct{}) map[string]struct{} {
+	leaderIndex := uint64(r.statValue("last_log_index"))
+	current := make(map[string]struct{})
+
+	var servers []hraft.Server
+
+	cfgFuture := r.raftInstance.GetConfiguration()
+	if err := cfgFuture.Error(); err != nil {
+		r.logger.Warn().Err(err).Msg("Failed to get raft configuration, polling the peers only")
+	} else {
+		servers = cfgFuture.Configuration().Servers
+	}
+
+	for id, lastIndex := range r.pollServerIndexes(servers) {
+		if string(id) == r.config.NodeID {
+			continue
+		}
+
+		lag := uint64(0)
+		if leaderIndex > lastIndex {
+			lag = leaderIndex - lastIndex
+		}
+
+		name := fmt.Sprintf("maf_raft_peer_replication_lag{peer=%q}", id)
+		r.metrics.GetOrCreateGauge(name, nil).Set(float64(lag))
+		current[name] = struct{}{}
+		delete(previous, name)
+	}
+
+	// Peers which left the cluster or can't be polled stop reporting rather than keep a stale value
+	r.clearReplicationLag(previous)
+
+	return current
+}
+
+func (r *Raft) clearReplicationLag(gauges map[string]struct{}) map[string]struct{} {
+	for name := range gauges {
+		r.metrics.UnregisterMetric(name)
+	}
+
+	return make(map[string]struct{})
+}
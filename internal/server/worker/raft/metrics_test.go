@@ -0,0 +1,144 @@
+package raft
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/VictoriaMetrics/metrics"
+	gometrics "github.com/hashicorp/go-metrics/compat"
+	hraft "github.com/hashicorp/raft"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeMetrics(set *metrics.Set) string {
+	var buf bytes.Buffer
+
+	set.WritePrometheus(&buf)
+
+	return buf.String()
+}
+
+func TestMetricName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		key      []string
+		suffix   string
+		labels   []gometrics.Label
+		expected string
+	}{
+		{
+			name:     "camel case",
+			key:      []string{"maf", "raft", "commitTime"},
+			expected: "maf_raft_commit_time",
+		},
+		{
+			name:     "counter suffix",
+			key:      []string{"maf", "raft", "state", "leader"},
+			suffix:   "_total",
+			expected: "maf_raft_state_leader_total",
+		},
+		{
+			name:     "invalid characters",
+			key:      []string{"maf", "raft", "replication", "heartbeat", "maf-2.dc1"},
+			expected: "maf_raft_replication_heartbeat_maf_2_dc1",
+		},
+		{
+			name:     "labels",
+			key:      []string{"maf", "raft", "replication", "appendEntries", "rpc"},
+			labels:   []gometrics.Label{{Name: "peer_id", Value: "maf-2"}, {Name: "rpcType", Value: "heartbeat"}},
+			expected: `maf_raft_replication_append_entries_rpc{peer_id="maf-2",rpc_type="heartbeat"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, metricName(tt.key, tt.suffix, tt.labels))
+		})
+	}
+}
+
+func TestMetricsSink(t *testing.T) {
+	t.Parallel()
+
+	set := metrics.NewSet()
+	sink := newMetricsSink(set)
+
+	sink.SetGauge([]string{"maf", "raft", "peers"}, 3)
+	sink.IncrCounter([]string{"maf", "raft", "apply"}, 1)
+	sink.IncrCounter([]string{"maf", "raft", "apply"}, 2)
+	sink.AddSampleWithLabels(
+		[]string{"maf", "raft", "fsm", "apply"},
+		1.5,
+		[]gometrics.Label{{Name: "type", Value: "set"}},
+	)
+
+	out := writeMetrics(set)
+	assert.Contains(t, out, "maf_raft_peers 3\n")
+	assert.Contains(t, out, "maf_raft_apply_total 3\n")
+	assert.Contains(t, out, `maf_raft_fsm_apply_count{type="set"} 1`)
+}
+
+func TestInitMetrics(t *testing.T) {
+	t.Parallel()
+
+	mockRaft := new(MockHRaft)
+	mockRaft.On("State").Return(hraft.Leader)
+	mockRaft.On("Stats").Return(map[string]string{
+		"term":                "7",
+		"commit_index":        "42",
+		"applied_index":       "41",
+		"last_log_index":      "42",
+		"last_snapshot_index": "invalid",
+	})
+
+	storage := NewSafeStorage()
+	storage.Set("key", "value")
+
+	raft := &Raft{raftInstance: mockRaft, storage: storage, logger: log.Logger}
+	raft.initMetrics()
+
+	defer metrics.UnregisterSet(raft.metrics, true)
+
+	out := writeMetrics(raft.metrics)
+	assert.Contains(t, out, "maf_raft_leader 1\n")
+	assert.Contains(t, out, "maf_raft_term 7\n")
+	assert.Contains(t, out, "maf_raft_commit_index 42\n")
+	assert.Contains(t, out, "maf_raft_applied_index 41\n")
+	assert.Contains(t, out, "maf_raft_last_snapshot_index 0\n")
+	assert.Contains(t, out, "maf_fsm_keys 1\n")
+}
+
+func TestUpdateReplicationLag(t *testing.T) {
+	t.Parallel()
+
+	mockRaft := new(MockHRaft)
+	mockRaft.On("Stats").Return(map[string]string{"last_log_index": "100"})
+	mockConfiguration(mockRaft)
+
+	raft := newAutopilotRaft(mockRaft, map[string]any{
+		"http://node1": peerStats("node1", "100"),
+		"http://node2": peerStats("node2", "90"),
+		"http://node3": peerStats("node3", "100"),
+	})
+	raft.metrics = metrics.NewSet()
+
+	stale := `maf_raft_peer_replication_lag{peer="node4"}`
+	raft.metrics.GetOrCreateGauge(stale, nil).Set(5)
+
+	gauges := raft.updateReplicationLag(map[string]struct{}{stale: {}})
+	assert.Len(t, gauges, 2)
+
+	out := writeMetrics(raft.metrics)
+	assert.Contains(t, out, `maf_raft_peer_replication_lag{peer="node2"} 10`)
+	assert.Contains(t, out, `maf_raft_peer_replication_lag{peer="node3"} 0`)
+	assert.NotContains(t, out, `peer="node1"`, "expected the leader to skip itself")
+	assert.NotContains(t, out, `peer="node4"`, "expected the lag of the gone peer to be removed")
+
+	raft.clearReplicationLag(gauges)
+	assert.NotContains(t, writeMetrics(raft.metrics), "maf_raft_peer_replication_lag")
+}
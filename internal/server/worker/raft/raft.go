@@ -1,6 +1,7 @@
 package raft
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,10 +9,12 @@ import (
 	neturl "net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/VictoriaMetrics/metrics"
 	"github.com/hashicorp/go-hclog"
 	hraft "github.com/hashicorp/raft"
 	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
@@ -19,15 +22,14 @@ import (
 	"github.com/rs/zerolog/log"
 	hclogzerolog "github.com/weastur/hclog-zerolog"
 	apiClient "github.com/weastur/maf/internal/server/client"
+	"github.com/weastur/maf/internal/server/worker/raft/discovery"
 	"github.com/weastur/maf/internal/utils/logging"
 )
 
 const (
 	datadirPerms     = 0o700
-	transportMaxPool = 3
-	transportTimeout = 10 * time.Second
 	retryJoinDelay   = time.Second
-	snapshotRetain   = 2
+	discoveryTimeout = 5 * time.Second
 	dbName           = "raft.db"
 	cmdTimeout       = 10 * time.Second
 )
@@ -40,30 +42,50 @@ type Sentry interface {
 
 type Config struct {
 	Addr               string
+	Advertise          string
 	NodeID             string
 	Devmode            bool
 	Peers              []string
+	Discovery          discovery.Provider
 	Datadir            string
 	Bootstrap          bool
+	Nonvoter           bool
+	JoinToken          string
+	LeaveOnTerminate   bool
+	PreferredLeader    string
+	Autopilot          *AutopilotConfig
+	Tuning             *TuningConfig
+	TLS                *TLSConfig
 	ServerAPITLSConfig *apiClient.TLSConfig
+	ServerAPIToken     string
+	// APIAdvertise is the API address of this server, the other servers are assumed to use the same scheme and port
+	APIAdvertise string
 }
 
 type HRaft interface {
 	State() hraft.RaftState
 	BootstrapCluster(configuration hraft.Configuration) hraft.Future
 	LeadershipTransfer() hraft.Future
+	LeadershipTransferToServer(id hraft.ServerID, address hraft.ServerAddress) hraft.Future
 	Shutdown() hraft.Future
 	GetConfiguration() hraft.ConfigurationFuture
 	RemoveServer(id hraft.ServerID, prevIndex uint64, timeout time.Duration) hraft.IndexFuture
 	AddVoter(id hraft.ServerID, address hraft.ServerAddress, prevIndex uint64, timeout time.Duration) hraft.IndexFuture
+	AddNonvoter(id hraft.ServerID, address hraft.ServerAddress, prevIndex uint64, timeout time.Duration) hraft.IndexFuture
+	DemoteVoter(id hraft.ServerID, prevIndex uint64, timeout time.Duration) hraft.IndexFuture
 	LeaderWithID() (hraft.ServerAddress, hraft.ServerID)
 	Stats() map[string]string
 	Apply(cmd []byte, timeout time.Duration) hraft.ApplyFuture
 	LeaderCh() <-chan bool
+	RegisterObserver(or *hraft.Observer)
+	DeregisterObserver(or *hraft.Observer)
 }
 
 type APIClient interface {
-	RaftJoin(nodeID, addr string) error
+	RaftJoin(nodeID, addr string, nonvoter bool, token string) error
+	RaftInfo(includeStats bool) (any, error)
+	RaftForget(serverID string) error
+	AuditAppend(entry any) error
 	Close() error
 }
 
@@ -84,6 +106,11 @@ type Raft struct {
 	leadershipChangesChannels []LeadershipChangesCh
 	sentry                    Sentry
 	getAPIClient              func(string) APIClient
+	autopilot                 *autopilot
+	metrics                   *metrics.Set
+	events                    *eventBus
+	// peersMu guards the static peers and the discovery provider of the config, which change on the config reload
+	peersMu sync.RWMutex
 }
 
 func New(config *Config, sentry Sentry) *Raft {
@@ -95,13 +122,35 @@ func New(config *Config, sentry Sentry) *Raft {
 		logger:                    log.With().Str(logging.ComponentCtxKey, "raft").Logger(),
 		hlogger:                   hclogzerolog.New(log.With().Str(logging.ComponentCtxKey, "hraft").Logger()),
 		leadershipChangesChannels: make([]LeadershipChangesCh, 0),
+		events:                    newEventBus(),
 		sentry:                    sentry,
 		getAPIClient: func(peer string) APIClient {
-			return apiClient.NewWithAutoTLS(peer, config.ServerAPITLSConfig, true)
+			return apiClient.NewWithAutoTLS(peer, config.ServerAPITLSConfig, true).SetAuthToken(config.ServerAPIToken)
 		},
 	}
 }
 
+// AdvertiseAddr returns the address other servers use to reach this one.
+// It falls back to the bind address if no advertise address is configured.
+func (c *Config) AdvertiseAddr() string {
+	if c.Advertise != "" {
+		return c.Advertise
+	}
+
+	return c.Addr
+}
+
+func isWildcardAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+
+	ip := net.ParseIP(host)
+
+	return host == "" || (ip != nil && ip.IsUnspecified())
+}
+
 func (r *Raft) IsReady() bool {
 	return r.initCompleted.Load()
 }
@@ -120,7 +169,14 @@ func (r *Raft) init() {
 	r.initStore()
 	r.initFSM()
 	r.initRaftInstance()
+	r.initMetrics()
+	r.initAutopilot()
 	r.monitorLeadership()
+	r.watchEvents()
+	r.runMetrics()
+	r.runAutopilot()
+	r.runPreferredLeader()
+	r.runJoinTokenSweeper()
 
 	if r.config.Bootstrap {
 		r.bootstrap()
@@ -158,7 +214,7 @@ func (r *Raft) retryJoin() {
 	r.logger.Info().Msg("Retrying to join peers")
 
 	for {
-		for _, peer := range r.config.Peers {
+		for _, peer := range r.peers() {
 			r.logger.Debug().Msgf("Joining peer %s", peer)
 
 			peerURL, err := neturl.Parse(peer)
@@ -175,7 +231,9 @@ func (r *Raft) retryJoin() {
 			}
 
 			api := r.getAPIClient(peer)
-			if err := api.RaftJoin(r.config.NodeID, r.config.Addr); err != nil {
+
+			err = api.RaftJoin(r.config.NodeID, r.config.AdvertiseAddr(), r.config.Nonvoter, r.config.JoinToken)
+			if err != nil {
 				r.logger.Warn().Err(err).Msgf("Failed to join peer %s", peer)
 				api.Close()
 			} else {
@@ -191,6 +249,42 @@ func (r *Raft) retryJoin() {
 	}
 }
 
+// SetDiscovery replaces the static peers and the discovery provider, e.g. on the config reload.
+// They are used from the next join attempt on.
+func (r *Raft) SetDiscovery(peers []string, provider discovery.Provider) {
+	r.peersMu.Lock()
+	defer r.peersMu.Unlock()
+
+	r.config.Peers = peers
+	r.config.Discovery = provider
+}
+
+// peers returns the API addresses of the servers. They are re-resolved with the discovery provider on every call,
+// the static peers list is used if there is no provider or it fails.
+func (r *Raft) peers() []string {
+	r.peersMu.RLock()
+	staticPeers, provider := r.config.Peers, r.config.Discovery
+	r.peersMu.RUnlock()
+
+	if provider == nil {
+		return staticPeers
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+
+	peers, err := provider.Discover(ctx)
+	if err != nil {
+		r.logger.Warn().Err(err).Msg("Failed to discover peers, falling back to the static list")
+
+		return staticPeers
+	}
+
+	r.logger.Debug().Msgf("Discovered peers: %v", peers)
+
+	return peers
+}
+
 func (r *Raft) initRaftInstance() {
 	var err error
 
@@ -202,7 +296,10 @@ func (r *Raft) initRaftInstance() {
 
 func (r *Raft) initFSM() {
 	r.storage = NewSafeStorage()
-	r.fsm = NewFSM(r.storage)
+
+	fsm := NewFSM(r.storage)
+	fsm.events = r.events
+	r.fsm = fsm
 }
 
 func (r *Raft) initStore() {
@@ -232,21 +329,49 @@ func (r *Raft) initSnapshotStore() {
 
 	r.logger.Trace().Msg("Initializing snapshot store")
 
-	r.snapshotStore, err = hraft.NewFileSnapshotStoreWithLogger(r.config.Datadir, snapshotRetain, r.hlogger)
+	r.snapshotStore, err = hraft.NewFileSnapshotStoreWithLogger(
+		r.config.Datadir,
+		r.config.Tuning.snapshotRetain(),
+		r.hlogger,
+	)
 	if err != nil {
 		panic("Failed to create snapshot store")
 	}
 }
 
 func (r *Raft) initTransport() {
-	r.logger.Trace().Msgf("Initializing transport for %s", r.config.Addr)
+	r.logger.Trace().Msgf("Initializing transport for %s advertised as %s", r.config.Addr, r.config.AdvertiseAddr())
 
-	addr, err := net.ResolveTCPAddr("tcp", r.config.Addr)
+	addr, err := net.ResolveTCPAddr("tcp", r.config.AdvertiseAddr())
 	if err != nil {
 		panic("Failed to resolve TCP address")
 	}
 
-	r.transport, err = hraft.NewTCPTransportWithLogger(r.config.Addr, addr, transportMaxPool, transportTimeout, r.hlogger)
+	if r.config.TLS.IsEnabled() {
+		r.logger.Info().Msg("Using TLS transport")
+
+		stream, err := NewTLSStreamLayer(r.config.Addr, addr, r.config.TLS)
+		if err != nil {
+			panic("Failed to create TLS stream layer")
+		}
+
+		r.transport = hraft.NewNetworkTransportWithLogger(
+			stream,
+			r.config.Tuning.transportMaxPool(),
+			r.config.Tuning.transportTimeout(),
+			r.hlogger,
+		)
+
+		return
+	}
+
+	r.transport, err = hraft.NewTCPTransportWithLogger(
+		r.config.Addr,
+		addr,
+		r.config.Tuning.transportMaxPool(),
+		r.config.Tuning.transportTimeout(),
+		r.hlogger,
+	)
 	if err != nil {
 		panic("Failed to create TCP transport")
 	}
@@ -259,6 +384,19 @@ func (r *Raft) configureRaft() {
 	r.logger.Trace().Msgf("Raft server ID: %s", r.config.NodeID)
 	r.hrconfig.LocalID = hraft.ServerID(r.config.NodeID)
 	r.hrconfig.Logger = r.hlogger
+	r.config.Tuning.apply(r.hrconfig)
+
+	r.logger.Debug().Msgf(
+		"Raft timeouts: heartbeat %s, election %s, leader lease %s",
+		r.hrconfig.HeartbeatTimeout,
+		r.hrconfig.ElectionTimeout,
+		r.hrconfig.LeaderLeaseTimeout,
+	)
+
+	if err := hraft.ValidateConfig(r.hrconfig); err != nil {
+		r.logger.Error().Err(err).Msg("Invalid raft configuration")
+		panic("Invalid raft configuration")
+	}
 }
 
 func (r *Raft) ensureDatadir() {
@@ -294,6 +432,12 @@ func (r *Raft) Stop() {
 		return
 	}
 
+	if r.config.LeaveOnTerminate {
+		if err := r.Leave(); err != nil {
+			r.logger.Error().Err(err).Msg("Failed to leave the cluster")
+		}
+	}
+
 	if r.IsLeader() {
 		r.logger.Info().Msg("I'm the leader, stepping down")
 
@@ -313,8 +457,14 @@ func (r *Raft) IsLeader() bool {
 	return r.raftInstance.State() == hraft.Leader
 }
 
-func (r *Raft) Join(serverID, addr string) error {
-	r.logger.Trace().Msgf("Joining %s at %s", serverID, addr)
+func (r *Raft) Join(serverID, addr string, nonvoter bool, token string) error {
+	r.logger.Trace().Msgf("Joining %s at %s (non-voter: %t)", serverID, addr, nonvoter)
+
+	if isWildcardAddr(addr) {
+		r.logger.Warn().Msgf("Refusing to join %s with non-advertisable address %s", serverID, addr)
+
+		return ErrWildcardAddr
+	}
 
 	if !r.IsLeader() {
 		r.logger.Warn().Msg("I'm not a leader, can't proceed with join")
@@ -329,19 +479,44 @@ func (r *Raft) Join(serverID, addr string) error {
 		return fmt.Errorf("failed to get raft configuration: %w", err)
 	}
 
-	cfg := cfgFuture.Configuration()
+	servers := cfgFuture.Configuration().Servers
 
 	rNodeID := hraft.ServerID(serverID)
 	rAddr := hraft.ServerAddress(addr)
 
-	// Check if the server is already a member of the cluster or needs to be removed first
-	// due to address or ID change.
-	for _, srv := range cfg.Servers {
-		if srv.ID == rNodeID && srv.Address == rAddr {
-			r.logger.Info().Msgf("node %s at %s already member of cluster, ignoring join request", serverID, addr)
+	// Restarted members rejoin on every start, so they don't need a token as long as nothing changes.
+	// The suffrage is left as is, it's managed by the operator and autopilot, not by the joining server
+	if slices.ContainsFunc(servers, func(srv hraft.Server) bool {
+		return srv.ID == rNodeID && srv.Address == rAddr
+	}) {
+		r.logger.Info().Msgf("node %s at %s already member of cluster, ignoring join request", serverID, addr)
 
-			return nil
-		} else if srv.ID == rNodeID || srv.Address == rAddr {
+		return nil
+	}
+
+	tokenID, err := r.validateJoinToken(token)
+	if err != nil {
+		r.logger.Warn().Msgf("Refusing to join %s at %s: %s", serverID, addr, err)
+
+		return err
+	}
+
+	if err := r.join(servers, rNodeID, rAddr, nonvoter); err != nil {
+		return err
+	}
+
+	r.recordJoin(serverID, addr, nonvoter, tokenID)
+
+	return nil
+}
+
+func (r *Raft) join(servers []hraft.Server, rNodeID hraft.ServerID, rAddr hraft.ServerAddress, nonvoter bool) error {
+	serverID, addr := string(rNodeID), string(rAddr)
+
+	// Check if the server needs to be removed first due to address or ID change.
+	// The same server is never here, it's already a member and its suffrage isn't changed on rejoin
+	for _, srv := range servers {
+		if srv.ID == rNodeID || srv.Address == rAddr {
 			r.logger.Info().Msgf("node %s at %s already member of cluster, removing existing node", serverID, addr)
 
 			idxFuture := r.raftInstance.RemoveServer(srv.ID, 0, 0)
@@ -350,17 +525,136 @@ func (r *Raft) Join(serverID, addr string) error {
 
 				return fmt.Errorf("failed to remove existing node %s at %s: %w", srv.ID, srv.Address, err)
 			}
+
+			r.publishMembership(membershipActionRemove, srv)
 		}
 	}
 
-	idxFuture := r.raftInstance.AddVoter(rNodeID, rAddr, 0, 0)
-	if err := idxFuture.Error(); err != nil {
-		r.logger.Err(err).Msg("Failed to add voter")
+	joined := hraft.Server{ID: rNodeID, Address: rAddr, Suffrage: hraft.Voter}
+
+	if nonvoter || r.autopilotEnabled() {
+		joined.Suffrage = hraft.Nonvoter
 
-		return fmt.Errorf("failed to add voter: %w", err)
+		idxFuture := r.raftInstance.AddNonvoter(rNodeID, rAddr, 0, 0)
+		if err := idxFuture.Error(); err != nil {
+			r.logger.Err(err).Msg("Failed to add non-voter")
+
+			return fmt.Errorf("failed to add non-voter: %w", err)
+		}
+
+		// Servers which asked to be voters are introduced as non-voters and promoted by autopilot
+		// once they are stable and caught up, so a flapping newcomer can't harm the quorum
+		if !nonvoter {
+			if err := r.stage(rNodeID); err != nil {
+				r.logger.Err(err).Msg("Failed to stage new server")
+
+				return fmt.Errorf("failed to stage new server: %w", err)
+			}
+		}
+	} else {
+		idxFuture := r.raftInstance.AddVoter(rNodeID, rAddr, 0, 0)
+		if err := idxFuture.Error(); err != nil {
+			r.logger.Err(err).Msg("Failed to add voter")
+
+			return fmt.Errorf("failed to add voter: %w", err)
+		}
 	}
 
 	r.logger.Info().Msgf("Successfully added %s at %s", serverID, addr)
+	r.publishMembership(membershipActionJoin, joined)
+
+	return nil
+}
+
+func (r *Raft) findServer(serverID string) (*hraft.Server, error) {
+	cfgFuture := r.raftInstance.GetConfiguration()
+	if err := cfgFuture.Error(); err != nil {
+		r.logger.Error().Err(err).Msg("Failed to get raft configuration")
+
+		return nil, fmt.Errorf("failed to get raft configuration: %w", err)
+	}
+
+	for _, srv := range cfgFuture.Configuration().Servers {
+		if srv.ID == hraft.ServerID(serverID) {
+			return &srv, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownServer, serverID)
+}
+
+func (r *Raft) Promote(serverID string) error {
+	r.logger.Trace().Msgf("Promote %s", serverID)
+
+	if !r.IsLeader() {
+		r.logger.Warn().Msg("I'm not a leader, can't proceed with promote")
+
+		return ErrNotALeader
+	}
+
+	srv, err := r.findServer(serverID)
+	if err != nil {
+		return err
+	}
+
+	if srv.Suffrage == hraft.Voter {
+		r.logger.Info().Msgf("node %s is already a voter, ignoring promote request", serverID)
+
+		return nil
+	}
+
+	idxFuture := r.raftInstance.AddVoter(srv.ID, srv.Address, 0, 0)
+	if err := idxFuture.Error(); err != nil {
+		r.logger.Err(err).Msgf("Failed to promote node %s", serverID)
+
+		return fmt.Errorf("failed to promote node %s: %w", serverID, err)
+	}
+
+	r.logger.Info().Msgf("Successfully promoted %s", serverID)
+	r.publishMembership(membershipActionPromote, hraft.Server{ID: srv.ID, Address: srv.Address, Suffrage: hraft.Voter})
+
+	if err := r.unstage(srv.ID); err != nil {
+		r.logger.Err(err).Msgf("Failed to clear staging mark of node %s", serverID)
+	}
+
+	return nil
+}
+
+func (r *Raft) Demote(serverID string) error {
+	r.logger.Trace().Msgf("Demote %s", serverID)
+
+	if !r.IsLeader() {
+		r.logger.Warn().Msg("I'm not a leader, can't proceed with demote")
+
+		return ErrNotALeader
+	}
+
+	srv, err := r.findServer(serverID)
+	if err != nil {
+		return err
+	}
+
+	if srv.Suffrage != hraft.Voter {
+		r.logger.Info().Msgf("node %s is not a voter, ignoring demote request", serverID)
+
+		return nil
+	}
+
+	return r.demote(*srv)
+}
+
+func (r *Raft) demote(srv hraft.Server) error {
+	idxFuture := r.raftInstance.DemoteVoter(srv.ID, 0, 0)
+	if err := idxFuture.Error(); err != nil {
+		r.logger.Err(err).Msgf("Failed to demote node %s", srv.ID)
+
+		return fmt.Errorf("failed to demote node %s: %w", srv.ID, err)
+	}
+
+	r.logger.Info().Msgf("Successfully demoted %s", srv.ID)
+
+	srv.Suffrage = hraft.Nonvoter
+	r.publishMembership(membershipActionDemote, srv)
 
 	return nil
 }
@@ -381,6 +675,102 @@ func (r *Raft) Forget(serverID string) error {
 		return fmt.Errorf("failed to remove existing node %s: %w", serverID, err)
 	}
 
+	r.publishMembership(membershipActionRemove, hraft.Server{ID: hraft.ServerID(serverID)})
+
+	if err := r.unstage(hraft.ServerID(serverID)); err != nil {
+		r.logger.Err(err).Msgf("Failed to clear staging mark of node %s", serverID)
+	}
+
+	return nil
+}
+
+// Leave removes the local server from the cluster, so it no longer counts toward the quorum.
+// The leader hands over the leadership first and then asks the new leader to remove it.
+// Each peer is asked once, the caller retries if no leader is reachable.
+func (r *Raft) Leave() error {
+	r.logger.Info().Msg("Leaving the cluster")
+
+	cfgFuture := r.raftInstance.GetConfiguration()
+	if err := cfgFuture.Error(); err != nil {
+		r.logger.Error().Err(err).Msg("Failed to get raft configuration")
+
+		return fmt.Errorf("failed to get raft configuration: %w", err)
+	}
+
+	servers := cfgFuture.Configuration().Servers
+
+	if !slices.ContainsFunc(servers, func(srv hraft.Server) bool { return srv.ID == hraft.ServerID(r.config.NodeID) }) {
+		r.logger.Info().Msg("I'm not a member of the cluster, nothing to leave")
+
+		return nil
+	}
+
+	if err := r.checkLeaveQuorum(servers); err != nil {
+		return err
+	}
+
+	if r.IsLeader() {
+		r.logger.Info().Msg("I'm the leader, transferring leadership before leaving")
+
+		if err := r.raftInstance.LeadershipTransfer().Error(); err != nil {
+			r.logger.Error().Err(err).Msg("Failed to transfer leadership")
+
+			return fmt.Errorf("failed to transfer leadership: %w", err)
+		}
+	}
+
+	for _, peer := range r.peers() {
+		api := r.getAPIClient(peer)
+		err := api.RaftForget(r.config.NodeID)
+		api.Close()
+
+		if err == nil {
+			r.logger.Info().Msgf("Successfully left the cluster through peer %s", peer)
+
+			return nil
+		}
+
+		if errors.Is(err, apiClient.ErrUnauthorized) || errors.Is(err, apiClient.ErrForbidden) {
+			r.logger.Error().Err(err).Msgf("Peer %s refused the server API token", peer)
+
+			return fmt.Errorf("%w: %w", ErrLeaveForbidden, err)
+		}
+
+		r.logger.Debug().Err(err).Msgf("Failed to leave the cluster through peer %s", peer)
+	}
+
+	return ErrNoLeader
+}
+
+// checkLeaveQuorum refuses to remove a voter if the rest of the voters can't form the quorum.
+// Only the leader with autopilot enabled knows which servers are failing, others assume all are healthy.
+func (r *Raft) checkLeaveQuorum(servers []hraft.Server) error {
+	selfID := hraft.ServerID(r.config.NodeID)
+	voters := 0
+	healthy := 0
+
+	for _, srv := range servers {
+		if srv.ID == selfID && srv.Suffrage != hraft.Voter {
+			return nil
+		}
+
+		if srv.Suffrage != hraft.Voter || srv.ID == selfID {
+			continue
+		}
+
+		voters++
+
+		if !r.autopilotEnabled() || !r.autopilot.isFailing(srv.ID) {
+			healthy++
+		}
+	}
+
+	if voters == 0 || healthy < voters/2+1 {
+		r.logger.Warn().Msgf("Refusing to leave, only %d of remaining %d voters are healthy", healthy, voters)
+
+		return ErrLeaveQuorum
+	}
+
 	return nil
 }
 
@@ -397,11 +787,12 @@ func (r *Raft) GetInfo(verbose bool) (*Info, error) {
 	cfg := cfgFuture.Configuration()
 
 	info := &Info{
-		ID:      r.config.NodeID,
-		Addr:    r.config.Addr,
-		State:   r.raftInstance.State().String(),
-		Servers: make([]Server, 0),
-		Stats:   nil,
+		ID:        r.config.NodeID,
+		Addr:      r.config.Addr,
+		Advertise: r.config.AdvertiseAddr(),
+		State:     r.raftInstance.State().String(),
+		Servers:   make([]Server, 0),
+		Stats:     nil,
 	}
 
 	lAddr, lID := r.raftInstance.LeaderWithID()
@@ -422,9 +813,14 @@ func (r *Raft) GetInfo(verbose bool) (*Info, error) {
 	return info, nil
 }
 
+// Get returns the value of the user key, the reserved keys are reported as missing
 func (r *Raft) Get(key string) (string, bool) {
 	r.logger.Trace().Msgf("Getting key %s", key)
 
+	if IsReservedKey(key) {
+		return "", false
+	}
+
 	return r.storage.Get(key)
 }
 
@@ -450,20 +846,28 @@ func (r *Raft) applyCommand(op OpType, key, value string) error {
 }
 
 func (r *Raft) Set(key, value string) error {
+	if IsReservedKey(key) {
+		return fmt.Errorf("%w: %s", ErrReservedKey, key)
+	}
+
 	if !r.IsLeader() {
 		r.logger.Warn().Msg("I'm not a leader, can't proceed with set")
 
-		return nil
+		return ErrNotALeader
 	}
 
 	return r.applyCommand(OpSet, key, value)
 }
 
 func (r *Raft) Delete(key string) error {
+	if IsReservedKey(key) {
+		return fmt.Errorf("%w: %s", ErrReservedKey, key)
+	}
+
 	if !r.IsLeader() {
 		r.logger.Warn().Msg("I'm not a leader, can't proceed with delete")
 
-		return nil
+		return ErrNotALeader
 	}
 
 	return r.applyCommand(OpDelete, key, "")
@@ -476,6 +880,8 @@ func (r *Raft) SubscribeOnLeadershipChanges(ch LeadershipChangesCh) {
 }
 
 func (r *Raft) broadcastLeadershipChange(isLeader bool) {
+	r.publishLeadership(isLeader)
+
 	for _, ch := range r.leadershipChangesChannels {
 		select {
 		case ch <- isLeader:
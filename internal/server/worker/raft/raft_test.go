@@ -1,6 +1,7 @@
 package raft
 
 import (
+	"context"
 	"errors"
 	"io"
 	"os"
@@ -16,6 +17,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	hclogzerolog "github.com/weastur/hclog-zerolog"
+	apiClient "github.com/weastur/maf/internal/server/client"
 	"github.com/weastur/maf/internal/utils/logging"
 	sentryWrapper "github.com/weastur/maf/internal/utils/sentry"
 )
@@ -103,6 +105,37 @@ func (m *MockHRaft) AddVoter(
 	return args.Get(0).(hraft.IndexFuture)
 }
 
+func (m *MockHRaft) AddNonvoter(
+	id hraft.ServerID,
+	address hraft.ServerAddress,
+	prevIndex uint64,
+	timeout time.Duration,
+) hraft.IndexFuture {
+	args := m.Called(id, address, prevIndex, timeout)
+
+	return args.Get(0).(hraft.IndexFuture)
+}
+
+func (m *MockHRaft) LeadershipTransferToServer(id hraft.ServerID, address hraft.ServerAddress) hraft.Future {
+	args := m.Called(id, address)
+
+	return args.Get(0).(hraft.Future)
+}
+
+func (m *MockHRaft) RegisterObserver(or *hraft.Observer) {
+	m.Called(or)
+}
+
+func (m *MockHRaft) DeregisterObserver(or *hraft.Observer) {
+	m.Called(or)
+}
+
+func (m *MockHRaft) DemoteVoter(id hraft.ServerID, prevIndex uint64, timeout time.Duration) hraft.IndexFuture {
+	args := m.Called(id, prevIndex, timeout)
+
+	return args.Get(0).(hraft.IndexFuture)
+}
+
 func (m *MockHRaft) LeaderWithID() (hraft.ServerAddress, hraft.ServerID) {
 	args := m.Called()
 
@@ -389,8 +422,26 @@ type MockAPIClient struct {
 	mock.Mock
 }
 
-func (m *MockAPIClient) RaftJoin(nodeID, addr string) error {
-	args := m.Called(nodeID, addr)
+func (m *MockAPIClient) RaftJoin(nodeID, addr string, nonvoter bool, token string) error {
+	args := m.Called(nodeID, addr, nonvoter, token)
+
+	return args.Error(0)
+}
+
+func (m *MockAPIClient) RaftInfo(includeStats bool) (any, error) {
+	args := m.Called(includeStats)
+
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockAPIClient) RaftForget(serverID string) error {
+	args := m.Called(serverID)
+
+	return args.Error(0)
+}
+
+func (m *MockAPIClient) AuditAppend(entry any) error {
+	args := m.Called(entry)
 
 	return args.Error(0)
 }
@@ -445,6 +496,13 @@ func TestNew(t *testing.T) {
 	assert.Empty(t, raft.leadershipChangesChannels, "expected leadershipChangesChannels to be empty")
 }
 
+func TestConfigAdvertiseAddr(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "127.0.0.1:7081", (&Config{Addr: "127.0.0.1:7081"}).AdvertiseAddr())
+	assert.Equal(t, "10.1.2.3:7081", (&Config{Addr: "0.0.0.0:7081", Advertise: "10.1.2.3:7081"}).AdvertiseAddr())
+}
+
 func TestIsReady(t *testing.T) {
 	t.Parallel()
 
@@ -591,6 +649,51 @@ func TestConfigureRaft(t *testing.T) {
 		assert.NotNil(t, raft.hrconfig, "expected hrconfig to be initialized")
 		assert.Equal(t, hraft.ServerID(config.NodeID), raft.hrconfig.LocalID, "expected LocalID to match NodeID")
 		assert.Equal(t, raft.hlogger, raft.hrconfig.Logger, "expected Logger to match hlogger")
+		assert.Equal(t, hraft.DefaultConfig().HeartbeatTimeout, raft.hrconfig.HeartbeatTimeout)
+	})
+
+	t.Run("Tuning", func(t *testing.T) {
+		t.Parallel()
+
+		config := &Config{
+			NodeID: "node1",
+			Tuning: &TuningConfig{
+				HeartbeatTimeout:   5 * time.Second,
+				ElectionTimeout:    10 * time.Second,
+				LeaderLeaseTimeout: 2 * time.Second,
+				SnapshotThreshold:  1024,
+				TrailingLogs:       2048,
+			},
+		}
+		raft := &Raft{
+			config:  config,
+			hlogger: hclogzerolog.New(log.With().Str(logging.ComponentCtxKey, "hraft").Logger()),
+		}
+
+		raft.configureRaft()
+		assert.Equal(t, 5*time.Second, raft.hrconfig.HeartbeatTimeout)
+		assert.Equal(t, 10*time.Second, raft.hrconfig.ElectionTimeout)
+		assert.Equal(t, 2*time.Second, raft.hrconfig.LeaderLeaseTimeout)
+		assert.Equal(t, uint64(1024), raft.hrconfig.SnapshotThreshold)
+		assert.Equal(t, uint64(2048), raft.hrconfig.TrailingLogs)
+		assert.Equal(t, hraft.DefaultConfig().SnapshotInterval, raft.hrconfig.SnapshotInterval)
+	})
+
+	t.Run("InvalidTuning", func(t *testing.T) {
+		t.Parallel()
+
+		config := &Config{
+			NodeID: "node1",
+			Tuning: &TuningConfig{HeartbeatTimeout: 5 * time.Second, ElectionTimeout: time.Second},
+		}
+		raft := &Raft{
+			config:  config,
+			hlogger: hclogzerolog.New(log.With().Str(logging.ComponentCtxKey, "hraft").Logger()),
+		}
+
+		assert.Panics(t, func() {
+			raft.configureRaft()
+		}, "expected configureRaft to panic for election timeout lower than heartbeat timeout")
 	})
 }
 
@@ -616,6 +719,25 @@ func TestInitTransport(t *testing.T) {
 		assert.Equal(t, config.Addr, string(raft.transport.LocalAddr()), "expected transport address to match config address")
 	})
 
+	t.Run("AdvertiseAddress", func(t *testing.T) {
+		t.Parallel()
+
+		config := &Config{
+			Addr:      "0.0.0.0:0",
+			Advertise: "10.1.2.3:7081",
+		}
+		raft := &Raft{
+			config:  config,
+			hlogger: hclogzerolog.New(log.With().Str(logging.ComponentCtxKey, "hraft").Logger()),
+		}
+
+		assert.NotPanics(t, func() {
+			raft.initTransport()
+		}, "expected initTransport to not panic for wildcard bind with advertise address")
+
+		assert.Equal(t, config.Advertise, string(raft.transport.LocalAddr()), "expected transport to advertise")
+	})
+
 	t.Run("InvalidAddress", func(t *testing.T) {
 		t.Parallel()
 
@@ -982,6 +1104,21 @@ func TestGet(t *testing.T) {
 		assert.Empty(t, value, "expected value to be empty")
 		mockStorage.AssertExpectations(t)
 	})
+
+	t.Run("ReservedKey", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := new(MockStorage)
+
+		raft := &Raft{
+			storage: mockStorage,
+		}
+
+		value, found := raft.Get(auditHeadKey)
+		assert.False(t, found, "expected reserved key to be hidden")
+		assert.Empty(t, value, "expected value to be empty")
+		mockStorage.AssertNotCalled(t, "Get", auditHeadKey)
+	})
 }
 
 func TestApplyCommand(t *testing.T) {
@@ -1076,8 +1213,9 @@ func TestSet(t *testing.T) {
 		}
 
 		err := raft.Set("key", "value")
-		require.NoError(t, err, "expected Set to not return an error for non-leader state")
+		require.ErrorIs(t, err, ErrNotALeader, "expected Set to be refused for non-leader state")
 		mockRaft.AssertExpectations(t)
+		mockRaft.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
 	})
 
 	t.Run("ApplyError", func(t *testing.T) {
@@ -1100,6 +1238,21 @@ func TestSet(t *testing.T) {
 		mockRaft.AssertExpectations(t)
 		mockApplyFuture.AssertExpectations(t)
 	})
+
+	t.Run("ReservedKey", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+
+		raft := &Raft{
+			raftInstance: mockRaft,
+			logger:       log.Logger,
+		}
+
+		err := raft.Set(joinTokenPrefix+"abc", "{}")
+		require.ErrorIs(t, err, ErrReservedKey)
+		mockRaft.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+	})
 }
 
 func TestDelete(t *testing.T) {
@@ -1138,8 +1291,9 @@ func TestDelete(t *testing.T) {
 		}
 
 		err := raft.Delete("key")
-		require.NoError(t, err, "expected Delete to not return an error for non-leader state")
+		require.ErrorIs(t, err, ErrNotALeader, "expected Delete to be refused for non-leader state")
 		mockRaft.AssertExpectations(t)
+		mockRaft.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
 	})
 
 	t.Run("ApplyError", func(t *testing.T) {
@@ -1162,6 +1316,21 @@ func TestDelete(t *testing.T) {
 		mockRaft.AssertExpectations(t)
 		mockApplyFuture.AssertExpectations(t)
 	})
+
+	t.Run("ReservedKey", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+
+		raft := &Raft{
+			raftInstance: mockRaft,
+			logger:       log.Logger,
+		}
+
+		err := raft.Delete(joinTokenPrefix + "abc")
+		require.ErrorIs(t, err, ErrReservedKey)
+		mockRaft.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+	})
 }
 
 func TestGetInfo(t *testing.T) {
@@ -1199,6 +1368,7 @@ func TestGetInfo(t *testing.T) {
 		require.NoError(t, err, "expected GetInfo to succeed")
 		assert.Equal(t, "node1", info.ID, "expected node ID to match")
 		assert.Equal(t, "127.0.0.1:8080", info.Addr, "expected address to match")
+		assert.Equal(t, "127.0.0.1:8080", info.Advertise, "expected advertise address to fall back to address")
 		assert.Equal(t, "Leader", info.State, "expected state to match")
 		assert.Len(t, info.Servers, 1, "expected one server in the cluster")
 		assert.Equal(t, "node1", info.Servers[0].ID, "expected server ID to match")
@@ -1278,6 +1448,7 @@ func TestStop(t *testing.T) {
 
 		mockRaft := new(MockHRaft)
 		raft := &Raft{
+			config:       &Config{},
 			raftInstance: mockRaft,
 			logger:       log.Logger,
 			done:         make(chan struct{}),
@@ -1304,6 +1475,7 @@ func TestStop(t *testing.T) {
 		mockFuture.On("Error").Return(nil)
 
 		raft := &Raft{
+			config:       &Config{},
 			raftInstance: mockRaft,
 			logger:       log.Logger,
 			done:         make(chan struct{}),
@@ -1333,6 +1505,7 @@ func TestStop(t *testing.T) {
 		mockApplyFuture.On("Error").Return(nil)
 
 		raft := &Raft{
+			config:       &Config{},
 			raftInstance: mockRaft,
 			logger:       log.Logger,
 			done:         make(chan struct{}),
@@ -1361,6 +1534,7 @@ func TestStop(t *testing.T) {
 		mockRaft.On("Shutdown").Return(mockApplyFuture)
 
 		raft := &Raft{
+			config:       &Config{},
 			raftInstance: mockRaft,
 			logger:       log.Logger,
 			done:         make(chan struct{}),
@@ -1392,6 +1566,7 @@ func TestStop(t *testing.T) {
 		mockApplyFuture.On("Error").Return(nil)
 
 		raft := &Raft{
+			config:       &Config{},
 			raftInstance: mockRaft,
 			logger:       log.Logger,
 			done:         make(chan struct{}),
@@ -1498,7 +1673,7 @@ func TestRetryJoin(t *testing.T) {
 		t.Parallel()
 
 		mockAPIClient := new(MockAPIClient)
-		mockAPIClient.On("RaftJoin", "node1", "127.0.0.1:8080").Return(nil)
+		mockAPIClient.On("RaftJoin", "node1", "127.0.0.1:8080", false, "").Return(nil)
 		mockAPIClient.On("Close").Return(nil)
 
 		raft := &Raft{
@@ -1522,12 +1697,41 @@ func TestRetryJoin(t *testing.T) {
 		mockAPIClient.AssertExpectations(t)
 	})
 
+	t.Run("JoinWithAdvertiseAddr", func(t *testing.T) {
+		t.Parallel()
+
+		mockAPIClient := new(MockAPIClient)
+		mockAPIClient.On("RaftJoin", "node1", "10.1.2.3:8080", false, "").Return(nil)
+		mockAPIClient.On("Close").Return(nil)
+
+		raft := &Raft{
+			config: &Config{
+				NodeID:    "node1",
+				Addr:      "0.0.0.0:8080",
+				Advertise: "10.1.2.3:8080",
+				Peers:     []string{"http://127.0.0.1:8081"},
+			},
+			logger: log.Logger,
+			getAPIClient: func(_ string) APIClient {
+				return mockAPIClient
+			},
+			initCompleted: atomic.Bool{},
+		}
+
+		go raft.retryJoin()
+
+		time.Sleep(100 * time.Millisecond) // Allow retryJoin to execute
+
+		assert.True(t, raft.initCompleted.Load(), "expected initCompleted to be set to true after successful join")
+		mockAPIClient.AssertExpectations(t)
+	})
+
 	t.Run("JoinFailsAndRetries", func(t *testing.T) {
 		t.Parallel()
 
 		mockAPIClient := new(MockAPIClient)
-		mockAPIClient.On("RaftJoin", "node1", "127.0.0.1:8080").Return(errors.New("join error")).Twice()
-		mockAPIClient.On("RaftJoin", "node1", "127.0.0.1:8080").Return(nil).Once()
+		mockAPIClient.On("RaftJoin", "node1", "127.0.0.1:8080", false, "").Return(errors.New("join error")).Twice()
+		mockAPIClient.On("RaftJoin", "node1", "127.0.0.1:8080", false, "").Return(nil).Once()
 		mockAPIClient.On("Close").Return(nil)
 
 		raft := &Raft{
@@ -1671,6 +1875,7 @@ func TestJoin(t *testing.T) {
 		t.Parallel()
 
 		mockRaft := new(MockHRaft)
+		mockApply(mockRaft)
 		mockConfigFuture := new(MockConfigurationFuture)
 		mockIndexFuture := new(MockIndexFuture)
 
@@ -1695,20 +1900,43 @@ func TestJoin(t *testing.T) {
 				Addr:   "127.0.0.1:8080",
 			},
 			raftInstance: mockRaft,
+			storage:      newJoinTokenStorage(),
 			logger:       log.Logger,
 		}
 
-		err := raft.Join("node2", "127.0.0.1:8081")
+		err := raft.Join("node2", "127.0.0.1:8081", false, testJoinToken)
 		require.NoError(t, err, "expected Join to succeed")
 		mockRaft.AssertExpectations(t)
 		mockConfigFuture.AssertExpectations(t)
 		mockIndexFuture.AssertExpectations(t)
 	})
 
+	t.Run("WildcardAddr", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockApply(mockRaft)
+
+		raft := &Raft{
+			config: &Config{
+				NodeID: "node1",
+				Addr:   "127.0.0.1:8080",
+			},
+			raftInstance: mockRaft,
+			storage:      newJoinTokenStorage(),
+			logger:       log.Logger,
+		}
+
+		err := raft.Join("node2", "0.0.0.0:8081", false, testJoinToken)
+		require.ErrorIs(t, err, ErrWildcardAddr, "expected Join to refuse wildcard address")
+		mockRaft.AssertNotCalled(t, "AddVoter")
+	})
+
 	t.Run("AlreadyMember", func(t *testing.T) {
 		t.Parallel()
 
 		mockRaft := new(MockHRaft)
+		mockApply(mockRaft)
 		mockConfigFuture := new(MockConfigurationFuture)
 
 		mockConfigFuture.On("Error").Return(nil)
@@ -1729,10 +1957,11 @@ func TestJoin(t *testing.T) {
 				Addr:   "127.0.0.1:8080",
 			},
 			raftInstance: mockRaft,
+			storage:      newJoinTokenStorage(),
 			logger:       log.Logger,
 		}
 
-		err := raft.Join("node2", "127.0.0.1:8081")
+		err := raft.Join("node2", "127.0.0.1:8081", false, testJoinToken)
 		require.NoError(t, err, "expected Join to succeed for already existing member")
 		mockRaft.AssertExpectations(t)
 		mockConfigFuture.AssertExpectations(t)
@@ -1742,6 +1971,7 @@ func TestJoin(t *testing.T) {
 		t.Parallel()
 
 		mockRaft := new(MockHRaft)
+		mockApply(mockRaft)
 		mockConfigFuture := new(MockConfigurationFuture)
 		mockIndexFuture := new(MockIndexFuture)
 
@@ -1772,10 +2002,11 @@ func TestJoin(t *testing.T) {
 				Addr:   "127.0.0.1:8080",
 			},
 			raftInstance: mockRaft,
+			storage:      newJoinTokenStorage(),
 			logger:       log.Logger,
 		}
 
-		err := raft.Join("node2", "127.0.0.1:8081")
+		err := raft.Join("node2", "127.0.0.1:8081", false, testJoinToken)
 		require.NoError(t, err, "expected Join to succeed after removing existing node")
 		mockRaft.AssertExpectations(t)
 		mockConfigFuture.AssertExpectations(t)
@@ -1786,6 +2017,7 @@ func TestJoin(t *testing.T) {
 		t.Parallel()
 
 		mockRaft := new(MockHRaft)
+		mockApply(mockRaft)
 		mockConfigFuture := new(MockConfigurationFuture)
 		mockIndexFuture := new(MockIndexFuture)
 
@@ -1809,10 +2041,11 @@ func TestJoin(t *testing.T) {
 				Addr:   "127.0.0.1:8080",
 			},
 			raftInstance: mockRaft,
+			storage:      newJoinTokenStorage(),
 			logger:       log.Logger,
 		}
 
-		err := raft.Join("node3", "127.0.0.1:8081")
+		err := raft.Join("node3", "127.0.0.1:8081", false, testJoinToken)
 		require.Error(t, err, "expected Join to fail due to RemoveServer error")
 		mockRaft.AssertExpectations(t)
 		mockConfigFuture.AssertExpectations(t)
@@ -1823,6 +2056,7 @@ func TestJoin(t *testing.T) {
 		t.Parallel()
 
 		mockRaft := new(MockHRaft)
+		mockApply(mockRaft)
 		mockRaft.On("State").Return(hraft.Follower)
 
 		raft := &Raft{
@@ -1831,10 +2065,11 @@ func TestJoin(t *testing.T) {
 				Addr:   "127.0.0.1:8080",
 			},
 			raftInstance: mockRaft,
+			storage:      newJoinTokenStorage(),
 			logger:       log.Logger,
 		}
 
-		err := raft.Join("node2", "127.0.0.1:8081")
+		err := raft.Join("node2", "127.0.0.1:8081", false, testJoinToken)
 		require.ErrorIs(t, err, ErrNotALeader, "expected Join to fail when not a leader")
 		mockRaft.AssertExpectations(t)
 	})
@@ -1843,6 +2078,7 @@ func TestJoin(t *testing.T) {
 		t.Parallel()
 
 		mockRaft := new(MockHRaft)
+		mockApply(mockRaft)
 		mockConfigFuture := new(MockConfigurationFuture)
 
 		mockConfigFuture.On("Error").Return(errors.New("configuration error"))
@@ -1855,10 +2091,11 @@ func TestJoin(t *testing.T) {
 				Addr:   "127.0.0.1:8080",
 			},
 			raftInstance: mockRaft,
+			storage:      newJoinTokenStorage(),
 			logger:       log.Logger,
 		}
 
-		err := raft.Join("node2", "127.0.0.1:8081")
+		err := raft.Join("node2", "127.0.0.1:8081", false, testJoinToken)
 		require.Error(t, err, "expected Join to fail due to configuration error")
 		mockRaft.AssertExpectations(t)
 		mockConfigFuture.AssertExpectations(t)
@@ -1868,6 +2105,7 @@ func TestJoin(t *testing.T) {
 		t.Parallel()
 
 		mockRaft := new(MockHRaft)
+		mockApply(mockRaft)
 		mockConfigFuture := new(MockConfigurationFuture)
 		mockIndexFuture := new(MockIndexFuture)
 
@@ -1892,13 +2130,541 @@ func TestJoin(t *testing.T) {
 				Addr:   "127.0.0.1:8080",
 			},
 			raftInstance: mockRaft,
+			storage:      newJoinTokenStorage(),
 			logger:       log.Logger,
 		}
 
-		err := raft.Join("node2", "127.0.0.1:8081")
+		err := raft.Join("node2", "127.0.0.1:8081", false, testJoinToken)
 		require.Error(t, err, "expected Join to fail due to AddVoter error")
 		mockRaft.AssertExpectations(t)
 		mockConfigFuture.AssertExpectations(t)
 		mockIndexFuture.AssertExpectations(t)
 	})
+	t.Run("JoinAsNonvoter", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockApply(mockRaft)
+		mockConfigFuture := new(MockConfigurationFuture)
+		mockIndexFuture := new(MockIndexFuture)
+
+		mockConfigFuture.On("Error").Return(nil)
+		mockConfigFuture.On("Configuration").Return(hraft.Configuration{
+			Servers: []hraft.Server{},
+		})
+		mockRaft.On("GetConfiguration").Return(mockConfigFuture)
+		mockRaft.On(
+			"AddNonvoter",
+			hraft.ServerID("node2"),
+			hraft.ServerAddress("127.0.0.1:8081"),
+			uint64(0),
+			time.Duration(0),
+		).Return(mockIndexFuture)
+		mockIndexFuture.On("Error").Return(nil)
+		mockRaft.On("State").Return(hraft.Leader)
+
+		raft := &Raft{
+			config: &Config{
+				NodeID: "node1",
+				Addr:   "127.0.0.1:8080",
+			},
+			raftInstance: mockRaft,
+			storage:      newJoinTokenStorage(),
+			logger:       log.Logger,
+		}
+
+		err := raft.Join("node2", "127.0.0.1:8081", true, testJoinToken)
+		require.NoError(t, err, "expected Join to succeed")
+		mockRaft.AssertExpectations(t)
+		mockRaft.AssertNotCalled(t, "AddVoter")
+	})
+
+	t.Run("RejoinKeepsSuffrage", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name     string
+			suffrage hraft.ServerSuffrage
+			nonvoter bool
+		}{
+			{name: "voter asks to be non-voter", suffrage: hraft.Voter, nonvoter: true},
+			{name: "demoted voter asks to be voter", suffrage: hraft.Nonvoter, nonvoter: false},
+		}
+
+		for _, test := range tests {
+			mockRaft := new(MockHRaft)
+			mockConfigFuture := new(MockConfigurationFuture)
+
+			mockConfigFuture.On("Error").Return(nil)
+			mockConfigFuture.On("Configuration").Return(hraft.Configuration{
+				Servers: []hraft.Server{
+					{
+						ID:       hraft.ServerID("node2"),
+						Address:  hraft.ServerAddress("127.0.0.1:8081"),
+						Suffrage: test.suffrage,
+					},
+				},
+			})
+			mockRaft.On("GetConfiguration").Return(mockConfigFuture)
+			mockRaft.On("State").Return(hraft.Leader)
+
+			raft := &Raft{
+				config: &Config{
+					NodeID: "node1",
+					Addr:   "127.0.0.1:8080",
+				},
+				raftInstance: mockRaft,
+				storage:      newJoinTokenStorage(),
+				logger:       log.Logger,
+			}
+
+			// the token of the first join is long expired
+			err := raft.Join("node2", "127.0.0.1:8081", test.nonvoter, "expired-token")
+			require.NoError(t, err, test.name)
+			mockRaft.AssertExpectations(t)
+			mockRaft.AssertNotCalled(t, "DemoteVoter", mock.Anything, mock.Anything, mock.Anything)
+			mockRaft.AssertNotCalled(t, "AddVoter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockRaft.AssertNotCalled(t, "AddNonvoter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockRaft.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("AlreadyNonvoterMember", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockApply(mockRaft)
+		mockConfigFuture := new(MockConfigurationFuture)
+
+		mockConfigFuture.On("Error").Return(nil)
+		mockConfigFuture.On("Configuration").Return(hraft.Configuration{
+			Servers: []hraft.Server{
+				{
+					ID:       hraft.ServerID("node2"),
+					Address:  hraft.ServerAddress("127.0.0.1:8081"),
+					Suffrage: hraft.Nonvoter,
+				},
+			},
+		})
+		mockRaft.On("GetConfiguration").Return(mockConfigFuture)
+		mockRaft.On("State").Return(hraft.Leader)
+
+		raft := &Raft{
+			config: &Config{
+				NodeID: "node1",
+				Addr:   "127.0.0.1:8080",
+			},
+			raftInstance: mockRaft,
+			storage:      newJoinTokenStorage(),
+			logger:       log.Logger,
+		}
+
+		err := raft.Join("node2", "127.0.0.1:8081", true, testJoinToken)
+		require.NoError(t, err, "expected Join to succeed for already existing non-voter")
+		mockRaft.AssertExpectations(t)
+		mockRaft.AssertNotCalled(t, "AddNonvoter")
+	})
+}
+
+func TestPromote(t *testing.T) {
+	t.Parallel()
+
+	nonvoterConfiguration := hraft.Configuration{
+		Servers: []hraft.Server{
+			{
+				ID:       hraft.ServerID("node2"),
+				Address:  hraft.ServerAddress("127.0.0.1:8081"),
+				Suffrage: hraft.Nonvoter,
+			},
+		},
+	}
+
+	t.Run("SuccessfulPromote", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfigFuture := new(MockConfigurationFuture)
+		mockIndexFuture := new(MockIndexFuture)
+
+		mockConfigFuture.On("Error").Return(nil)
+		mockConfigFuture.On("Configuration").Return(nonvoterConfiguration)
+		mockRaft.On("GetConfiguration").Return(mockConfigFuture)
+		mockRaft.On(
+			"AddVoter",
+			hraft.ServerID("node2"),
+			hraft.ServerAddress("127.0.0.1:8081"),
+			uint64(0),
+			time.Duration(0),
+		).Return(mockIndexFuture)
+		mockIndexFuture.On("Error").Return(nil)
+		mockRaft.On("State").Return(hraft.Leader)
+
+		raft := &Raft{config: &Config{NodeID: "node1"}, raftInstance: mockRaft, logger: log.Logger}
+
+		require.NoError(t, raft.Promote("node2"))
+		mockRaft.AssertExpectations(t)
+		mockIndexFuture.AssertExpectations(t)
+	})
+
+	t.Run("AlreadyVoter", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfigFuture := new(MockConfigurationFuture)
+
+		mockConfigFuture.On("Error").Return(nil)
+		mockConfigFuture.On("Configuration").Return(testConfiguration)
+		mockRaft.On("GetConfiguration").Return(mockConfigFuture)
+		mockRaft.On("State").Return(hraft.Leader)
+
+		raft := &Raft{config: &Config{NodeID: "node1"}, raftInstance: mockRaft, logger: log.Logger}
+
+		require.NoError(t, raft.Promote(string(testConfiguration.Servers[0].ID)))
+		mockRaft.AssertNotCalled(t, "AddVoter")
+	})
+
+	t.Run("UnknownServer", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfigFuture := new(MockConfigurationFuture)
+
+		mockConfigFuture.On("Error").Return(nil)
+		mockConfigFuture.On("Configuration").Return(nonvoterConfiguration)
+		mockRaft.On("GetConfiguration").Return(mockConfigFuture)
+		mockRaft.On("State").Return(hraft.Leader)
+
+		raft := &Raft{config: &Config{NodeID: "node1"}, raftInstance: mockRaft, logger: log.Logger}
+
+		require.ErrorIs(t, raft.Promote("node3"), ErrUnknownServer)
+	})
+
+	t.Run("NotALeader", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockRaft.On("State").Return(hraft.Follower)
+
+		raft := &Raft{config: &Config{NodeID: "node1"}, raftInstance: mockRaft, logger: log.Logger}
+
+		require.ErrorIs(t, raft.Promote("node2"), ErrNotALeader)
+		mockRaft.AssertNotCalled(t, "GetConfiguration")
+	})
+}
+
+func TestDemote(t *testing.T) {
+	t.Parallel()
+
+	voterConfiguration := hraft.Configuration{
+		Servers: []hraft.Server{
+			{
+				ID:       hraft.ServerID("node2"),
+				Address:  hraft.ServerAddress("127.0.0.1:8081"),
+				Suffrage: hraft.Voter,
+			},
+		},
+	}
+
+	t.Run("SuccessfulDemote", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfigFuture := new(MockConfigurationFuture)
+		mockIndexFuture := new(MockIndexFuture)
+
+		mockConfigFuture.On("Error").Return(nil)
+		mockConfigFuture.On("Configuration").Return(voterConfiguration)
+		mockRaft.On("GetConfiguration").Return(mockConfigFuture)
+		mockRaft.On("DemoteVoter", hraft.ServerID("node2"), uint64(0), time.Duration(0)).Return(mockIndexFuture)
+		mockIndexFuture.On("Error").Return(nil)
+		mockRaft.On("State").Return(hraft.Leader)
+
+		raft := &Raft{config: &Config{NodeID: "node1"}, raftInstance: mockRaft, logger: log.Logger}
+
+		require.NoError(t, raft.Demote("node2"))
+		mockRaft.AssertExpectations(t)
+		mockIndexFuture.AssertExpectations(t)
+	})
+
+	t.Run("DemoteVoterError", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfigFuture := new(MockConfigurationFuture)
+		mockIndexFuture := new(MockIndexFuture)
+
+		mockConfigFuture.On("Error").Return(nil)
+		mockConfigFuture.On("Configuration").Return(voterConfiguration)
+		mockRaft.On("GetConfiguration").Return(mockConfigFuture)
+		mockRaft.On("DemoteVoter", hraft.ServerID("node2"), uint64(0), time.Duration(0)).Return(mockIndexFuture)
+		mockIndexFuture.On("Error").Return(errors.New("demote error"))
+		mockRaft.On("State").Return(hraft.Leader)
+
+		raft := &Raft{config: &Config{NodeID: "node1"}, raftInstance: mockRaft, logger: log.Logger}
+
+		require.Error(t, raft.Demote("node2"))
+	})
+
+	t.Run("UnknownServer", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockConfigFuture := new(MockConfigurationFuture)
+
+		mockConfigFuture.On("Error").Return(nil)
+		mockConfigFuture.On("Configuration").Return(voterConfiguration)
+		mockRaft.On("GetConfiguration").Return(mockConfigFuture)
+		mockRaft.On("State").Return(hraft.Leader)
+
+		raft := &Raft{config: &Config{NodeID: "node1"}, raftInstance: mockRaft, logger: log.Logger}
+
+		require.ErrorIs(t, raft.Demote("node3"), ErrUnknownServer)
+	})
+
+	t.Run("NotALeader", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockRaft.On("State").Return(hraft.Follower)
+
+		raft := &Raft{config: &Config{NodeID: "node1"}, raftInstance: mockRaft, logger: log.Logger}
+
+		require.ErrorIs(t, raft.Demote("node2"), ErrNotALeader)
+	})
+}
+
+func TestLeave(t *testing.T) {
+	t.Parallel()
+
+	servers := []hraft.Server{
+		{ID: "node1", Address: "127.0.0.1:7081", Suffrage: hraft.Voter},
+		{ID: "node2", Address: "127.0.0.1:7082", Suffrage: hraft.Voter},
+		{ID: "node3", Address: "127.0.0.1:7083", Suffrage: hraft.Voter},
+	}
+
+	newLeavingRaft := func(mockRaft *MockHRaft, mockAPIClient *MockAPIClient) *Raft {
+		return &Raft{
+			config:       &Config{NodeID: "node1", Peers: []string{"http://node2"}},
+			raftInstance: mockRaft,
+			logger:       log.Logger,
+			getAPIClient: func(_ string) APIClient {
+				return mockAPIClient
+			},
+		}
+	}
+
+	t.Run("Follower", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockAPIClient := new(MockAPIClient)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On("State").Return(hraft.Follower)
+		mockAPIClient.On("RaftForget", "node1").Return(nil).Once()
+		mockAPIClient.On("Close").Return(nil)
+
+		require.NoError(t, newLeavingRaft(mockRaft, mockAPIClient).Leave())
+		mockAPIClient.AssertExpectations(t)
+		mockRaft.AssertNotCalled(t, "LeadershipTransfer")
+	})
+
+	t.Run("LeaderTransfersLeadershipFirst", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockAPIClient := new(MockAPIClient)
+		mockFuture := new(MockFuture)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On("State").Return(hraft.Leader)
+		mockRaft.On("LeadershipTransfer").Return(mockFuture)
+		mockFuture.On("Error").Return(nil)
+		mockAPIClient.On("RaftForget", "node1").Return(nil).Once()
+		mockAPIClient.On("Close").Return(nil)
+
+		require.NoError(t, newLeavingRaft(mockRaft, mockAPIClient).Leave())
+		mockRaft.AssertExpectations(t)
+		mockAPIClient.AssertExpectations(t)
+	})
+
+	t.Run("ForbiddenIsTerminal", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockAPIClient := new(MockAPIClient)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On("State").Return(hraft.Follower)
+		mockAPIClient.On("RaftForget", "node1").Return(apiClient.ErrForbidden).Once()
+		mockAPIClient.On("Close").Return(nil)
+
+		raft := newLeavingRaft(mockRaft, mockAPIClient)
+		raft.config.Peers = []string{"http://node2", "http://node3"}
+
+		err := raft.Leave()
+
+		require.ErrorIs(t, err, ErrLeaveForbidden)
+		require.ErrorIs(t, err, apiClient.ErrForbidden)
+		mockAPIClient.AssertNumberOfCalls(t, "RaftForget", 1)
+	})
+
+	t.Run("NoLeaderAfterSinglePass", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockAPIClient := new(MockAPIClient)
+		mockConfiguration(mockRaft, servers...)
+		mockRaft.On("State").Return(hraft.Follower)
+		mockAPIClient.On("RaftForget", "node1").Return(apiClient.ErrNotLeader)
+		mockAPIClient.On("Close").Return(nil)
+
+		raft := newLeavingRaft(mockRaft, mockAPIClient)
+		raft.config.Peers = []string{"http://node2", "http://node3"}
+
+		require.ErrorIs(t, raft.Leave(), ErrNoLeader)
+		mockAPIClient.AssertNumberOfCalls(t, "RaftForget", 2)
+	})
+
+	t.Run("NotAMember", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockAPIClient := new(MockAPIClient)
+		mockConfiguration(mockRaft, servers[1:]...)
+
+		require.NoError(t, newLeavingRaft(mockRaft, mockAPIClient).Leave())
+		mockAPIClient.AssertNotCalled(t, "RaftForget", mock.Anything)
+	})
+
+	t.Run("LastVoter", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockAPIClient := new(MockAPIClient)
+		mockConfiguration(mockRaft, servers[0])
+
+		require.ErrorIs(t, newLeavingRaft(mockRaft, mockAPIClient).Leave(), ErrLeaveQuorum)
+		mockAPIClient.AssertNotCalled(t, "RaftForget", mock.Anything)
+	})
+
+	t.Run("FailingVoters", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockAPIClient := new(MockAPIClient)
+		mockConfiguration(mockRaft, servers...)
+
+		raft := newLeavingRaft(mockRaft, mockAPIClient)
+		raft.autopilot = &autopilot{
+			failingSince: map[hraft.ServerID]time.Time{"node3": time.Now()},
+			healthySince: make(map[hraft.ServerID]time.Time),
+		}
+
+		require.ErrorIs(t, raft.Leave(), ErrLeaveQuorum)
+		mockAPIClient.AssertNotCalled(t, "RaftForget", mock.Anything)
+	})
+
+	t.Run("Nonvoter", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockAPIClient := new(MockAPIClient)
+		mockConfiguration(mockRaft, hraft.Server{ID: "node1", Address: "127.0.0.1:7081", Suffrage: hraft.Nonvoter})
+		mockRaft.On("State").Return(hraft.Follower)
+		mockAPIClient.On("RaftForget", "node1").Return(nil).Once()
+		mockAPIClient.On("Close").Return(nil)
+
+		require.NoError(t, newLeavingRaft(mockRaft, mockAPIClient).Leave())
+		mockAPIClient.AssertExpectations(t)
+	})
+}
+
+func TestStopLeaveOnTerminate(t *testing.T) {
+	t.Parallel()
+
+	mockRaft := new(MockHRaft)
+	mockFuture := new(MockFuture)
+	mockAPIClient := new(MockAPIClient)
+
+	mockConfiguration(mockRaft, testConfiguration.Servers...)
+	mockRaft.On("State").Return(hraft.Follower)
+	mockRaft.On("Shutdown").Return(mockFuture)
+	mockFuture.On("Error").Return(nil)
+	mockAPIClient.On("RaftForget", "node1").Return(nil).Once()
+	mockAPIClient.On("Close").Return(nil)
+
+	raft := &Raft{
+		config:       &Config{NodeID: "node1", Peers: []string{"http://node2"}, LeaveOnTerminate: true},
+		raftInstance: mockRaft,
+		logger:       log.Logger,
+		done:         make(chan struct{}),
+		getAPIClient: func(_ string) APIClient {
+			return mockAPIClient
+		},
+	}
+
+	raft.initCompleted.Store(true)
+	raft.Stop()
+
+	mockRaft.AssertExpectations(t)
+	mockAPIClient.AssertExpectations(t)
+}
+
+type MockDiscovery struct {
+	mock.Mock
+}
+
+func (m *MockDiscovery) Discover(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func TestPeers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Static", func(t *testing.T) {
+		t.Parallel()
+
+		raft := &Raft{config: &Config{Peers: []string{"http://node1"}}, logger: log.Logger}
+
+		assert.Equal(t, []string{"http://node1"}, raft.peers())
+	})
+
+	t.Run("Discovered", func(t *testing.T) {
+		t.Parallel()
+
+		mockDiscovery := new(MockDiscovery)
+		mockDiscovery.On("Discover", mock.Anything).Return([]string{"http://node2", "http://node3"}, nil).Once()
+
+		raft := &Raft{config: &Config{Peers: []string{"http://node1"}, Discovery: mockDiscovery}, logger: log.Logger}
+
+		assert.Equal(t, []string{"http://node2", "http://node3"}, raft.peers())
+		mockDiscovery.AssertExpectations(t)
+	})
+
+	t.Run("FallbackOnError", func(t *testing.T) {
+		t.Parallel()
+
+		mockDiscovery := new(MockDiscovery)
+		mockDiscovery.On("Discover", mock.Anything).Return([]string{}, errors.New("lookup failed")).Once()
+
+		raft := &Raft{config: &Config{Peers: []string{"http://node1"}, Discovery: mockDiscovery}, logger: log.Logger}
+
+		assert.Equal(t, []string{"http://node1"}, raft.peers())
+	})
+}
+
+func TestSetDiscovery(t *testing.T) {
+	t.Parallel()
+
+	mockDiscovery := new(MockDiscovery)
+	mockDiscovery.On("Discover", mock.Anything).Return([]string{"http://node3"}, nil).Once()
+
+	raft := &Raft{config: &Config{Peers: []string{"http://node1"}}, logger: log.Logger}
+
+	raft.SetDiscovery([]string{"http://node2"}, nil)
+	assert.Equal(t, []string{"http://node2"}, raft.peers())
+
+	raft.SetDiscovery([]string{"http://node2"}, mockDiscovery)
+	assert.Equal(t, []string{"http://node3"}, raft.peers())
+	mockDiscovery.AssertExpectations(t)
 }
@@ -0,0 +1,125 @@
+package raft
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	hraft "github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"github.com/rs/zerolog/log"
+	hclogzerolog "github.com/weastur/hclog-zerolog"
+	"github.com/weastur/maf/internal/utils/logging"
+	"go.etcd.io/bbolt"
+)
+
+var ErrLocalNodeNotInPeers = errors.New("local node is not in the recovery peers list")
+
+type RecoveryPlan struct {
+	NodeID        string   `json:"nodeId"`
+	Datadir       string   `json:"datadir"`
+	LastIndex     uint64   `json:"lastIndex"`
+	LastTerm      uint64   `json:"lastTerm"`
+	SnapshotIndex uint64   `json:"snapshotIndex"`
+	Configuration []Server `json:"configuration"`
+	Peers         []Server `json:"peers"`
+}
+
+// PlanRecovery validates the peers file against the local node and reports the local raft state,
+// so the operator can compare it across the surviving servers and pick the most up-to-date one.
+func PlanRecovery(datadir, nodeID, peersFile string) (*RecoveryPlan, error) {
+	peers, err := readRecoveryPeers(nodeID, peersFile)
+	if err != nil {
+		return nil, err
+	}
+
+	inspection, err := Inspect(datadir, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &RecoveryPlan{
+		NodeID:        nodeID,
+		Datadir:       datadir,
+		LastIndex:     inspection.LastIndex,
+		LastTerm:      inspection.LastTerm,
+		Configuration: inspection.Configuration,
+		Peers:         convertServers(peers.Servers),
+	}
+
+	if len(inspection.Snapshots) > 0 {
+		plan.SnapshotIndex = inspection.Snapshots[0].Index
+	}
+
+	return plan, nil
+}
+
+// Recover rewrites the raft configuration of a stopped server to the given peers.
+// It must be run with the same peers file on every surviving server before starting them.
+func Recover(datadir, nodeID, peersFile string) error {
+	peers, err := readRecoveryPeers(nodeID, peersFile)
+	if err != nil {
+		return err
+	}
+
+	// bolt creates a missing file, which would recover an empty log on a wrong datadir
+	dbPath := filepath.Join(datadir, dbName)
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("%w: %s", ErrNoRaftDB, dbPath)
+	}
+
+	logger := log.With().Str(logging.ComponentCtxKey, "raft-recover").Logger()
+	hlogger := hclogzerolog.New(log.With().Str(logging.ComponentCtxKey, "hraft").Logger())
+
+	store, err := raftboltdb.New(raftboltdb.Options{
+		Path:        dbPath,
+		BoltOptions: &bbolt.Options{Timeout: dbLockTimeout},
+	})
+	if err != nil {
+		if errors.Is(err, bbolt.ErrTimeout) {
+			return ErrRaftDBLocked
+		}
+
+		return fmt.Errorf("failed to open raft database: %w", err)
+	}
+	defer store.Close()
+
+	snapshotStore, err := hraft.NewFileSnapshotStoreWithLogger(datadir, defaultSnapshotRetain, hlogger)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot store: %w", err)
+	}
+
+	_, transport := hraft.NewInmemTransport("")
+
+	hrconfig := hraft.DefaultConfig()
+	hrconfig.LocalID = hraft.ServerID(nodeID)
+	hrconfig.Logger = hlogger
+
+	logger.Info().Msgf("Recovering cluster with %d peers", len(peers.Servers))
+
+	if err := hraft.RecoverCluster(
+		hrconfig, NewFSM(NewSafeStorage()), store, store, snapshotStore, transport, peers,
+	); err != nil {
+		return fmt.Errorf("failed to recover cluster: %w", err)
+	}
+
+	logger.Info().Msg("Cluster configuration recovered")
+
+	return nil
+}
+
+func readRecoveryPeers(nodeID, peersFile string) (hraft.Configuration, error) {
+	peers, err := hraft.ReadConfigJSON(peersFile)
+	if err != nil {
+		return hraft.Configuration{}, fmt.Errorf("failed to read peers file: %w", err)
+	}
+
+	for _, srv := range peers.Servers {
+		if srv.ID == hraft.ServerID(nodeID) {
+			return peers, nil
+		}
+	}
+
+	return hraft.Configuration{}, fmt.Errorf("%w: %s", ErrLocalNodeNotInPeers, nodeID)
+}
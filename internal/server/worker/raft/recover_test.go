@@ -0,0 +1,124 @@
+package raft
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	hraft "github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareRecoverableDatadir(t *testing.T) string {
+	t.Helper()
+
+	return writeDatadir(t, []*hraft.Log{
+		{Index: 1, Term: 1, Type: hraft.LogConfiguration, Data: hraft.EncodeConfiguration(testConfiguration)},
+		{Index: 2, Term: 2, Type: hraft.LogNoop},
+		commandLog(t, 3, 2, "key", "value"),
+		commandLog(t, 4, 2, "key2", "value2"),
+	})
+}
+
+func writePeersFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "peers.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestPlanRecovery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ValidPeers", func(t *testing.T) {
+		t.Parallel()
+
+		datadir := prepareRecoverableDatadir(t)
+		peers := writePeersFile(t, `[{"id": "node1", "address": "127.0.0.1:7081"}]`)
+
+		plan, err := PlanRecovery(datadir, "node1", peers)
+		require.NoError(t, err)
+		assert.Equal(t, "node1", plan.NodeID)
+		assert.Equal(t, uint64(4), plan.LastIndex)
+		assert.Equal(t, uint64(2), plan.LastTerm)
+		assert.Equal(t, uint64(3), plan.SnapshotIndex)
+		assert.Len(t, plan.Configuration, 2)
+		require.Len(t, plan.Peers, 1)
+		assert.Equal(t, "127.0.0.1:7081", plan.Peers[0].Address)
+	})
+
+	t.Run("LocalNodeNotInPeers", func(t *testing.T) {
+		t.Parallel()
+
+		datadir := prepareRecoverableDatadir(t)
+		peers := writePeersFile(t, `[{"id": "node2", "address": "127.0.0.1:7082"}]`)
+
+		_, err := PlanRecovery(datadir, "node1", peers)
+		require.ErrorIs(t, err, ErrLocalNodeNotInPeers)
+	})
+
+	t.Run("InvalidPeersFile", func(t *testing.T) {
+		t.Parallel()
+
+		datadir := prepareRecoverableDatadir(t)
+		peers := writePeersFile(t, `not a json`)
+
+		_, err := PlanRecovery(datadir, "node1", peers)
+		require.Error(t, err)
+	})
+}
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		datadir := prepareRecoverableDatadir(t)
+		peers := writePeersFile(t, `[{"id": "node1", "address": "127.0.0.1:7081"}]`)
+
+		require.NoError(t, Recover(datadir, "node1", peers))
+
+		inspection, err := Inspect(datadir, -1)
+		require.NoError(t, err)
+		require.Len(t, inspection.Configuration, 1)
+		assert.Equal(t, "node1", inspection.Configuration[0].ID)
+	})
+
+	t.Run("LockedDatabase", func(t *testing.T) {
+		t.Parallel()
+
+		datadir := prepareRecoverableDatadir(t)
+		peers := writePeersFile(t, `[{"id": "node1", "address": "127.0.0.1:7081"}]`)
+
+		store, err := raftboltdb.NewBoltStore(filepath.Join(datadir, dbName))
+		require.NoError(t, err)
+
+		defer store.Close()
+
+		require.ErrorIs(t, Recover(datadir, "node1", peers), ErrRaftDBLocked)
+	})
+
+	t.Run("LocalNodeNotInPeers", func(t *testing.T) {
+		t.Parallel()
+
+		datadir := prepareRecoverableDatadir(t)
+		peers := writePeersFile(t, `[{"id": "node2", "address": "127.0.0.1:7082"}]`)
+
+		require.ErrorIs(t, Recover(datadir, "node1", peers), ErrLocalNodeNotInPeers)
+	})
+
+	t.Run("MissingDatabase", func(t *testing.T) {
+		t.Parallel()
+
+		datadir := t.TempDir()
+		peers := writePeersFile(t, `[{"id": "node1", "address": "127.0.0.1:7081"}]`)
+
+		require.ErrorIs(t, Recover(datadir, "node1", peers), ErrNoRaftDB)
+		assert.NoFileExists(t, filepath.Join(datadir, dbName))
+	})
+}
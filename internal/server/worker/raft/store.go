@@ -2,6 +2,7 @@ package raft
 
 import (
 	"maps"
+	"strings"
 	"sync"
 
 	"github.com/rs/zerolog"
@@ -9,8 +10,16 @@ import (
 	"github.com/weastur/maf/internal/utils/logging"
 )
 
+// ReservedPrefix holds the internal state, e.g. the audit log, the join tokens and the autopilot marks.
+// The KV API can't read or write it, only the raft worker itself does.
+const ReservedPrefix = "maf/"
+
 type Mapping map[string]string
 
+func IsReservedKey(key string) bool {
+	return strings.HasPrefix(key, ReservedPrefix)
+}
+
 type SafeStorage struct {
 	mu     sync.RWMutex
 	data   Mapping
@@ -52,6 +61,13 @@ func (s *SafeStorage) Delete(key string) {
 	delete(s.data, key)
 }
 
+func (s *SafeStorage) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.data)
+}
+
 func (s *SafeStorage) Snapshot() Mapping {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
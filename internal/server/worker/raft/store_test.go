@@ -47,6 +47,16 @@ func TestSafeStorage_Delete(t *testing.T) {
 	assert.False(t, ok, "expected key %s to not exist", key)
 }
 
+func TestSafeStorage_Len(t *testing.T) {
+	t.Parallel()
+
+	storage := NewSafeStorage()
+	assert.Equal(t, 0, storage.Len())
+
+	storage.Set(key, value)
+	assert.Equal(t, 1, storage.Len())
+}
+
 func TestSafeStorage_Snapshot(t *testing.T) {
 	t.Parallel()
 
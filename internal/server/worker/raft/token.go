@@ -0,0 +1,203 @@
+package raft
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	joinTokenBytes      = 32
+	joinTokenIDLen      = 8
+	defaultJoinTokenTTL = time.Hour
+	maxJoinTokenTTL     = 24 * time.Hour
+	// The expired tokens are removed on presentation too, the sweep catches the ones nobody presents again
+	joinTokenSweepInterval = time.Minute
+	// Only the hashes of the tokens are stored, so reading the storage doesn't reveal them
+	joinTokenPrefix  = ReservedPrefix + "join-tokens/"
+	joinRecordPrefix = ReservedPrefix + "joins/"
+)
+
+type JoinToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+type JoinRecord struct {
+	ServerID string    `json:"serverId"`
+	Address  string    `json:"address"`
+	Nonvoter bool      `json:"nonvoter"`
+	TokenID  string    `json:"tokenId"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+type joinTokenRecord struct {
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func hashJoinToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateJoinToken issues a token the new servers must present to join the cluster.
+// The token can be used by any number of servers until it expires.
+func (r *Raft) CreateJoinToken(ttl time.Duration) (*JoinToken, error) {
+	r.logger.Trace().Msgf("Creating join token with TTL %s", ttl)
+
+	if !r.IsLeader() {
+		r.logger.Warn().Msg("I'm not a leader, can't proceed with join token creation")
+
+		return nil, ErrNotALeader
+	}
+
+	if ttl <= 0 {
+		ttl = defaultJoinTokenTTL
+	}
+
+	if ttl > maxJoinTokenTTL {
+		return nil, fmt.Errorf("%w: %s", ErrJoinTokenTTL, maxJoinTokenTTL)
+	}
+
+	secret := make([]byte, joinTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate join token: %w", err)
+	}
+
+	now := time.Now().UTC()
+	token := &JoinToken{Token: hex.EncodeToString(secret), ExpiresAt: now.Add(ttl)}
+
+	data, err := json.Marshal(&joinTokenRecord{CreatedAt: now, ExpiresAt: token.ExpiresAt})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal join token: %w", err)
+	}
+
+	hash := hashJoinToken(token.Token)
+	if err := r.applyCommand(OpSet, joinTokenPrefix+hash, string(data)); err != nil {
+		return nil, err
+	}
+
+	r.logger.Info().Msgf(
+		"Created join token %s valid until %s",
+		hash[:joinTokenIDLen],
+		token.ExpiresAt.Format(time.RFC3339),
+	)
+
+	return token, nil
+}
+
+// expiresAt caps the expiration by the maximum TTL, so the record can't outlive it whatever it says
+func (rec *joinTokenRecord) expiresAt() time.Time {
+	if deadline := rec.CreatedAt.Add(maxJoinTokenTTL); deadline.Before(rec.ExpiresAt) {
+		return deadline
+	}
+
+	return rec.ExpiresAt
+}
+
+// validateJoinToken checks the token is known and not expired, and returns its ID for the audit.
+func (r *Raft) validateJoinToken(token string) (string, error) {
+	if token == "" {
+		return "", ErrInvalidJoinToken
+	}
+
+	hash := hashJoinToken(token)
+
+	value, ok := r.storage.Get(joinTokenPrefix + hash)
+	if !ok {
+		return "", ErrInvalidJoinToken
+	}
+
+	var record joinTokenRecord
+	if err := json.Unmarshal([]byte(value), &record); err != nil {
+		r.logger.Warn().Err(err).Msgf("Invalid join token record %s", hash[:joinTokenIDLen])
+
+		return "", ErrInvalidJoinToken
+	}
+
+	if time.Now().After(record.expiresAt()) {
+		_ = r.removeJoinToken(hash)
+
+		return "", ErrInvalidJoinToken
+	}
+
+	return hash[:joinTokenIDLen], nil
+}
+
+func (r *Raft) removeJoinToken(hash string) error {
+	r.logger.Info().Msgf("Join token %s expired, removing it", hash[:min(len(hash), joinTokenIDLen)])
+
+	if err := r.applyCommand(OpDelete, joinTokenPrefix+hash, ""); err != nil {
+		r.logger.Warn().Err(err).Msg("Failed to remove expired join token")
+
+		return err
+	}
+
+	return nil
+}
+
+// runJoinTokenSweeper periodically removes the expired join tokens. Only the leader does it, for the whole cluster
+func (r *Raft) runJoinTokenSweeper() {
+	go func() {
+		ticker := time.NewTicker(joinTokenSweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if r.IsLeader() {
+					r.sweepJoinTokens(time.Now())
+				}
+			case <-r.done:
+				r.logger.Info().Msg("Stopping join token sweeper")
+
+				return
+			}
+		}
+	}()
+}
+
+// sweepJoinTokens removes the tokens expired by now, and the malformed ones, which can't be presented either
+func (r *Raft) sweepJoinTokens(now time.Time) {
+	for key, value := range r.storage.Snapshot() {
+		hash, ok := strings.CutPrefix(key, joinTokenPrefix)
+		if !ok {
+			continue
+		}
+
+		var record joinTokenRecord
+		if err := json.Unmarshal([]byte(value), &record); err == nil && !now.After(record.expiresAt()) {
+			continue
+		}
+
+		// Most likely the leadership is lost, the next leader sweeps the rest
+		if err := r.removeJoinToken(hash); err != nil {
+			return
+		}
+	}
+}
+
+func (r *Raft) recordJoin(serverID, addr string, nonvoter bool, tokenID string) {
+	data, err := json.Marshal(&JoinRecord{
+		ServerID: serverID,
+		Address:  addr,
+		Nonvoter: nonvoter,
+		TokenID:  tokenID,
+		JoinedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		r.logger.Err(err).Msg("Failed to marshal join record")
+
+		return
+	}
+
+	if err := r.applyCommand(OpSet, joinRecordPrefix+serverID, string(data)); err != nil {
+		r.logger.Err(err).Msgf("Failed to record join of %s", serverID)
+	}
+}
@@ -0,0 +1,274 @@
+package raft
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	hraft "github.com/hashicorp/raft"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testJoinToken = "secret"
+
+// newJoinTokenStorage returns the storage with testJoinToken valid for the next hour.
+func newJoinTokenStorage() *SafeStorage {
+	storage := NewSafeStorage()
+	setJoinToken(storage, testJoinToken, time.Now().Add(time.Hour))
+
+	return storage
+}
+
+func setJoinToken(storage Storage, token string, expiresAt time.Time) {
+	data, _ := json.Marshal(&joinTokenRecord{CreatedAt: time.Now(), ExpiresAt: expiresAt})
+	storage.Set(joinTokenPrefix+hashJoinToken(token), string(data))
+}
+
+// mockApply accepts any command, e.g. the join records.
+func mockApply(mockRaft *MockHRaft) *MockApplyFuture {
+	mockApplyFuture := new(MockApplyFuture)
+	mockApplyFuture.On("Error").Return(nil).Maybe()
+	mockRaft.On("Apply", mock.Anything, cmdTimeout).Return(mockApplyFuture).Maybe()
+
+	return mockApplyFuture
+}
+
+func appliedCommands(mockRaft *MockHRaft) []*Command {
+	commands := make([]*Command, 0)
+
+	for _, call := range mockRaft.Calls {
+		if call.Method != "Apply" {
+			continue
+		}
+
+		var cmd Command
+		if err := json.Unmarshal(call.Arguments.Get(0).([]byte), &cmd); err == nil {
+			commands = append(commands, &cmd)
+		}
+	}
+
+	return commands
+}
+
+func TestCreateJoinToken(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockRaft.On("State").Return(hraft.Leader)
+		mockApply(mockRaft)
+
+		raft := &Raft{raftInstance: mockRaft, logger: log.Logger}
+
+		token, err := raft.CreateJoinToken(30 * time.Minute)
+		require.NoError(t, err)
+		assert.Len(t, token.Token, 2*joinTokenBytes)
+		assert.WithinDuration(t, time.Now().Add(30*time.Minute), token.ExpiresAt, time.Minute)
+
+		commands := appliedCommands(mockRaft)
+		require.Len(t, commands, 1)
+		assert.Equal(t, joinTokenPrefix+hashJoinToken(token.Token), commands[0].Key)
+		assert.NotContains(t, commands[0].Value, token.Token, "expected only the hash to be stored")
+	})
+
+	t.Run("DefaultTTL", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockRaft.On("State").Return(hraft.Leader)
+		mockApply(mockRaft)
+
+		raft := &Raft{raftInstance: mockRaft, logger: log.Logger}
+
+		token, err := raft.CreateJoinToken(0)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(defaultJoinTokenTTL), token.ExpiresAt, time.Minute)
+	})
+
+	t.Run("TTLTooLong", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockRaft.On("State").Return(hraft.Leader)
+
+		raft := &Raft{raftInstance: mockRaft, logger: log.Logger}
+
+		_, err := raft.CreateJoinToken(48 * time.Hour)
+		require.ErrorIs(t, err, ErrJoinTokenTTL)
+		mockRaft.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+	})
+
+	t.Run("NotLeader", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockRaft.On("State").Return(hraft.Follower)
+
+		raft := &Raft{raftInstance: mockRaft, logger: log.Logger}
+
+		_, err := raft.CreateJoinToken(time.Hour)
+		require.ErrorIs(t, err, ErrNotALeader)
+	})
+}
+
+func TestValidateJoinToken(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+
+		raft := &Raft{storage: newJoinTokenStorage(), logger: log.Logger}
+
+		tokenID, err := raft.validateJoinToken(testJoinToken)
+		require.NoError(t, err)
+		assert.Equal(t, hashJoinToken(testJoinToken)[:joinTokenIDLen], tokenID)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Parallel()
+
+		raft := &Raft{storage: newJoinTokenStorage(), logger: log.Logger}
+
+		_, err := raft.validateJoinToken("unknown")
+		require.ErrorIs(t, err, ErrInvalidJoinToken)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+
+		raft := &Raft{storage: newJoinTokenStorage(), logger: log.Logger}
+
+		_, err := raft.validateJoinToken("")
+		require.ErrorIs(t, err, ErrInvalidJoinToken)
+	})
+
+	t.Run("ExpiredIsRemoved", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockApply(mockRaft)
+
+		storage := NewSafeStorage()
+		setJoinToken(storage, testJoinToken, time.Now().Add(-time.Minute))
+
+		raft := &Raft{storage: storage, raftInstance: mockRaft, logger: log.Logger}
+
+		_, err := raft.validateJoinToken(testJoinToken)
+		require.ErrorIs(t, err, ErrInvalidJoinToken)
+
+		commands := appliedCommands(mockRaft)
+		require.Len(t, commands, 1)
+		assert.Equal(t, OpType(OpDelete), commands[0].Op)
+		assert.Equal(t, joinTokenPrefix+hashJoinToken(testJoinToken), commands[0].Key)
+	})
+
+	t.Run("TTLAboveMaximum", func(t *testing.T) {
+		t.Parallel()
+
+		mockRaft := new(MockHRaft)
+		mockApply(mockRaft)
+
+		now := time.Now()
+		data, _ := json.Marshal(&joinTokenRecord{
+			CreatedAt: now.Add(-maxJoinTokenTTL - time.Minute),
+			ExpiresAt: now.Add(365 * 24 * time.Hour),
+		})
+
+		storage := NewSafeStorage()
+		storage.Set(joinTokenPrefix+hashJoinToken(testJoinToken), string(data))
+
+		raft := &Raft{storage: storage, raftInstance: mockRaft, logger: log.Logger}
+
+		_, err := raft.validateJoinToken(testJoinToken)
+		require.ErrorIs(t, err, ErrInvalidJoinToken)
+	})
+}
+
+func TestSweepJoinTokens(t *testing.T) {
+	t.Parallel()
+
+	mockRaft := new(MockHRaft)
+	mockApply(mockRaft)
+
+	storage := newJoinTokenStorage()
+	setJoinToken(storage, "expired", time.Now().Add(-time.Minute))
+	storage.Set(joinTokenPrefix+hashJoinToken("malformed"), "{")
+	storage.Set("key", "value")
+
+	raft := &Raft{storage: storage, raftInstance: mockRaft, logger: log.Logger}
+
+	raft.sweepJoinTokens(time.Now())
+
+	removed := make([]string, 0)
+
+	for _, cmd := range appliedCommands(mockRaft) {
+		assert.Equal(t, OpType(OpDelete), cmd.Op)
+
+		removed = append(removed, cmd.Key)
+	}
+
+	assert.ElementsMatch(t, []string{
+		joinTokenPrefix + hashJoinToken("expired"),
+		joinTokenPrefix + hashJoinToken("malformed"),
+	}, removed)
+}
+
+func TestJoinRecordsJoin(t *testing.T) {
+	t.Parallel()
+
+	mockRaft := new(MockHRaft)
+	mockIndexFuture := new(MockIndexFuture)
+	mockIndexFuture.On("Error").Return(nil)
+	mockConfiguration(mockRaft)
+	mockRaft.On("State").Return(hraft.Leader)
+	mockApply(mockRaft)
+	mockRaft.On(
+		"AddVoter",
+		hraft.ServerID("node2"),
+		hraft.ServerAddress("127.0.0.1:8081"),
+		uint64(0),
+		time.Duration(0),
+	).Return(mockIndexFuture)
+
+	raft := &Raft{
+		config:       &Config{NodeID: "node1"},
+		raftInstance: mockRaft,
+		storage:      newJoinTokenStorage(),
+		logger:       log.Logger,
+	}
+
+	require.NoError(t, raft.Join("node2", "127.0.0.1:8081", false, testJoinToken))
+
+	commands := appliedCommands(mockRaft)
+	require.Len(t, commands, 1)
+	assert.Equal(t, joinRecordPrefix+"node2", commands[0].Key)
+
+	var record JoinRecord
+	require.NoError(t, json.Unmarshal([]byte(commands[0].Value), &record))
+	assert.Equal(t, "127.0.0.1:8081", record.Address)
+	assert.Equal(t, hashJoinToken(testJoinToken)[:joinTokenIDLen], record.TokenID)
+}
+
+func TestJoinRequiresToken(t *testing.T) {
+	t.Parallel()
+
+	mockRaft := new(MockHRaft)
+	mockConfiguration(mockRaft)
+	mockRaft.On("State").Return(hraft.Leader)
+
+	raft := &Raft{
+		config:       &Config{NodeID: "node1"},
+		raftInstance: mockRaft,
+		storage:      newJoinTokenStorage(),
+		logger:       log.Logger,
+	}
+
+	err := raft.Join("node2", "127.0.0.1:8081", false, "forged")
+	require.ErrorIs(t, err, ErrInvalidJoinToken)
+	mockRaft.AssertNotCalled(t, "AddVoter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
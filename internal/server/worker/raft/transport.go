@@ -0,0 +1,102 @@
+package raft
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	hraft "github.com/hashicorp/raft"
+	"github.com/weastur/maf/internal/utils/certs"
+)
+
+var (
+	ErrInvalidCA     = certs.ErrInvalidCA
+	ErrNotAdvertised = errors.New("local bind address is not advertisable")
+)
+
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+func (c *TLSConfig) IsEnabled() bool {
+	return c != nil && c.CertFile != "" && c.KeyFile != "" && c.CAFile != ""
+}
+
+// TLSStreamLayer is a raft stream layer which requires and verifies peer certificates
+// on both sides of the connection, so only servers with a certificate issued by the
+// trusted CA can take part in the replication.
+type TLSStreamLayer struct {
+	net.Listener
+
+	advertise net.Addr
+	certs     *certs.Store
+}
+
+// NewTLSStreamLayer listens on the bind address. The certs are reloaded in place when the files change,
+// so the rotation doesn't require the rolling restart of the cluster
+func NewTLSStreamLayer(bind string, advertise net.Addr, config *TLSConfig) (*TLSStreamLayer, error) {
+	store, err := certs.New(config.CertFile, config.KeyFile, config.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load raft certs: %w", err)
+	}
+
+	listener, err := tls.Listen("tcp", bind, store.ServerConfig())
+	if err != nil {
+		store.Close()
+
+		return nil, fmt.Errorf("failed to listen on %s: %w", bind, err)
+	}
+
+	if advertise == nil {
+		advertise = listener.Addr()
+	}
+
+	if tcpAddr, ok := advertise.(*net.TCPAddr); !ok || tcpAddr.IP == nil || tcpAddr.IP.IsUnspecified() {
+		listener.Close()
+		store.Close()
+
+		return nil, ErrNotAdvertised
+	}
+
+	return &TLSStreamLayer{
+		Listener:  listener,
+		advertise: advertise,
+		certs:     store,
+	}, nil
+}
+
+// Dial verifies the peer cert is issued by the trusted CA for the dialed host, e.g. the IP SAN matches the address
+func (t *TLSStreamLayer) Dial(address hraft.ServerAddress, timeout time.Duration) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(string(address))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse address %s: %w", address, err)
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+
+	conn, err := tls.DialWithDialer(dialer, "tcp", string(address), t.certs.ClientConfig(host))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", address, err)
+	}
+
+	return conn, nil
+}
+
+func (t *TLSStreamLayer) Addr() net.Addr {
+	return t.advertise
+}
+
+// Close stops the listener and the certs reloading
+func (t *TLSStreamLayer) Close() error {
+	t.certs.Close()
+
+	if err := t.Listener.Close(); err != nil {
+		return fmt.Errorf("failed to close raft listener: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package raft
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	hraft "github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	file string
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+}
+
+func newTestCA(t *testing.T, dir, name string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	file := filepath.Join(dir, name+".ca.pem")
+	writePEM(t, file, "CERTIFICATE", der)
+
+	return &testCA{cert: cert, key: key, file: file}
+}
+
+func (ca *testCA) issue(t *testing.T, dir, name string) *TLSConfig {
+	t.Helper()
+
+	return ca.issueFor(t, dir, name, "127.0.0.1")
+}
+
+// issueFor writes the cert valid only for the given IP
+func (ca *testCA) issueFor(t *testing.T, dir, name, ip string) *TLSConfig {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP(ip)},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	config := &TLSConfig{
+		CertFile: filepath.Join(dir, name+".cert.pem"),
+		KeyFile:  filepath.Join(dir, name+".key.pem"),
+		CAFile:   ca.file,
+	}
+	writePEM(t, config.CertFile, "CERTIFICATE", der)
+	writePEM(t, config.KeyFile, "EC PRIVATE KEY", keyDER)
+
+	return config
+}
+
+func TestTLSConfigIsEnabled(t *testing.T) {
+	t.Parallel()
+
+	var nilConfig *TLSConfig
+
+	assert.False(t, nilConfig.IsEnabled())
+	assert.False(t, (&TLSConfig{CertFile: "cert", KeyFile: "key"}).IsEnabled())
+	assert.True(t, (&TLSConfig{CertFile: "cert", KeyFile: "key", CAFile: "ca"}).IsEnabled())
+}
+
+func TestNewTLSStreamLayer(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "maf")
+	config := ca.issue(t, dir, "node1")
+
+	t.Run("MissingKeyPair", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewTLSStreamLayer("127.0.0.1:0", nil, &TLSConfig{
+			CertFile: filepath.Join(dir, "missing.pem"),
+			KeyFile:  config.KeyFile,
+			CAFile:   config.CAFile,
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidCA", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewTLSStreamLayer("127.0.0.1:0", nil, &TLSConfig{
+			CertFile: config.CertFile,
+			KeyFile:  config.KeyFile,
+			CAFile:   config.KeyFile,
+		})
+		require.ErrorIs(t, err, ErrInvalidCA)
+	})
+
+	t.Run("NotAdvertisable", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewTLSStreamLayer("0.0.0.0:0", nil, config)
+		require.ErrorIs(t, err, ErrNotAdvertised)
+	})
+
+	t.Run("Advertise", func(t *testing.T) {
+		t.Parallel()
+
+		advertise := &net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 7081}
+
+		stream, err := NewTLSStreamLayer("127.0.0.1:0", advertise, config)
+		require.NoError(t, err)
+
+		defer stream.Close()
+
+		assert.Equal(t, advertise, stream.Addr())
+	})
+}
+
+func TestTLSStreamLayerDial(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "maf")
+	rogueCA := newTestCA(t, dir, "rogue")
+
+	server, err := NewTLSStreamLayer("127.0.0.1:0", nil, ca.issue(t, dir, "node1"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { server.Close() })
+
+	go func() {
+		for {
+			conn, err := server.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	t.Run("TrustedPeer", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewTLSStreamLayer("127.0.0.1:0", nil, ca.issue(t, dir, "node2"))
+		require.NoError(t, err)
+
+		defer client.Close()
+
+		conn, err := client.Dial(hraft.ServerAddress(server.Addr().String()), time.Second)
+		require.NoError(t, err)
+
+		defer conn.Close()
+
+		_, err = conn.Write([]byte("ping"))
+		require.NoError(t, err)
+
+		buf := make([]byte, 4)
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "ping", string(buf))
+	})
+
+	t.Run("RoguePeer", func(t *testing.T) {
+		t.Parallel()
+
+		rogueConfig := rogueCA.issue(t, dir, "rogue-node")
+		rogueConfig.CAFile = ca.file
+
+		client, err := NewTLSStreamLayer("127.0.0.1:0", nil, rogueConfig)
+		require.NoError(t, err)
+
+		defer client.Close()
+
+		conn, err := client.Dial(hraft.ServerAddress(server.Addr().String()), time.Second)
+		if err == nil {
+			defer conn.Close()
+
+			// With TLS 1.3 the client learns about rejected certificate on the first read
+			_, err = conn.Write([]byte("ping"))
+			if err == nil {
+				_, err = io.ReadFull(conn, make([]byte, 4))
+			}
+		}
+
+		require.Error(t, err, "expected server to reject certificate issued by untrusted CA")
+	})
+
+	t.Run("PeerCertForAnotherIP", func(t *testing.T) {
+		t.Parallel()
+
+		impostor, err := NewTLSStreamLayer("127.0.0.1:0", nil, ca.issueFor(t, dir, "impostor", "10.0.0.1"))
+		require.NoError(t, err)
+
+		defer impostor.Close()
+
+		go func() {
+			if conn, err := impostor.Accept(); err == nil {
+				defer conn.Close()
+
+				_, _ = io.Copy(conn, conn)
+			}
+		}()
+
+		client, err := NewTLSStreamLayer("127.0.0.1:0", nil, ca.issue(t, dir, "node3"))
+		require.NoError(t, err)
+
+		defer client.Close()
+
+		_, err = client.Dial(hraft.ServerAddress(impostor.Addr().String()), time.Second)
+		require.ErrorContains(t, err, "certificate is valid for 10.0.0.1", "expected cert of another peer to be rejected")
+	})
+}
@@ -0,0 +1,81 @@
+package raft
+
+import (
+	"time"
+
+	hraft "github.com/hashicorp/raft"
+)
+
+const (
+	defaultSnapshotRetain   = 2
+	defaultTransportMaxPool = 3
+	defaultTransportTimeout = 10 * time.Second
+)
+
+// TuningConfig overrides the raft timings and limits. Zero values keep the library defaults,
+// which are tuned for LAN. WAN deployments need much longer timeouts.
+type TuningConfig struct {
+	HeartbeatTimeout   time.Duration
+	ElectionTimeout    time.Duration
+	LeaderLeaseTimeout time.Duration
+	SnapshotInterval   time.Duration
+	SnapshotThreshold  uint64
+	TrailingLogs       uint64
+	SnapshotRetain     int
+	TransportMaxPool   int
+	TransportTimeout   time.Duration
+}
+
+func (c *TuningConfig) apply(hrconfig *hraft.Config) {
+	if c == nil {
+		return
+	}
+
+	if c.HeartbeatTimeout > 0 {
+		hrconfig.HeartbeatTimeout = c.HeartbeatTimeout
+	}
+
+	if c.ElectionTimeout > 0 {
+		hrconfig.ElectionTimeout = c.ElectionTimeout
+	}
+
+	if c.LeaderLeaseTimeout > 0 {
+		hrconfig.LeaderLeaseTimeout = c.LeaderLeaseTimeout
+	}
+
+	if c.SnapshotInterval > 0 {
+		hrconfig.SnapshotInterval = c.SnapshotInterval
+	}
+
+	if c.SnapshotThreshold > 0 {
+		hrconfig.SnapshotThreshold = c.SnapshotThreshold
+	}
+
+	if c.TrailingLogs > 0 {
+		hrconfig.TrailingLogs = c.TrailingLogs
+	}
+}
+
+func (c *TuningConfig) snapshotRetain() int {
+	if c == nil || c.SnapshotRetain <= 0 {
+		return defaultSnapshotRetain
+	}
+
+	return c.SnapshotRetain
+}
+
+func (c *TuningConfig) transportMaxPool() int {
+	if c == nil || c.TransportMaxPool <= 0 {
+		return defaultTransportMaxPool
+	}
+
+	return c.TransportMaxPool
+}
+
+func (c *TuningConfig) transportTimeout() time.Duration {
+	if c == nil || c.TransportTimeout <= 0 {
+		return defaultTransportTimeout
+	}
+
+	return c.TransportTimeout
+}
@@ -0,0 +1,24 @@
+package raft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTuningConfigDefaults(t *testing.T) {
+	t.Parallel()
+
+	var nilConfig *TuningConfig
+
+	assert.Equal(t, defaultSnapshotRetain, nilConfig.snapshotRetain())
+	assert.Equal(t, defaultTransportMaxPool, nilConfig.transportMaxPool())
+	assert.Equal(t, defaultTransportTimeout, nilConfig.transportTimeout())
+
+	config := &TuningConfig{SnapshotRetain: 5, TransportMaxPool: 10, TransportTimeout: time.Minute}
+
+	assert.Equal(t, 5, config.snapshotRetain())
+	assert.Equal(t, 10, config.transportMaxPool())
+	assert.Equal(t, time.Minute, config.transportTimeout())
+}
@@ -1,18 +1,19 @@
 package raft
 
 type Server struct {
-	ID       string
-	Address  string
-	Suffrage string
-	Leader   bool
+	ID       string `json:"id"`
+	Address  string `json:"address"`
+	Suffrage string `json:"suffrage"`
+	Leader   bool   `json:"leader"`
 }
 
 type Stats map[string]string
 
 type Info struct {
-	State   string
-	Addr    string
-	ID      string
-	Servers []Server
-	Stats   Stats
+	State     string
+	Addr      string
+	Advertise string
+	ID        string
+	Servers   []Server
+	Stats     Stats
 }
@@ -0,0 +1,255 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/weastur/maf/internal/utils/logging"
+)
+
+// reloadDelay collapses the burst of the file events, e.g. the cert and the key written one by one
+const reloadDelay = 500 * time.Millisecond
+
+var (
+	ErrInvalidCA = errors.New("failed to parse CA certificate")
+)
+
+// Store keeps the key pair and the CA pool loaded from the files and reloads them in place
+// when the files change, so the cert rotation doesn't require a restart. Any of the files may be empty,
+// e.g. the client trusting a private CA doesn't need a key pair.
+type Store struct {
+	certFile  string
+	keyFile   string
+	caFile    string
+	logger    zerolog.Logger
+	watcher   *fsnotify.Watcher
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+}
+
+// New loads the files and starts watching them. Close the store to stop watching
+func New(certFile, keyFile, caFile string) (*Store, error) {
+	s := &Store{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		logger:   log.With().Str(logging.ComponentCtxKey, "certs").Logger(),
+		done:     make(chan struct{}),
+	}
+
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certs watcher: %w", err)
+	}
+
+	// The dirs are watched instead of the files, since the files are usually replaced rather than written,
+	// e.g. by renaming or by switching the symlink like Kubernetes does for the mounted secrets
+	for _, dir := range s.dirs() {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+
+			return nil, fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+	}
+
+	s.watcher = watcher
+
+	go s.watch()
+
+	return s, nil
+}
+
+// NewServerStore loads the certs of the TLS listener, with the client CA for the mutual TLS if it's set.
+// It returns nil if the listener has no cert, so it serves plain text
+func NewServerStore(certFile, keyFile, clientCertFile string) (*Store, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	return New(certFile, keyFile, clientCertFile)
+}
+
+func (s *Store) dirs() []string {
+	dirs := make([]string, 0, 3) //nolint:mnd
+
+	for _, file := range []string{s.certFile, s.keyFile, s.caFile} {
+		if file == "" {
+			continue
+		}
+
+		if dir := filepath.Dir(file); !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	return dirs
+}
+
+func (s *Store) watch() {
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case event, ok := <-s.watcher.Events:
+			if !ok {
+				return
+			}
+
+			s.logger.Trace().Str("file", event.Name).Str("op", event.Op.String()).Msg("Certs dir changed")
+			timer.Reset(reloadDelay)
+		case err, ok := <-s.watcher.Errors:
+			if !ok {
+				return
+			}
+
+			s.logger.Error().Err(err).Msg("Failed to watch certs")
+		case <-timer.C:
+			if err := s.Reload(); err != nil {
+				s.logger.Error().Err(err).Msg("Failed to reload certs, keeping the previous ones")
+			}
+		}
+	}
+}
+
+// Reload reads the files again. The loaded certs are kept if any of the files is invalid
+func (s *Store) Reload() error {
+	var cert *tls.Certificate
+
+	if s.certFile != "" || s.keyFile != "" {
+		pair, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load key pair: %w", err)
+		}
+
+		if pair.Leaf == nil {
+			if pair.Leaf, err = x509.ParseCertificate(pair.Certificate[0]); err != nil {
+				return fmt.Errorf("failed to parse certificate: %w", err)
+			}
+		}
+
+		cert = &pair
+	}
+
+	var pool *x509.CertPool
+
+	if s.caFile != "" {
+		caPEM, err := os.ReadFile(s.caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA file: %w", err)
+		}
+
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return fmt.Errorf("%w: %s", ErrInvalidCA, s.caFile)
+		}
+	}
+
+	s.mu.Lock()
+	s.cert = cert
+	s.pool = pool
+	s.mu.Unlock()
+
+	if cert != nil {
+		expiry := cert.Leaf.NotAfter
+
+		metrics.GetOrCreateGauge(fmt.Sprintf(`maf_tls_cert_expiry_timestamp_seconds{cert=%q}`, s.certFile), nil).
+			Set(float64(expiry.Unix()))
+		s.logger.Info().Str("cert", s.certFile).Time("expiry", expiry).Msg("Certificate loaded")
+	}
+
+	return nil
+}
+
+// Certificate returns the current key pair, nil if the store has none
+func (s *Store) Certificate() *tls.Certificate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.cert
+}
+
+// Pool returns the current CA pool, nil if the store has none
+func (s *Store) Pool() *x509.CertPool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.pool
+}
+
+// ServerConfig returns the TLS config picking the current certs up on every handshake.
+// The client certs are required and verified if the store has the CA pool.
+// The ALPN protocols have to be set here, since the config returned per handshake replaces the outer one.
+func (s *Store) ServerConfig(nextProtos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(_ *tls.ClientHelloInfo) (*tls.Config, error) {
+			config := &tls.Config{
+				MinVersion: tls.VersionTLS12,
+				NextProtos: nextProtos,
+			}
+
+			if cert := s.Certificate(); cert != nil {
+				config.Certificates = []tls.Certificate{*cert}
+			}
+
+			if pool := s.Pool(); pool != nil {
+				config.ClientCAs = pool
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+
+			return config, nil
+		},
+	}
+}
+
+// ClientConfig returns the TLS config presenting the current cert and verifying the server
+// against the current CA pool, or the system roots if the store has none. The pool can change
+// on the reload, so the config must be built for every connection. The server name is the dialed host,
+// it's checked against the DNS and IP SANs of the server cert.
+func (s *Store) ClientConfig(serverName string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		RootCAs:    s.Pool(),
+		GetClientCertificate: func(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if cert := s.Certificate(); cert != nil {
+				return cert, nil
+			}
+
+			return &tls.Certificate{}, nil
+		},
+	}
+}
+
+// Close stops watching the files. It's safe to close the store more than once
+func (s *Store) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.watcher.Close()
+	})
+}
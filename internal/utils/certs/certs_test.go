@@ -0,0 +1,327 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	file string
+}
+
+type testPair struct {
+	certFile string
+	keyFile  string
+}
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	log.Logger = log.Output(zerolog.Nop())
+
+	os.Exit(m.Run())
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+
+	// The file is replaced by renaming, the same way the cert managers do, to not expose it half-written
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func newTestCA(t *testing.T, dir, name string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	file := filepath.Join(dir, name+".ca.pem")
+	writePEM(t, file, "CERTIFICATE", der)
+
+	return &testCA{cert: cert, key: key, file: file}
+}
+
+// issue writes the cert of the given serial number, valid for the given duration, to the dir
+func (ca *testCA) issue(t *testing.T, dir, name string, serial int64, validity time.Duration) *testPair {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validity).Truncate(time.Second),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	pair := &testPair{
+		certFile: filepath.Join(dir, name+".cert.pem"),
+		keyFile:  filepath.Join(dir, name+".key.pem"),
+	}
+	writePEM(t, pair.keyFile, "EC PRIVATE KEY", keyDER)
+	writePEM(t, pair.certFile, "CERTIFICATE", der)
+
+	return pair
+}
+
+func newTestStore(t *testing.T, certFile, keyFile, caFile string) *Store {
+	t.Helper()
+
+	store, err := New(certFile, keyFile, caFile)
+	require.NoError(t, err)
+
+	t.Cleanup(store.Close)
+
+	return store
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "maf")
+	pair := ca.issue(t, dir, "node1", 2, time.Hour)
+
+	t.Run("KeyPairAndCA", func(t *testing.T) {
+		t.Parallel()
+
+		store := newTestStore(t, pair.certFile, pair.keyFile, ca.file)
+
+		require.NotNil(t, store.Certificate())
+		assert.Equal(t, "node1", store.Certificate().Leaf.Subject.CommonName)
+		assert.NotNil(t, store.Pool())
+	})
+
+	t.Run("CAOnly", func(t *testing.T) {
+		t.Parallel()
+
+		store := newTestStore(t, "", "", ca.file)
+
+		assert.Nil(t, store.Certificate())
+		assert.NotNil(t, store.Pool())
+	})
+
+	t.Run("MissingKeyPair", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(filepath.Join(dir, "missing.pem"), pair.keyFile, "")
+		require.ErrorContains(t, err, "failed to load key pair")
+	})
+
+	t.Run("MissingCA", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(pair.certFile, pair.keyFile, filepath.Join(dir, "missing.pem"))
+		require.ErrorContains(t, err, "failed to read CA file")
+	})
+
+	t.Run("InvalidCA", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(pair.certFile, pair.keyFile, pair.keyFile)
+		require.ErrorIs(t, err, ErrInvalidCA)
+	})
+}
+
+func TestNewServerStore(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "maf")
+	pair := ca.issue(t, dir, "node1", 2, time.Hour)
+
+	t.Run("PlainText", func(t *testing.T) {
+		t.Parallel()
+
+		store, err := NewServerStore("", "", ca.file)
+		require.NoError(t, err)
+		assert.Nil(t, store)
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		t.Parallel()
+
+		store, err := NewServerStore(pair.certFile, pair.keyFile, "")
+		require.NoError(t, err)
+
+		defer store.Close()
+
+		assert.NotNil(t, store.Certificate())
+		assert.Nil(t, store.Pool())
+	})
+}
+
+func TestStore_Reload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "maf")
+	pair := ca.issue(t, dir, "node1", 2, time.Hour)
+	store := newTestStore(t, pair.certFile, pair.keyFile, ca.file)
+
+	gauge := fmt.Sprintf(`maf_tls_cert_expiry_timestamp_seconds{cert=%q}`, pair.certFile)
+	assert.InDelta(t, float64(store.Certificate().Leaf.NotAfter.Unix()), metrics.GetOrCreateGauge(gauge, nil).Get(), 0)
+
+	// The rotated cert is picked up by the watcher without the explicit reload
+	rotated := ca.issue(t, dir, "node1", 3, 2*time.Hour)
+	require.Equal(t, pair, rotated)
+
+	assert.Eventually(t, func() bool {
+		return store.Certificate().Leaf.SerialNumber.Int64() == 3
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.InDelta(t, float64(store.Certificate().Leaf.NotAfter.Unix()), metrics.GetOrCreateGauge(gauge, nil).Get(), 0)
+
+	// The broken files don't replace the loaded ones
+	require.NoError(t, os.WriteFile(pair.certFile, []byte("garbage"), 0o600))
+	require.Error(t, store.Reload())
+	assert.Equal(t, int64(3), store.Certificate().Leaf.SerialNumber.Int64())
+}
+
+func TestStore_Handshake(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "maf")
+	rogueCA := newTestCA(t, dir, "rogue")
+
+	serverPair := ca.issue(t, dir, "server", 2, time.Hour)
+	server := newTestStore(t, serverPair.certFile, serverPair.keyFile, ca.file)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", server.ServerConfig())
+	require.NoError(t, err)
+
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	// ping fails if either side rejects the other, with TLS 1.3 the client learns it on the first read
+	ping := func(config *tls.Config) error {
+		conn, err := tls.Dial("tcp", listener.Addr().String(), config)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		defer conn.Close()
+
+		if _, err := conn.Write([]byte("ping")); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		_, err = io.ReadFull(conn, make([]byte, 4))
+
+		return err //nolint:wrapcheck
+	}
+
+	t.Run("TrustedClient", func(t *testing.T) {
+		t.Parallel()
+
+		pair := ca.issue(t, dir, "client", 4, time.Hour)
+		client := newTestStore(t, pair.certFile, pair.keyFile, ca.file)
+
+		require.NoError(t, ping(client.ClientConfig("127.0.0.1")))
+	})
+
+	t.Run("RogueClient", func(t *testing.T) {
+		t.Parallel()
+
+		pair := rogueCA.issue(t, dir, "rogue-client", 5, time.Hour)
+		client := newTestStore(t, pair.certFile, pair.keyFile, ca.file)
+
+		require.Error(t, ping(client.ClientConfig("127.0.0.1")))
+	})
+
+	t.Run("UntrustedServer", func(t *testing.T) {
+		t.Parallel()
+
+		pair := ca.issue(t, dir, "untrusting-client", 6, time.Hour)
+		client := newTestStore(t, pair.certFile, pair.keyFile, rogueCA.file)
+
+		require.ErrorContains(t, ping(client.ClientConfig("127.0.0.1")), "certificate signed by unknown authority")
+	})
+
+	t.Run("ServerCertForAnotherIP", func(t *testing.T) {
+		t.Parallel()
+
+		pair := ca.issue(t, dir, "other-ip-client", 7, time.Hour)
+		client := newTestStore(t, pair.certFile, pair.keyFile, ca.file)
+
+		// the server cert is issued by the trusted CA, but for 127.0.0.1 only
+		require.ErrorContains(t, ping(client.ClientConfig("10.0.0.1")), "certificate is valid for 127.0.0.1")
+	})
+}
+
+func TestStore_Close(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "maf")
+
+	store, err := New("", "", ca.file)
+	require.NoError(t, err)
+
+	assert.NotPanics(t, func() {
+		store.Close()
+		store.Close()
+	})
+}
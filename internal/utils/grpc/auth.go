@@ -0,0 +1,134 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2/middleware/keyauth"
+	"github.com/rs/zerolog"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// AuthMetadataKey is the metadata key of the API token, the same as the HTTP header
+const AuthMetadataKey = "x-auth-token"
+
+type identityContextKey struct{}
+
+var ErrUnknownMethod = errors.New("method is not allowed")
+
+// MethodRoles maps the full method names to the roles required to call them. Unlisted methods are denied
+type MethodRoles map[string]v1alphaUtils.Role
+
+// GetIdentity returns the identity the call is authenticated with
+func GetIdentity(ctx context.Context) (*v1alphaUtils.Identity, bool) {
+	identity, ok := ctx.Value(identityContextKey{}).(*v1alphaUtils.Identity)
+
+	return identity, ok
+}
+
+func authToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get(AuthMetadataKey); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func authCert(ctx context.Context, auth *v1alphaUtils.Authenticator) (*v1alphaUtils.Identity, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, false
+	}
+
+	return auth.AuthenticateCert(&tlsInfo.State)
+}
+
+// authenticate works the same way as the HTTP auth middleware: the token is checked first,
+// the verified client certificate is used if the token is absent
+func authenticate(ctx context.Context, auth *v1alphaUtils.Authenticator, roles MethodRoles, method string) (
+	context.Context, error,
+) {
+	logger := zerolog.Ctx(ctx)
+
+	role, ok := roles[method]
+	if !ok {
+		logger.Warn().Msgf("Method %s is not mapped to a role", method)
+
+		return ctx, v1alphaUtils.NewAPIError(v1alphaUtils.CodeForbidden, fmt.Errorf("%w: %s", ErrUnknownMethod, method), nil)
+	}
+
+	var identity *v1alphaUtils.Identity
+
+	switch token := authToken(ctx); {
+	case auth.Disabled():
+		identity = &v1alphaUtils.AnonymousIdentity
+	case token != "":
+		identity, ok = auth.Authenticate(token)
+		if !ok {
+			logger.Warn().Msg("API key is missing or malformed")
+
+			return ctx, keyauth.ErrMissingOrMalformedAPIKey
+		}
+
+		logger.Trace().Msg("Authenticated by token")
+	default:
+		identity, ok = authCert(ctx, auth)
+		if !ok {
+			logger.Warn().Msg("API key is missing or malformed")
+
+			return ctx, keyauth.ErrMissingOrMalformedAPIKey
+		}
+
+		logger.Trace().Msg("Authenticated by client certificate")
+	}
+
+	if identity.Role < role {
+		logger.Warn().Msgf("Role %s is required to call %s", role, method)
+
+		return ctx, fmt.Errorf("%w: %s is required", v1alphaUtils.ErrInsufficientRole, role)
+	}
+
+	ctx = context.WithValue(ctx, identityContextKey{}, identity)
+	ctxLogger := logger.With().Str("identity", identity.Name).Str("role", identity.Role.String()).Logger()
+
+	return ctxLogger.WithContext(ctx), nil
+}
+
+// UnaryAuthInterceptor authenticates the unary calls and checks the role required by the method
+func UnaryAuthInterceptor(auth *v1alphaUtils.Authenticator, roles MethodRoles) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, auth, roles, info.FullMethod)
+		if err != nil {
+			return nil, AsStatusError(err)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor authenticates the streaming calls and checks the role required by the method
+func StreamAuthInterceptor(auth *v1alphaUtils.Authenticator, roles MethodRoles) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), auth, roles, info.FullMethod)
+		if err != nil {
+			return AsStatusError(err)
+		}
+
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
@@ -0,0 +1,188 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testReadMethod     = "/maf.v1.Test/Read"
+	testOperatorMethod = "/maf.v1.Test/Write"
+)
+
+var testRoles = MethodRoles{
+	testReadMethod:     v1alphaUtils.RoleRead,
+	testOperatorMethod: v1alphaUtils.RoleOperator,
+}
+
+type testStream struct {
+	grpc.ServerStream
+
+	ctx context.Context //nolint:containedctx
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+func newTestAuthenticator(t *testing.T) *v1alphaUtils.Authenticator {
+	t.Helper()
+
+	auth, err := v1alphaUtils.NewAuthenticator(&v1alphaUtils.AuthConfig{
+		Tokens: []v1alphaUtils.TokenConfig{
+			{Name: "reader", Role: "read", Token: "read-token"},
+			{Name: "operator", Role: "operator", Token: "operator-token"},
+		},
+		ClientCerts: []v1alphaUtils.ClientCertConfig{
+			{Name: "ops", Role: "operator", CommonName: "ops"},
+		},
+	})
+	require.NoError(t, err)
+
+	return auth
+}
+
+func tokenContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthMetadataKey, token))
+}
+
+func certContext(commonName string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+func TestUnaryAuthInterceptor(t *testing.T) {
+	t.Parallel()
+
+	interceptor := UnaryAuthInterceptor(newTestAuthenticator(t), testRoles)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context //nolint:containedctx
+		method   string
+		code     codes.Code
+		identity string
+	}{
+		{"Token", tokenContext("read-token"), testReadMethod, codes.OK, "reader"},
+		{"ClientCert", certContext("ops"), testOperatorMethod, codes.OK, "ops"},
+		{"MissingCredentials", context.Background(), testReadMethod, codes.Unauthenticated, ""},
+		{"UnknownClientCert", certContext("unknown"), testReadMethod, codes.Unauthenticated, ""},
+		{"InvalidToken", tokenContext("wrong"), testReadMethod, codes.Unauthenticated, ""},
+		{"InsufficientRole", tokenContext("read-token"), testOperatorMethod, codes.PermissionDenied, ""},
+		{"UnknownMethod", tokenContext("operator-token"), "/maf.v1.Test/Unknown", codes.PermissionDenied, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var identity *v1alphaUtils.Identity
+
+			handler := func(ctx context.Context, _ any) (any, error) {
+				identity, _ = GetIdentity(ctx)
+
+				return "ok", nil
+			}
+
+			resp, err := interceptor(test.ctx, nil, &grpc.UnaryServerInfo{FullMethod: test.method}, handler)
+			assert.Equal(t, test.code, status.Code(err))
+
+			if test.code != codes.OK {
+				assert.Nil(t, resp)
+				assert.Nil(t, identity)
+
+				return
+			}
+
+			assert.Equal(t, "ok", resp)
+			require.NotNil(t, identity)
+			assert.Equal(t, test.identity, identity.Name)
+		})
+	}
+}
+
+func TestUnaryAuthInterceptorDisabled(t *testing.T) {
+	t.Parallel()
+
+	auth, err := v1alphaUtils.NewAuthenticator(&v1alphaUtils.AuthConfig{Disabled: true})
+	require.NoError(t, err)
+
+	interceptor := UnaryAuthInterceptor(auth, testRoles)
+
+	var identity *v1alphaUtils.Identity
+
+	handler := func(ctx context.Context, _ any) (any, error) {
+		identity, _ = GetIdentity(ctx)
+
+		return "ok", nil
+	}
+
+	resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: testOperatorMethod}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+	require.NotNil(t, identity)
+	assert.Equal(t, v1alphaUtils.AnonymousIdentity, *identity)
+}
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	t.Parallel()
+
+	interceptor := StreamAuthInterceptor(newTestAuthenticator(t), testRoles)
+
+	t.Run("Authenticated", func(t *testing.T) {
+		t.Parallel()
+
+		var identity *v1alphaUtils.Identity
+
+		err := interceptor(
+			nil,
+			&testStream{ctx: tokenContext("operator-token")},
+			&grpc.StreamServerInfo{FullMethod: testReadMethod},
+			func(_ any, stream grpc.ServerStream) error {
+				identity, _ = GetIdentity(stream.Context())
+
+				return nil
+			},
+		)
+
+		require.NoError(t, err)
+		require.NotNil(t, identity)
+		assert.Equal(t, "operator", identity.Name)
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		t.Parallel()
+
+		err := interceptor(
+			nil,
+			&testStream{ctx: context.Background()},
+			&grpc.StreamServerInfo{FullMethod: testReadMethod},
+			func(_ any, _ grpc.ServerStream) error {
+				t.Fatal("handler must not be called")
+
+				return nil
+			},
+		)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
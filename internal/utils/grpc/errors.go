@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	apiV1 "github.com/weastur/maf/internal/utils/grpc/api/v1"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var codeGRPC = map[v1alphaUtils.ErrorCode]codes.Code{
+	// The call must be sent to the leader, the system is not in a state required for it
+	v1alphaUtils.CodeNotLeader:        codes.FailedPrecondition,
+	v1alphaUtils.CodeValidationFailed: codes.InvalidArgument,
+	v1alphaUtils.CodeUnauthorized:     codes.Unauthenticated,
+	v1alphaUtils.CodeForbidden:        codes.PermissionDenied,
+	v1alphaUtils.CodeNotFound:         codes.NotFound,
+	v1alphaUtils.CodeConflict:         codes.Aborted,
+	v1alphaUtils.CodeTimeout:          codes.DeadlineExceeded,
+	v1alphaUtils.CodeInternal:         codes.Internal,
+	v1alphaUtils.CodeNotImplemented:   codes.Unimplemented,
+}
+
+// Code returns the gRPC code of the API error code
+func Code(ec v1alphaUtils.ErrorCode) codes.Code {
+	if code, ok := codeGRPC[ec]; ok {
+		return code
+	}
+
+	return codes.Internal
+}
+
+// Error converts the API error to the gRPC status error. The error code and the details
+// are attached to the status, so the clients don't need to parse the message
+func Error(apiErr *v1alphaUtils.APIError) error {
+	st := status.New(Code(apiErr.Code), apiErr.Error())
+
+	detailed, err := st.WithDetails(&apiV1.Error{
+		Code:    string(apiErr.Code),
+		Details: apiErr.Details,
+	})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// AsStatusError classifies the error by the common rules, unless it's already the gRPC one
+func AsStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	return Error(v1alphaUtils.AsAPIError(err))
+}
@@ -0,0 +1,104 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiV1 "github.com/weastur/maf/internal/utils/grpc/api/v1"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code     v1alphaUtils.ErrorCode
+		expected codes.Code
+	}{
+		{v1alphaUtils.CodeNotLeader, codes.FailedPrecondition},
+		{v1alphaUtils.CodeValidationFailed, codes.InvalidArgument},
+		{v1alphaUtils.CodeUnauthorized, codes.Unauthenticated},
+		{v1alphaUtils.CodeForbidden, codes.PermissionDenied},
+		{v1alphaUtils.CodeNotFound, codes.NotFound},
+		{v1alphaUtils.CodeConflict, codes.Aborted},
+		{v1alphaUtils.CodeTimeout, codes.DeadlineExceeded},
+		{v1alphaUtils.CodeInternal, codes.Internal},
+		{v1alphaUtils.CodeNotImplemented, codes.Unimplemented},
+		{v1alphaUtils.ErrorCode("unknown"), codes.Internal},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.code), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, Code(test.code))
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	err := Error(v1alphaUtils.NewAPIError(
+		v1alphaUtils.CodeNotLeader,
+		errors.New("node is not the leader"),
+		map[string]string{v1alphaUtils.DetailLeader: "http://10.0.0.1:7080"},
+	))
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	assert.Equal(t, "node is not the leader", st.Message())
+
+	details := st.Details()
+	require.Len(t, details, 1)
+
+	apiErr, ok := details[0].(*apiV1.Error)
+	require.True(t, ok)
+	assert.Equal(t, "not_leader", apiErr.GetCode())
+	assert.Equal(t, map[string]string{"leader": "http://10.0.0.1:7080"}, apiErr.GetDetails())
+}
+
+func TestAsStatusError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Nil", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, AsStatusError(nil))
+	})
+
+	t.Run("StatusError", func(t *testing.T) {
+		t.Parallel()
+
+		err := status.Error(codes.Unavailable, "unavailable")
+
+		assert.Equal(t, err, AsStatusError(err))
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, codes.Canceled, status.Code(AsStatusError(fmt.Errorf("call: %w", context.Canceled))))
+	})
+
+	t.Run("InsufficientRole", func(t *testing.T) {
+		t.Parallel()
+
+		err := fmt.Errorf("%w: admin is required", v1alphaUtils.ErrInsufficientRole)
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(AsStatusError(err)))
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, codes.Internal, status.Code(AsStatusError(errors.New("boom"))))
+	})
+}
@@ -0,0 +1,164 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/rs/zerolog"
+	"github.com/weastur/maf/internal/utils/certs"
+	apiUtils "github.com/weastur/maf/internal/utils/http/api"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDMetadataKey is the metadata key of the request ID, the same as the HTTP header
+const RequestIDMetadataKey = "x-request-id"
+
+var ErrShutdownTimeout = errors.New("graceful shutdown timed out, connections are closed")
+
+type Config struct {
+	Certs *certs.Store
+	Auth  *v1alphaUtils.Authenticator
+	Roles MethodRoles
+}
+
+// wrappedStream replaces the context of the stream, so the handlers see the identity and the logger
+type wrappedStream struct {
+	grpc.ServerStream
+
+	ctx context.Context //nolint:containedctx
+}
+
+func (s *wrappedStream) Context() context.Context {
+	return s.ctx
+}
+
+// TransportCredentials returns the same TLS setup as the HTTP listener, or nil for the plain text
+func TransportCredentials(store *certs.Store) credentials.TransportCredentials {
+	if store == nil {
+		return nil
+	}
+
+	// gRPC clients require the HTTP/2 to be negotiated with ALPN
+	return credentials.NewTLS(store.ServerConfig("h2"))
+}
+
+// requestContext attaches the request ID, taken from the metadata or generated, and the logger to the call
+func requestContext(ctx context.Context, logger zerolog.Logger, method string) context.Context {
+	var rid string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDMetadataKey); len(values) > 0 {
+			rid = values[0]
+		}
+	}
+
+	if rid == "" {
+		rid = utils.UUIDv4()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, rid))
+
+	ctx = context.WithValue(ctx, apiUtils.RequestIDContextKey, rid)
+	ctxLogger := logger.With().Str("requestId", rid).Str("method", method).Logger()
+
+	return ctxLogger.WithContext(ctx)
+}
+
+func logCall(ctx context.Context, start time.Time, err error) {
+	logger := zerolog.Ctx(ctx)
+	code := status.Code(err)
+
+	event := logger.Debug()
+	if err != nil {
+		event = logger.Error().Err(err)
+	}
+
+	event.Str("code", code.String()).Dur("latency", time.Since(start)).Msg("Call finished")
+}
+
+func unaryRequestInterceptor(logger zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx = requestContext(ctx, logger, info.FullMethod)
+
+		resp, err := handler(ctx, req)
+		logCall(ctx, start, err)
+
+		return resp, err
+	}
+}
+
+func streamRequestInterceptor(logger zerolog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := requestContext(ss.Context(), logger, info.FullMethod)
+
+		err := handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, start, err)
+
+		return err
+	}
+}
+
+// NewServer creates the gRPC server with the TLS, the request logging and the role-based auth
+func NewServer(config *Config, logger zerolog.Logger) *grpc.Server {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			unaryRequestInterceptor(logger),
+			UnaryAuthInterceptor(config.Auth, config.Roles),
+		),
+		grpc.ChainStreamInterceptor(
+			streamRequestInterceptor(logger),
+			StreamAuthInterceptor(config.Auth, config.Roles),
+		),
+	}
+
+	if creds := TransportCredentials(config.Certs); creds != nil {
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	return grpc.NewServer(opts...)
+}
+
+// Serve listens on the address and serves the calls until the server is stopped
+func Serve(server *grpc.Server, logger zerolog.Logger, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	logger.Info().Msgf("Listening on %s", addr)
+
+	if err := server.Serve(listener); err != nil {
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+
+	return nil
+}
+
+// Stop waits for the running calls to finish, but not longer than the timeout, then closes the connections
+func Stop(server *grpc.Server, timeout time.Duration) error {
+	stopped := make(chan struct{})
+
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-time.After(timeout):
+		server.Stop()
+
+		return ErrShutdownTimeout
+	}
+}
@@ -0,0 +1,127 @@
+package grpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weastur/maf/internal/utils/certs"
+	apiUtils "github.com/weastur/maf/internal/utils/http/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	log.Logger = log.Output(zerolog.Nop())
+
+	os.Exit(m.Run())
+}
+
+// writeTestCert writes the self-signed cert and its key to the dir
+func writeTestCert(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "maf"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
+
+func TestTransportCredentials(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PlainText", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, TransportCredentials(nil))
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		t.Parallel()
+
+		certFile, keyFile := writeTestCert(t, t.TempDir())
+
+		store, err := certs.New(certFile, keyFile, certFile)
+		require.NoError(t, err)
+
+		defer store.Close()
+
+		creds := TransportCredentials(store)
+		require.NotNil(t, creds)
+		assert.Equal(t, "tls", creds.Info().SecurityProtocol)
+	})
+}
+
+func TestRequestContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("FromMetadata", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDMetadataKey, "rid"))
+		ctx = requestContext(ctx, log.Logger, "/maf.v1.KV/Get")
+
+		assert.Equal(t, "rid", ctx.Value(apiUtils.RequestIDContextKey))
+	})
+
+	t.Run("Generated", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := requestContext(context.Background(), log.Logger, "/maf.v1.KV/Get")
+
+		rid, _ := ctx.Value(apiUtils.RequestIDContextKey).(string)
+		assert.Len(t, rid, 36)
+	})
+}
+
+func TestNewServer(t *testing.T) {
+	t.Parallel()
+
+	assert.NotNil(t, NewServer(&Config{}, log.Logger))
+}
+
+func TestServe(t *testing.T) {
+	t.Parallel()
+
+	err := Serve(grpc.NewServer(), log.Logger, "invalid_address")
+	require.Error(t, err)
+}
+
+func TestStop(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Stop(grpc.NewServer(), time.Second))
+}
@@ -0,0 +1,29 @@
+//go:generate replacer
+package v1
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/weastur/maf/internal/utils"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+// Get maf version
+//
+// @Summary      Return version
+// @Description  Return the version of running app. Not the API version, but the application
+// @Tags         aux
+// @Success      200 {object} Response{data=Version} "Version"
+// @Router       /version [get]
+// @Header       all {string} X-Request-ID "UUID of the request"
+// @Header       all {string} X-API-Version "API version, e.g. v1"
+// @Header       all {int} X-Ratelimit-Limit "Rate limit value"
+// @Header       all {int} X-Ratelimit-Remaining "Rate limit remaining"
+// @Header       all {int} X-Ratelimit-Reset "Rate limit reset interval in seconds"
+func VersionHandler(c *fiber.Ctx) error {
+	return WrapResponse(c, fiber.StatusOK, &Version{Version: utils.AppVersion()})
+}
+
+// ErrorHandler responds with the typed error and the HTTP status matching its code
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	return WrapError(c, v1alphaUtils.AsAPIError(err))
+}
@@ -0,0 +1,88 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weastur/maf/internal/utils"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+func TestVersionHandler(t *testing.T) {
+	t.Parallel()
+
+	expectedVersion := utils.AppVersion()
+
+	app := fiber.New()
+	app.Get("/version", VersionHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/version", nil)
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"data":{"version":"`+expectedVersion+`"}}`, string(body))
+}
+
+func TestErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		status   int
+		expected string
+	}{
+		{
+			name:     "Unknown",
+			err:      errors.New("test error"),
+			status:   http.StatusInternalServerError,
+			expected: `{"error":{"code":"internal","message":"test error"}}`,
+		},
+		{
+			name: "Typed",
+			err: v1alphaUtils.NewAPIError(
+				v1alphaUtils.CodeNotLeader,
+				errors.New("node is not a leader"),
+				map[string]string{v1alphaUtils.DetailLeader: "http://10.0.0.1:7080"},
+			),
+			status: http.StatusMisdirectedRequest,
+			expected: `{"error":{"code":"not_leader","message":"node is not a leader",` +
+				`"details":{"leader":"http://10.0.0.1:7080"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := fiber.New(fiber.Config{
+				ErrorHandler: ErrorHandler,
+			})
+
+			app.Get("/error", func(_ *fiber.Ctx) error {
+				return tt.err
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/error", nil)
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.JSONEq(t, tt.expected, string(body))
+		})
+	}
+}
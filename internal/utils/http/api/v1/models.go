@@ -0,0 +1,31 @@
+package v1
+
+import v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+
+// Response wrapper
+// @Description Response with either the data or the typed error. The outcome is reported by the HTTP status too
+type Response struct {
+	// Any structured data. Omitted on error
+	Data any `json:"data,omitempty" swaggertype:"object"`
+	// Typed error. Omitted on success
+	Error *Error `json:"error,omitempty"`
+} // @Name Response
+
+// Error
+// @Description Typed error with the machine-readable code and the structured details
+type Error struct {
+	// Machine-readable error code:
+	// not_leader, validation_failed, unauthorized, forbidden, not_found, conflict, timeout, internal,
+	// not_implemented
+	Code v1alphaUtils.ErrorCode `example:"not_leader" json:"code" swaggertype:"string"`
+	// Human-readable error message
+	Message string `example:"node is not a leader" json:"message"`
+	// Structured error details, e.g. the leader address or the failing field
+	Details map[string]string `json:"details,omitempty"`
+} // @Name Error
+
+// Version
+// @Description Application version
+type Version struct {
+	Version string `example:"v1.0.0" json:"version"`
+} // @Name VersionResponse
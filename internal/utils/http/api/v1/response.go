@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"github.com/gofiber/fiber/v2"
+	v1alphaUtils "github.com/weastur/maf/internal/utils/http/api/v1alpha"
+)
+
+func WrapResponse(c *fiber.Ctx, status int, data any) error {
+	return c.Status(status).JSON(Response{Data: data})
+}
+
+func WrapError(c *fiber.Ctx, apiErr *v1alphaUtils.APIError) error {
+	return c.Status(apiErr.Code.Status()).JSON(Response{
+		Error: &Error{
+			Code:    apiErr.Code,
+			Message: apiErr.Error(),
+			Details: apiErr.Details,
+		},
+	})
+}
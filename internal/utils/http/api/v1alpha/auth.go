@@ -3,21 +3,371 @@ package v1alpha
 import (
 	"crypto/sha256"
 	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/keyauth"
 	"github.com/rs/zerolog"
+	apiUtils "github.com/weastur/maf/internal/utils/http/api"
+)
+
+type Role int
+
+const (
+	RoleRead Role = iota + 1
+	RoleOperator
+	RoleAdmin
+)
+
+const (
+	identityLocalsKey = "identity"
+	authHeader        = "X-Auth-Token"
 )
 
 var (
-	apiKey          = "root"
+	ErrNoCredentials    = errors.New("no API tokens or client certificates configured")
+	ErrDisabledWithAuth = errors.New("auth is disabled, but API tokens or client certificates are configured")
+	ErrAuthModeChange   = errors.New("auth can't be enabled or disabled without restart")
+	ErrInvalidCertRule  = errors.New("invalid client certificate config")
+	ErrEmptyCertRule    = errors.New("either common_name or san must be set")
+	ErrInvalidToken     = errors.New("invalid API token config")
+	ErrUnknownRole      = errors.New("unknown role")
+	ErrInsufficientRole = errors.New("insufficient role")
+	ErrNotAPeer         = errors.New("only the servers of the cluster are allowed")
+	ErrMissingToken     = errors.New("token is not set")
+	ErrTokenSources     = errors.New("only one of token, token_file, token_env and token_sha256 must be set")
+
 	unprotectedURLs = []*regexp.Regexp{
 		regexp.MustCompile(`.*\/version$`),
 	}
+
+	roleName = map[Role]string{
+		RoleRead:     "read",
+		RoleOperator: "operator",
+		RoleAdmin:    "admin",
+	}
+
+	// LocalIdentity is granted to the requests coming through the Unix socket, which is trusted by its file mode
+	LocalIdentity = Identity{Name: "local", Role: RoleAdmin}
+
+	// AnonymousIdentity is granted to every request if the auth is explicitly disabled
+	AnonymousIdentity = Identity{Name: "anonymous", Role: RoleAdmin, Peer: true}
 )
 
+func (r Role) String() string {
+	return roleName[r]
+}
+
+func ParseRole(name string) (Role, error) {
+	for role, roleName := range roleName {
+		if strings.EqualFold(name, roleName) {
+			return role, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrUnknownRole, name)
+}
+
+// TokenConfig describes a single API token. The token itself can be set inline, read from a file
+// or an environment variable, or given as a SHA-256 hex digest to keep the plain text out of the config.
+// Peer marks the token of the other servers of the cluster, they are trusted to forward the audit records.
+type TokenConfig struct {
+	Name        string `mapstructure:"name"`
+	Role        string `mapstructure:"role"`
+	Peer        bool   `mapstructure:"peer"`
+	Token       string `mapstructure:"token"`
+	TokenFile   string `mapstructure:"token_file"`
+	TokenEnv    string `mapstructure:"token_env"`
+	TokenSHA256 string `mapstructure:"token_sha256"`
+}
+
+// ClientCertConfig maps a client certificate to a role. If both fields are set, both must match.
+// SAN is compared against the DNS names, email addresses, IP addresses and URIs of the certificate.
+type ClientCertConfig struct {
+	Name       string `mapstructure:"name"`
+	Role       string `mapstructure:"role"`
+	Peer       bool   `mapstructure:"peer"`
+	CommonName string `mapstructure:"common_name"`
+	SAN        string `mapstructure:"san"`
+}
+
+// AuthConfig requires at least one token or client certificate, unless the auth is disabled.
+// Disabled grants the admin role to everyone and is meant only for the local development.
+// PeerToken is the token the server calls the other servers with, it's a peer token too,
+// since all the servers of the cluster share it.
+type AuthConfig struct {
+	Disabled    bool               `mapstructure:"disabled"`
+	Tokens      []TokenConfig      `mapstructure:"tokens"`
+	ClientCerts []ClientCertConfig `mapstructure:"client_certs"`
+	PeerToken   string             `mapstructure:"-"`
+}
+
+type Identity struct {
+	Name string
+	Role Role
+	Peer bool
+}
+
+type credential struct {
+	hash     []byte
+	identity Identity
+}
+
+type certRule struct {
+	commonName string
+	san        string
+	identity   Identity
+}
+
+type errorHandler interface {
+	ErrorHandler(c *fiber.Ctx, err error) error
+}
+
+// Authenticator keeps only the hashes of the configured tokens
+type Authenticator struct {
+	mu          sync.RWMutex
+	disabled    bool
+	credentials []credential
+	certRules   []certRule
+}
+
+func (tc *TokenConfig) secretHash() ([]byte, error) {
+	sources := 0
+
+	for _, src := range []string{tc.Token, tc.TokenFile, tc.TokenEnv, tc.TokenSHA256} {
+		if src != "" {
+			sources++
+		}
+	}
+
+	if sources > 1 {
+		return nil, ErrTokenSources
+	}
+
+	var secret string
+
+	switch {
+	case tc.TokenSHA256 != "":
+		hash, err := hex.DecodeString(tc.TokenSHA256)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("%w: token_sha256 must be a hex encoded SHA-256 digest", ErrInvalidToken)
+		}
+
+		return hash, nil
+	case tc.TokenFile != "":
+		data, err := os.ReadFile(tc.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token file: %w", err)
+		}
+
+		secret = strings.TrimSpace(string(data))
+	case tc.TokenEnv != "":
+		secret = os.Getenv(tc.TokenEnv)
+	default:
+		secret = tc.Token
+	}
+
+	if secret == "" {
+		return nil, ErrMissingToken
+	}
+
+	hash := sha256.Sum256([]byte(secret))
+
+	return hash[:], nil
+}
+
+func NewAuthenticator(config *AuthConfig) (*Authenticator, error) {
+	if config != nil && config.Disabled {
+		if len(config.Tokens)+len(config.ClientCerts) > 0 {
+			return nil, ErrDisabledWithAuth
+		}
+
+		return &Authenticator{disabled: true}, nil
+	}
+
+	if config == nil || len(config.Tokens)+len(config.ClientCerts) == 0 {
+		return nil, ErrNoCredentials
+	}
+
+	// an empty token is never matched, since the configured tokens can't be empty
+	peerHash := sha256.Sum256([]byte(config.PeerToken))
+	if config.PeerToken == "" {
+		peerHash = [sha256.Size]byte{}
+	}
+
+	auth := &Authenticator{
+		credentials: make([]credential, 0, len(config.Tokens)),
+		certRules:   make([]certRule, 0, len(config.ClientCerts)),
+	}
+
+	for i, tc := range config.Tokens {
+		name := tc.Name
+		if name == "" {
+			name = fmt.Sprintf("token-%d", i)
+		}
+
+		role, err := ParseRole(tc.Role)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrInvalidToken, name, err)
+		}
+
+		hash, err := tc.secretHash()
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrInvalidToken, name, err)
+		}
+
+		peer := tc.Peer || subtle.ConstantTimeCompare(hash, peerHash[:]) == 1
+
+		auth.credentials = append(auth.credentials, credential{
+			hash:     hash,
+			identity: Identity{Name: name, Role: role, Peer: peer},
+		})
+	}
+
+	for i, cc := range config.ClientCerts {
+		name := cc.Name
+		if name == "" {
+			name = fmt.Sprintf("client-cert-%d", i)
+		}
+
+		role, err := ParseRole(cc.Role)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrInvalidCertRule, name, err)
+		}
+
+		if cc.CommonName == "" && cc.SAN == "" {
+			return nil, fmt.Errorf("%w %s: %w", ErrInvalidCertRule, name, ErrEmptyCertRule)
+		}
+
+		auth.certRules = append(auth.certRules, certRule{
+			commonName: cc.CommonName,
+			san:        cc.SAN,
+			identity:   Identity{Name: name, Role: role, Peer: cc.Peer},
+		})
+	}
+
+	return auth, nil
+}
+
+// Update replaces the credentials in place, e.g. on the config reload.
+// The current ones are kept if the config is invalid or the auth is enabled or disabled
+func (a *Authenticator) Update(config *AuthConfig) error {
+	next, err := NewAuthenticator(config)
+	if err != nil {
+		return err
+	}
+
+	if next.disabled != a.Disabled() {
+		return ErrAuthModeChange
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.credentials = next.credentials
+	a.certRules = next.certRules
+
+	return nil
+}
+
+// Disabled reports whether every request is granted the AnonymousIdentity. It never changes on the reload
+func (a *Authenticator) Disabled() bool {
+	return a != nil && a.disabled
+}
+
+// Authenticate returns the identity the key belongs to. All the credentials are compared
+// regardless of the match to not leak the position of the token through the timing.
+func (a *Authenticator) Authenticate(key string) (*Identity, bool) {
+	if a == nil || key == "" {
+		return nil, false
+	}
+
+	hashedKey := sha256.Sum256([]byte(key))
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var found *Identity
+
+	for i := range a.credentials {
+		if subtle.ConstantTimeCompare(a.credentials[i].hash, hashedKey[:]) == 1 && found == nil {
+			found = &a.credentials[i].identity
+		}
+	}
+
+	return found, found != nil
+}
+
+func certSANs(cert *x509.Certificate) []string {
+	sans := make([]string, 0, len(cert.DNSNames)+len(cert.EmailAddresses)+len(cert.IPAddresses)+len(cert.URIs))
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	return sans
+}
+
+func (r *certRule) matches(cert *x509.Certificate) bool {
+	if r.commonName != "" && r.commonName != cert.Subject.CommonName {
+		return false
+	}
+
+	if r.san != "" && !slices.Contains(certSANs(cert), r.san) {
+		return false
+	}
+
+	return true
+}
+
+// AuthenticateCert returns the identity of the first rule matching the verified client certificate.
+// The certificate chain is verified by the TLS listener, so only the leaf is inspected here.
+func (a *Authenticator) AuthenticateCert(state *tls.ConnectionState) (*Identity, bool) {
+	if a == nil || state == nil || len(state.VerifiedChains) == 0 || len(state.PeerCertificates) == 0 {
+		return nil, false
+	}
+
+	cert := state.PeerCertificates[0]
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for i := range a.certRules {
+		if a.certRules[i].matches(cert) {
+			return &a.certRules[i].identity, true
+		}
+	}
+
+	return nil, false
+}
+
+// setIdentity stores the identity for the handlers and attaches it to the request logger
+func setIdentity(c *fiber.Ctx, identity *Identity) {
+	c.Locals(identityLocalsKey, identity)
+
+	logger := zerolog.Ctx(c.UserContext()).With().
+		Str("identity", identity.Name).
+		Str("role", identity.Role.String()).
+		Logger()
+
+	c.SetUserContext(logger.WithContext(c.UserContext()))
+}
+
 func authFilter(c *fiber.Ctx) bool {
 	url := strings.ToLower(c.OriginalURL())
 	logger := zerolog.Ctx(c.UserContext())
@@ -33,27 +383,110 @@ func authFilter(c *fiber.Ctx) bool {
 	return false
 }
 
-func validateAPIKey(c *fiber.Ctx, key string) (bool, error) {
-	hashedAPIKey := sha256.Sum256([]byte(apiKey))
-	hashedKey := sha256.Sum256([]byte(key))
-	logger := zerolog.Ctx(c.UserContext())
+func isLocalSocket(c *fiber.Ctx) bool {
+	return c.Context().LocalAddr().Network() == "unix"
+}
 
-	if subtle.ConstantTimeCompare(hashedAPIKey[:], hashedKey[:]) == 1 {
-		return true, nil
+// authErrorHandler responds in the format of the API version the middleware is mounted to
+func authErrorHandler(c *fiber.Ctx, err error) error {
+	if api, ok := c.UserContext().Value(apiUtils.APIInstanceContextKey).(errorHandler); ok {
+		return api.ErrorHandler(c, err)
 	}
 
-	logger.Warn().Msg("API key is missing or malformed")
-
-	return false, keyauth.ErrMissingOrMalformedAPIKey
+	return ErrorHandler(c, err)
 }
 
-func AuthMiddleware() fiber.Handler {
+// AuthMiddleware authenticates the request by the X-Auth-Token header. If the header is absent,
+// the verified client certificate is used instead, so the mTLS clients don't need a token.
+// The requests through the Unix socket need neither and get the LocalIdentity.
+// If the auth is disabled, the rest of the requests get the AnonymousIdentity.
+func AuthMiddleware(auth *Authenticator) fiber.Handler {
 	return keyauth.New(keyauth.Config{
-		ErrorHandler: ErrorHandler,
-		Next:         authFilter,
-		Validator:    validateAPIKey,
-		KeyLookup:    "header:X-Auth-Token",
-		AuthScheme:   "",
-		ContextKey:   "token",
+		ErrorHandler: authErrorHandler,
+		Next: func(c *fiber.Ctx) bool {
+			if authFilter(c) {
+				return true
+			}
+
+			if isLocalSocket(c) {
+				setIdentity(c, &LocalIdentity)
+				zerolog.Ctx(c.UserContext()).Trace().Msg("Authenticated by local socket")
+
+				return true
+			}
+
+			if auth.Disabled() {
+				setIdentity(c, &AnonymousIdentity)
+				zerolog.Ctx(c.UserContext()).Trace().Msg("Auth is disabled")
+
+				return true
+			}
+
+			if c.Get(authHeader) != "" {
+				return false
+			}
+
+			identity, ok := auth.AuthenticateCert(c.Context().TLSConnectionState())
+			if ok {
+				setIdentity(c, identity)
+				zerolog.Ctx(c.UserContext()).Trace().Msg("Authenticated by client certificate")
+			}
+
+			return ok
+		},
+		Validator: func(c *fiber.Ctx, key string) (bool, error) {
+			logger := zerolog.Ctx(c.UserContext())
+
+			identity, ok := auth.Authenticate(key)
+			if !ok {
+				logger.Warn().Msg("API key is missing or malformed")
+
+				return false, keyauth.ErrMissingOrMalformedAPIKey
+			}
+
+			setIdentity(c, identity)
+			zerolog.Ctx(c.UserContext()).Trace().Msg("Authenticated by token")
+
+			return true, nil
+		},
+		KeyLookup:  "header:" + authHeader,
+		AuthScheme: "",
+		ContextKey: "token",
 	})
 }
+
+func GetIdentity(c *fiber.Ctx) (*Identity, bool) {
+	identity, ok := c.Locals(identityLocalsKey).(*Identity)
+
+	return identity, ok
+}
+
+// RequireRole allows the request only if the authenticated identity has at least the given role
+func RequireRole(role Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identity, ok := GetIdentity(c)
+		if !ok || identity.Role < role {
+			logger := zerolog.Ctx(c.UserContext())
+			logger.Warn().Msgf("Role %s is required to access %s", role, c.Path())
+
+			return fmt.Errorf("%w: %s is required", ErrInsufficientRole, role)
+		}
+
+		return c.Next()
+	}
+}
+
+// RequirePeer allows the request only from the other servers of the cluster
+func RequirePeer() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identity, ok := GetIdentity(c)
+		if !ok || !identity.Peer {
+			logger := zerolog.Ctx(c.UserContext())
+			logger.Warn().Msgf("Peer identity is required to access %s", c.Path())
+
+			return ErrNotAPeer
+		}
+
+		return c.Next()
+	}
+}
@@ -1,8 +1,17 @@
 package v1alpha
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
 	"io"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
@@ -11,13 +20,26 @@ import (
 	"github.com/weastur/maf/internal/utils"
 )
 
+func newTestAuthenticator(t *testing.T) *Authenticator {
+	t.Helper()
+
+	auth, err := NewAuthenticator(&AuthConfig{Tokens: []TokenConfig{
+		{Name: "reader", Role: "read", Token: "read-token"},
+		{Name: "operator", Role: "operator", Token: "operator-token"},
+		{Name: "admin", Role: "admin", Token: "admin-token"},
+	}})
+	require.NoError(t, err)
+
+	return auth
+}
+
 func TestAuthFilter(t *testing.T) {
 	t.Parallel()
 
 	expectedVersion := utils.AppVersion()
 
 	app := fiber.New()
-	app.Use(AuthMiddleware())
+	app.Use(AuthMiddleware(newTestAuthenticator(t)))
 	app.Get("/protected", func(c *fiber.Ctx) error {
 		return c.SendStatus(http.StatusOK)
 	})
@@ -25,10 +47,19 @@ func TestAuthFilter(t *testing.T) {
 
 	tests := []struct {
 		url          string
+		expectedCode int
 		expectedResp string
 	}{
-		{url: "/version", expectedResp: `{"status":"success","error":"","data":{"version":"` + expectedVersion + `"}}`},
-		{url: "/protected", expectedResp: `{"status":"error","error":"missing or malformed API Key","data":{}}`},
+		{
+			url:          "/version",
+			expectedCode: http.StatusOK,
+			expectedResp: `{"status":"success","error":"","data":{"version":"` + expectedVersion + `"}}`,
+		},
+		{
+			url:          "/protected",
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"status":"error","error":"missing or malformed API Key","code":"unauthorized","data":{}}`,
+		},
 	}
 
 	for _, test := range tests {
@@ -39,7 +70,7 @@ func TestAuthFilter(t *testing.T) {
 		body, err := io.ReadAll(resp.Body)
 		require.NoError(t, err)
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, test.expectedCode, resp.StatusCode)
 		assert.JSONEq(t, test.expectedResp, string(body))
 	}
 }
@@ -48,7 +79,7 @@ func TestApiKey(t *testing.T) {
 	t.Parallel()
 
 	app := fiber.New()
-	app.Use(AuthMiddleware())
+	app.Use(AuthMiddleware(newTestAuthenticator(t)))
 	app.Get("/protected", func(c *fiber.Ctx) error {
 		return c.SendStatus(http.StatusOK)
 	})
@@ -61,21 +92,21 @@ func TestApiKey(t *testing.T) {
 	}{
 		{
 			name:         "Valid API Key",
-			apiKey:       "root",
+			apiKey:       "admin-token",
 			expectedCode: http.StatusOK,
 			expectedResp: "",
 		},
 		{
 			name:         "Invalid API Key",
 			apiKey:       "invalid",
-			expectedCode: http.StatusOK,
-			expectedResp: `{"status":"error","error":"missing or malformed API Key","data":{}}`,
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"status":"error","error":"missing or malformed API Key","code":"unauthorized","data":{}}`,
 		},
 		{
 			name:         "Missing API Key",
 			apiKey:       "",
-			expectedCode: http.StatusOK,
-			expectedResp: `{"status":"error","error":"missing or malformed API Key","data":{}}`,
+			expectedCode: http.StatusUnauthorized,
+			expectedResp: `{"status":"error","error":"missing or malformed API Key","code":"unauthorized","data":{}}`,
 		},
 	}
 
@@ -102,3 +133,347 @@ func TestApiKey(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRole(t *testing.T) {
+	t.Parallel()
+
+	role, err := ParseRole("Operator")
+	require.NoError(t, err)
+	assert.Equal(t, RoleOperator, role)
+	assert.Equal(t, "operator", role.String())
+
+	_, err = ParseRole("root")
+	require.ErrorIs(t, err, ErrUnknownRole)
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("file-token\n"), 0o600))
+
+	sum := sha256.Sum256([]byte("hashed-token"))
+
+	t.Setenv("MAF_TEST_AUTH_TOKEN", "env-token")
+
+	auth, err := NewAuthenticator(&AuthConfig{Tokens: []TokenConfig{
+		{Name: "inline", Role: "read", Token: "inline-token"},
+		{Name: "file", Role: "operator", TokenFile: tokenFile},
+		{Name: "env", Role: "admin", TokenEnv: "MAF_TEST_AUTH_TOKEN"},
+		{Name: "hashed", Role: "read", TokenSHA256: hex.EncodeToString(sum[:])},
+	}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		key  string
+		name string
+		role Role
+	}{
+		{key: "inline-token", name: "inline", role: RoleRead},
+		{key: "file-token", name: "file", role: RoleOperator},
+		{key: "env-token", name: "env", role: RoleAdmin},
+		{key: "hashed-token", name: "hashed", role: RoleRead},
+	}
+
+	for _, test := range tests {
+		identity, ok := auth.Authenticate(test.key)
+		require.True(t, ok, test.key)
+		assert.Equal(t, test.name, identity.Name)
+		assert.Equal(t, test.role, identity.Role)
+	}
+
+	_, ok := auth.Authenticate("unknown")
+	assert.False(t, ok)
+
+	_, ok = auth.Authenticate("")
+	assert.False(t, ok)
+}
+
+func TestNewAuthenticatorErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config *AuthConfig
+		err    error
+	}{
+		{name: "nil config", config: nil, err: ErrNoCredentials},
+		{name: "no credentials", config: &AuthConfig{}, err: ErrNoCredentials},
+		{name: "unknown role", config: &AuthConfig{Tokens: []TokenConfig{{Role: "root", Token: "x"}}}, err: ErrUnknownRole},
+		{name: "missing token", config: &AuthConfig{Tokens: []TokenConfig{{Role: "read"}}}, err: ErrMissingToken},
+		{
+			name:   "several sources",
+			config: &AuthConfig{Tokens: []TokenConfig{{Role: "read", Token: "x", TokenEnv: "MAF_TOKEN"}}},
+			err:    ErrTokenSources,
+		},
+		{
+			name:   "bad digest",
+			config: &AuthConfig{Tokens: []TokenConfig{{Role: "read", TokenSHA256: "abc"}}},
+			err:    ErrInvalidToken,
+		},
+		{
+			name:   "missing file",
+			config: &AuthConfig{Tokens: []TokenConfig{{Role: "read", TokenFile: "/nonexistent/token"}}},
+			err:    os.ErrNotExist,
+		},
+		{
+			name:   "empty cert rule",
+			config: &AuthConfig{ClientCerts: []ClientCertConfig{{Role: "read"}}},
+			err:    ErrEmptyCertRule,
+		},
+		{
+			name:   "cert rule with unknown role",
+			config: &AuthConfig{ClientCerts: []ClientCertConfig{{Role: "root", CommonName: "maf"}}},
+			err:    ErrUnknownRole,
+		},
+		{
+			name:   "disabled with tokens",
+			config: &AuthConfig{Disabled: true, Tokens: []TokenConfig{{Role: "read", Token: "x"}}},
+			err:    ErrDisabledWithAuth,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewAuthenticator(test.config)
+			require.ErrorIs(t, err, test.err)
+		})
+	}
+}
+
+func TestAuthenticatorUpdate(t *testing.T) {
+	t.Parallel()
+
+	auth, err := NewAuthenticator(&AuthConfig{Tokens: []TokenConfig{{Name: "old", Role: "read", Token: "old-token"}}})
+	require.NoError(t, err)
+
+	require.NoError(t, auth.Update(&AuthConfig{Tokens: []TokenConfig{{Name: "new", Role: "admin", Token: "new-token"}}}))
+
+	_, ok := auth.Authenticate("old-token")
+	assert.False(t, ok)
+
+	identity, ok := auth.Authenticate("new-token")
+	require.True(t, ok)
+	assert.Equal(t, Identity{Name: "new", Role: RoleAdmin}, *identity)
+
+	// The invalid config keeps the current credentials
+	require.ErrorIs(t, auth.Update(&AuthConfig{}), ErrNoCredentials)
+
+	_, ok = auth.Authenticate("new-token")
+	assert.True(t, ok)
+
+	// The auth can't be disabled on the reload
+	require.ErrorIs(t, auth.Update(&AuthConfig{Disabled: true}), ErrAuthModeChange)
+	assert.False(t, auth.Disabled())
+}
+
+func TestAuthDisabled(t *testing.T) {
+	t.Parallel()
+
+	auth, err := NewAuthenticator(&AuthConfig{Disabled: true})
+	require.NoError(t, err)
+	assert.True(t, auth.Disabled())
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(AuthMiddleware(auth))
+	app.Post("/forget", RequireRole(RoleAdmin), func(c *fiber.Ctx) error {
+		identity, _ := GetIdentity(c)
+
+		return c.SendString(identity.Name)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/forget", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, AnonymousIdentity.Name, string(body))
+
+	// The auth can't be enabled on the reload
+	require.ErrorIs(t, auth.Update(&AuthConfig{Tokens: []TokenConfig{{Role: "read", Token: "x"}}}), ErrAuthModeChange)
+	assert.True(t, auth.Disabled())
+}
+
+func TestAuthenticateCert(t *testing.T) {
+	t.Parallel()
+
+	auth, err := NewAuthenticator(&AuthConfig{ClientCerts: []ClientCertConfig{
+		{Name: "server", Role: "operator", CommonName: "maf-server", SAN: "server.maf.local"},
+		{Name: "ops", Role: "read", SAN: "ops@example.com"},
+	}})
+	require.NoError(t, err)
+
+	state := func(cert *x509.Certificate, verified bool) *tls.ConnectionState {
+		state := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+		if verified {
+			state.VerifiedChains = [][]*x509.Certificate{{cert}}
+		}
+
+		return state
+	}
+
+	serverCert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "maf-server"},
+		DNSNames: []string{"server.maf.local"},
+	}
+	opsCert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "alice"},
+		EmailAddresses: []string{"ops@example.com"},
+	}
+	wrongSANCert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "maf-server"},
+		DNSNames: []string{"other.maf.local"},
+	}
+
+	identity, ok := auth.AuthenticateCert(state(serverCert, true))
+	require.True(t, ok)
+	assert.Equal(t, Identity{Name: "server", Role: RoleOperator}, *identity)
+
+	identity, ok = auth.AuthenticateCert(state(opsCert, true))
+	require.True(t, ok)
+	assert.Equal(t, Identity{Name: "ops", Role: RoleRead}, *identity)
+
+	_, ok = auth.AuthenticateCert(state(wrongSANCert, true))
+	assert.False(t, ok)
+
+	_, ok = auth.AuthenticateCert(state(serverCert, false))
+	assert.False(t, ok, "unverified certificates must be ignored")
+
+	_, ok = auth.AuthenticateCert(nil)
+	assert.False(t, ok)
+}
+
+func TestRequireRole(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(AuthMiddleware(newTestAuthenticator(t)))
+	app.Get("/info", RequireRole(RoleRead), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	app.Post("/forget", RequireRole(RoleAdmin), func(c *fiber.Ctx) error {
+		identity, _ := GetIdentity(c)
+
+		return c.SendString(identity.Name)
+	})
+
+	tests := []struct {
+		method string
+		url    string
+		key    string
+		resp   string
+	}{
+		{method: http.MethodGet, url: "/info", key: "read-token", resp: "ok"},
+		{method: http.MethodGet, url: "/info", key: "admin-token", resp: "ok"},
+		{method: http.MethodPost, url: "/forget", key: "admin-token", resp: "admin"},
+		{
+			method: http.MethodPost,
+			url:    "/forget",
+			key:    "operator-token",
+			resp:   "insufficient role: admin is required",
+		},
+	}
+
+	for _, test := range tests {
+		req, _ := http.NewRequest(test.method, test.url, nil)
+		req.Header.Set("X-Auth-Token", test.key)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Contains(t, string(body), test.resp)
+	}
+}
+
+func TestRequirePeer(t *testing.T) {
+	t.Parallel()
+
+	auth, err := NewAuthenticator(&AuthConfig{
+		Tokens: []TokenConfig{
+			{Name: "admin", Role: "admin", Token: "admin-token"},
+			{Name: "server", Role: "admin", Token: "server-token"},
+			{Name: "peer", Role: "operator", Token: "peer-token", Peer: true},
+		},
+		PeerToken: "server-token",
+	})
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(AuthMiddleware(auth))
+	app.Post("/audit", RequirePeer(), func(c *fiber.Ctx) error {
+		identity, _ := GetIdentity(c)
+
+		return c.SendString(identity.Name)
+	})
+
+	tests := []struct {
+		key    string
+		status int
+		resp   string
+	}{
+		{key: "server-token", status: http.StatusOK, resp: "server"},
+		{key: "peer-token", status: http.StatusOK, resp: "peer"},
+		{key: "admin-token", status: http.StatusForbidden, resp: "only the servers of the cluster are allowed"},
+	}
+
+	for _, test := range tests {
+		req, _ := http.NewRequest(http.MethodPost, "/audit", nil)
+		req.Header.Set("X-Auth-Token", test.key)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, test.status, resp.StatusCode, test.key)
+		assert.Contains(t, string(body), test.resp)
+	}
+}
+
+func TestLocalSocket(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(AuthMiddleware(newTestAuthenticator(t)))
+	app.Post("/forget", RequireRole(RoleAdmin), func(c *fiber.Ctx) error {
+		identity, _ := GetIdentity(c)
+
+		return c.SendString(identity.Name)
+	})
+
+	ln, err := net.Listen("unix", filepath.Join(t.TempDir(), "maf.sock"))
+	require.NoError(t, err)
+
+	go func() {
+		_ = app.Listener(ln)
+	}()
+
+	t.Cleanup(func() {
+		_ = app.Shutdown()
+	})
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", ln.Addr().String())
+		},
+	}}
+
+	req, _ := http.NewRequest(http.MethodPost, "http://localhost/forget", nil)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, LocalIdentity.Name, string(body))
+}
@@ -0,0 +1,127 @@
+package v1alpha
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/keyauth"
+)
+
+// ErrorCode is the machine-readable code of the API error, mapped to the HTTP status
+type ErrorCode string
+
+const (
+	CodeNotLeader        ErrorCode = "not_leader"
+	CodeValidationFailed ErrorCode = "validation_failed"
+	CodeUnauthorized     ErrorCode = "unauthorized"
+	CodeForbidden        ErrorCode = "forbidden"
+	CodeNotFound         ErrorCode = "not_found"
+	CodeConflict         ErrorCode = "conflict"
+	CodeTimeout          ErrorCode = "timeout"
+	CodeInternal         ErrorCode = "internal"
+	CodeNotImplemented   ErrorCode = "not_implemented"
+)
+
+// Detail keys used across the API
+const (
+	DetailLeader = "leader"
+	DetailField  = "field"
+	DetailTag    = "tag"
+)
+
+var codeStatus = map[ErrorCode]int{
+	// The request must be sent to the leader, it's not a server failure, so the client doesn't retry it
+	CodeNotLeader:        fiber.StatusMisdirectedRequest,
+	CodeValidationFailed: fiber.StatusBadRequest,
+	CodeUnauthorized:     fiber.StatusUnauthorized,
+	CodeForbidden:        fiber.StatusForbidden,
+	CodeNotFound:         fiber.StatusNotFound,
+	CodeConflict:         fiber.StatusConflict,
+	CodeTimeout:          fiber.StatusGatewayTimeout,
+	CodeInternal:         fiber.StatusInternalServerError,
+	// The resource isn't served yet, the client doesn't retry it
+	CodeNotImplemented: fiber.StatusNotImplemented,
+}
+
+// Status returns the HTTP status of the error code
+func (ec ErrorCode) Status() int {
+	if status, ok := codeStatus[ec]; ok {
+		return status
+	}
+
+	return fiber.StatusInternalServerError
+}
+
+// APIError is the error with the code and the structured details, e.g. the leader address
+type APIError struct {
+	Code    ErrorCode
+	Details map[string]string
+	Err     error
+}
+
+func NewAPIError(code ErrorCode, err error, details map[string]string) *APIError {
+	return &APIError{Code: code, Details: details, Err: err}
+}
+
+func (e *APIError) Error() string {
+	if e.Err == nil {
+		return string(e.Code)
+	}
+
+	return e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// AsAPIError classifies the errors common for all the APIs. Unknown errors become internal ones
+func AsAPIError(err error) *APIError {
+	var (
+		apiErr        *APIError
+		validationErr *ValidationError
+		fiberErr      *fiber.Error
+	)
+
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr
+	case errors.As(err, &validationErr):
+		return NewAPIError(CodeValidationFailed, err, map[string]string{
+			DetailField: validationErr.Field,
+			DetailTag:   validationErr.Tag,
+		})
+	case errors.Is(err, keyauth.ErrMissingOrMalformedAPIKey):
+		return NewAPIError(CodeUnauthorized, err, nil)
+	case errors.Is(err, ErrInsufficientRole), errors.Is(err, ErrNotAPeer):
+		return NewAPIError(CodeForbidden, err, nil)
+	case errors.Is(err, context.DeadlineExceeded):
+		return NewAPIError(CodeTimeout, err, nil)
+	case errors.As(err, &fiberErr):
+		return NewAPIError(fiberErrorCode(fiberErr.Code), err, nil)
+	}
+
+	return NewAPIError(CodeInternal, err, nil)
+}
+
+func fiberErrorCode(status int) ErrorCode {
+	switch status {
+	case fiber.StatusBadRequest, fiber.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case fiber.StatusUnauthorized:
+		return CodeUnauthorized
+	case fiber.StatusForbidden:
+		return CodeForbidden
+	case fiber.StatusNotFound, fiber.StatusMethodNotAllowed:
+		return CodeNotFound
+	case fiber.StatusConflict:
+		return CodeConflict
+	case fiber.StatusRequestTimeout, fiber.StatusGatewayTimeout:
+		return CodeTimeout
+	case fiber.StatusNotImplemented:
+		return CodeNotImplemented
+	}
+
+	return CodeInternal
+}
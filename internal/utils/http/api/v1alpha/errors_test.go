@@ -0,0 +1,86 @@
+package v1alpha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/keyauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCodeStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := map[ErrorCode]int{
+		CodeNotLeader:        fiber.StatusMisdirectedRequest,
+		CodeValidationFailed: fiber.StatusBadRequest,
+		CodeUnauthorized:     fiber.StatusUnauthorized,
+		CodeForbidden:        fiber.StatusForbidden,
+		CodeNotFound:         fiber.StatusNotFound,
+		CodeConflict:         fiber.StatusConflict,
+		CodeTimeout:          fiber.StatusGatewayTimeout,
+		CodeInternal:         fiber.StatusInternalServerError,
+		CodeNotImplemented:   fiber.StatusNotImplemented,
+		ErrorCode("unknown"): fiber.StatusInternalServerError,
+	}
+
+	for code, status := range tests {
+		assert.Equal(t, status, code.Status(), code)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	t.Parallel()
+
+	err := errors.New("some error")
+	apiErr := NewAPIError(CodeConflict, err, nil)
+
+	assert.Equal(t, "some error", apiErr.Error())
+	require.ErrorIs(t, apiErr, err)
+	assert.Equal(t, "not_found", NewAPIError(CodeNotFound, nil, nil).Error())
+}
+
+func TestAsAPIError(t *testing.T) {
+	t.Parallel()
+
+	apiErr := NewAPIError(CodeNotLeader, errors.New("not a leader"), map[string]string{DetailLeader: "addr"})
+
+	tests := []struct {
+		name    string
+		err     error
+		code    ErrorCode
+		details map[string]string
+	}{
+		{"APIError", fmt.Errorf("wrapped: %w", apiErr), CodeNotLeader, map[string]string{DetailLeader: "addr"}},
+		{
+			"ValidationError",
+			&ValidationError{Field: "ServerID", Tag: "required"},
+			CodeValidationFailed,
+			map[string]string{DetailField: "ServerID", DetailTag: "required"},
+		},
+		{"MissingKey", keyauth.ErrMissingOrMalformedAPIKey, CodeUnauthorized, nil},
+		{"InsufficientRole", fmt.Errorf("%w: admin is required", ErrInsufficientRole), CodeForbidden, nil},
+		{"DeadlineExceeded", context.DeadlineExceeded, CodeTimeout, nil},
+		{"FiberBadRequest", fiber.ErrBadRequest, CodeValidationFailed, nil},
+		{"FiberNotFound", fiber.ErrNotFound, CodeNotFound, nil},
+		{"FiberConflict", fiber.ErrConflict, CodeConflict, nil},
+		{"FiberTimeout", fiber.ErrRequestTimeout, CodeTimeout, nil},
+		{"FiberTeapot", fiber.ErrTeapot, CodeInternal, nil},
+		{"Unknown", errors.New("some error"), CodeInternal, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := AsAPIError(tt.err)
+			assert.Equal(t, tt.code, result.Code)
+			assert.Equal(t, tt.details, result.Details)
+			require.ErrorIs(t, tt.err, result.Err)
+		})
+	}
+}
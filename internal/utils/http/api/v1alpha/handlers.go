@@ -22,6 +22,7 @@ func VersionHandler(c *fiber.Ctx) error {
 	return WrapResponse(c, StatusSuccess, &Version{Version: utils.AppVersion()}, nil)
 }
 
+// ErrorHandler responds with the error code, details and the HTTP status matching the code
 func ErrorHandler(c *fiber.Ctx, err error) error {
-	return WrapResponse(c, StatusError, nil, err)
+	return WrapError(c, AsAPIError(err))
 }
@@ -51,7 +51,7 @@ func TestErrorHandler(t *testing.T) {
 	body, err := io.ReadAll(resp.Body)
 	require.NoError(t, err)
 
-	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
-	assert.JSONEq(t, `{"status":"error","error":"test error","data":{}}`, string(body))
+	assert.JSONEq(t, `{"status":"error","error":"test error","code":"internal","data":{}}`, string(body))
 }
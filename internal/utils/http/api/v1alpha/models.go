@@ -18,6 +18,12 @@ type Response struct {
 	Data any `json:"data" swaggertype:"object"`
 	// Error message. If status is not success, this field must be filled by a string with error message
 	Error error `example:"" json:"error" swaggertype:"string"`
+	// Machine-readable error code. Set only if status is error:
+	// not_leader, validation_failed, unauthorized, forbidden, not_found, conflict, timeout, internal,
+	// not_implemented
+	Code ErrorCode `example:"not_leader" json:"code,omitempty" swaggertype:"string"`
+	// Structured error details, e.g. the leader address or the failing field
+	Details map[string]string `json:"details,omitempty"`
 } // @Name Response
 
 // Version
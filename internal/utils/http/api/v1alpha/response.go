@@ -11,3 +11,12 @@ func WrapResponse(c *fiber.Ctx, status Status, data any, err error) error {
 		Error:  err,
 	})
 }
+
+func WrapError(c *fiber.Ctx, apiErr *APIError) error {
+	return c.Status(apiErr.Code.Status()).JSON(Response{
+		Status:  StatusError,
+		Error:   apiErr,
+		Code:    apiErr.Code,
+		Details: apiErr.Details,
+	})
+}
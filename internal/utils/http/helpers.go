@@ -1,26 +1,28 @@
 package http
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
-	"time"
+	"io/fs"
+	"net"
+	"os"
 
 	"github.com/gofiber/contrib/fibersentry"
 	"github.com/gofiber/contrib/fiberzerolog"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/healthcheck"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/rs/zerolog"
 
 	"github.com/weastur/maf/internal/utils"
+	"github.com/weastur/maf/internal/utils/certs"
 	apiUtils "github.com/weastur/maf/internal/utils/http/api"
 )
 
 const (
-	defaultRateLimit           = 100
-	defaultRateLimitExpiration = 30 * time.Second
-	APIPrefix                  = "/api"
+	APIPrefix = "/api"
 )
 
 type API interface {
@@ -29,10 +31,17 @@ type API interface {
 
 type Listener interface {
 	Listen(addr string) error
-	ListenTLS(addr, certFile, keyFile string) error
-	ListenMutualTLS(addr, certFile, keyFile, clientCertFile string) error
+	Listener(ln net.Listener) error
 }
 
+type SocketListener interface {
+	Listener(ln net.Listener) error
+}
+
+var ErrSocketInUse = errors.New("socket is in use")
+
+// Listen serves the app with TLS if the cert is set, and with mutual TLS if the client cert is set too.
+// The certs are reloaded in place when the files change, so the rotation doesn't require a restart
 func Listen(
 	app Listener,
 	logger zerolog.Logger,
@@ -41,25 +50,88 @@ func Listen(
 	keyFile string,
 	clientCertFile string,
 ) error {
-	switch {
-	case clientCertFile != "":
-		logger.Info().Msgf("Listening with mutual TLS on %s", addr)
-
-		if err := app.ListenMutualTLS(addr, certFile, keyFile, clientCertFile); err != nil {
-			return fmt.Errorf("failed to listen with mutual TLS: %w", err)
-		}
-	case certFile != "" && keyFile != "":
-		logger.Info().Msgf("Listening with TLS on %s", addr)
+	store, err := certs.NewServerStore(certFile, keyFile, clientCertFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certs: %w", err)
+	}
 
-		if err := app.ListenTLS(addr, certFile, keyFile); err != nil {
-			return fmt.Errorf("failed to listen with TLS: %w", err)
-		}
-	default:
+	if store == nil {
 		logger.Info().Msgf("Listening on %s", addr)
 
 		if err := app.Listen(addr); err != nil {
 			return fmt.Errorf("failed to listen: %w", err)
 		}
+
+		return nil
+	}
+	defer store.Close()
+
+	ln, err := tls.Listen("tcp", addr, store.ServerConfig())
+	if err != nil {
+		return fmt.Errorf("failed to listen with TLS: %w", err)
+	}
+
+	if clientCertFile != "" {
+		logger.Info().Msgf("Listening with mutual TLS on %s", addr)
+	} else {
+		logger.Info().Msgf("Listening with TLS on %s", addr)
+	}
+
+	if err := app.Listener(ln); err != nil {
+		return fmt.Errorf("failed to serve with TLS: %w", err)
+	}
+
+	return nil
+}
+
+// ListenSocket serves the app on the Unix socket. The access is trusted to whoever can connect,
+// so the socket is restricted by the file mode before serving. A stale socket left by the crashed
+// process is removed, but the one still accepting connections is not.
+func ListenSocket(app SocketListener, logger zerolog.Logger, path string, mode fs.FileMode) error {
+	if err := removeStaleSocket(path); err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on socket: %w", err)
+	}
+
+	if err := os.Chmod(path, mode); err != nil {
+		ln.Close()
+
+		return fmt.Errorf("failed to set socket mode: %w", err)
+	}
+
+	logger.Info().Msgf("Listening on socket %s", path)
+
+	if err := app.Listener(ln); err != nil {
+		return fmt.Errorf("failed to serve on socket: %w", err)
+	}
+
+	return nil
+}
+
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to stat socket: %w", err)
+	}
+
+	if info.Mode().Type() != fs.ModeSocket {
+		return fmt.Errorf("%w: %s is not a socket", ErrSocketInUse, path)
+	}
+
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+
+		return fmt.Errorf("%w: %s", ErrSocketInUse, path)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove stale socket: %w", err)
 	}
 
 	return nil
@@ -81,7 +153,35 @@ func APIVersionGroup(api fiber.Router, version string) fiber.Router {
 	})
 }
 
-func AttachGenericMiddlewares(app *fiber.App, logger zerolog.Logger, healthchecker Healthchecker) {
+// APIVersionsHandler lists the mounted API versions, the newest first, so the clients can negotiate one
+func APIVersionsHandler(versions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"versions": versions})
+	}
+}
+
+// DeprecatedAPIVersion marks the responses as deprecated and points the clients to the successor version
+func DeprecatedAPIVersion(successor string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set("Deprecation", "true")
+		c.Set(fiber.HeaderLink, fmt.Sprintf(`<%s/%s>; rel="successor-version"`, APIPrefix, successor))
+
+		return c.Next()
+	}
+}
+
+// AttachGenericMiddlewares attaches the middlewares shared by all the APIs. The default rate limits are used
+// if the rate limiter is nil
+func AttachGenericMiddlewares(
+	app *fiber.App,
+	logger zerolog.Logger,
+	healthchecker Healthchecker,
+	rateLimiter *RateLimiter,
+) {
+	if rateLimiter == nil {
+		rateLimiter = NewRateLimiter(DefaultRateLimit, DefaultRateLimitWindow)
+	}
+
 	app.Use(fibersentry.New(fibersentry.Config{
 		Repanic:         true,
 		WaitForDelivery: true,
@@ -105,10 +205,8 @@ func AttachGenericMiddlewares(app *fiber.App, logger zerolog.Logger, healthcheck
 			ContextKey: apiUtils.RequestIDContextKey,
 		},
 	))
-	app.Use(limiter.New(limiter.Config{
-		Max:        defaultRateLimit,
-		Expiration: defaultRateLimitExpiration,
-	}))
+	app.Use(MetricsMiddleware())
+	app.Use(rateLimiter.Handler())
 	app.Use(healthcheck.New(healthcheck.Config{
 		LivenessProbe:  healthchecker.IsLive,
 		ReadinessProbe: healthchecker.IsReady,
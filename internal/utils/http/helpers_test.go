@@ -2,10 +2,22 @@ package http
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"io"
+	"io/fs"
+	"math/big"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog"
@@ -41,26 +53,56 @@ func (m *MockListener) Listen(addr string) error {
 	return args.Error(0)
 }
 
-func (m *MockListener) ListenTLS(addr, certFile, keyFile string) error {
-	args := m.Called(addr, certFile, keyFile)
+func (m *MockListener) Listener(ln net.Listener) error {
+	args := m.Called(ln)
 
 	return args.Error(0)
 }
 
-func (m *MockListener) ListenMutualTLS(addr, certFile, keyFile, clientCertFile string) error {
-	args := m.Called(addr, certFile, keyFile, clientCertFile)
+// writeTestCert writes the self-signed cert and its key to the dir
+func writeTestCert(t *testing.T, dir string) (string, string) {
+	t.Helper()
 
-	return args.Error(0)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "maf"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
 }
 
 func TestListen(t *testing.T) {
 	t.Parallel()
 
 	logger := zerolog.Nop()
+	certFile, keyFile := writeTestCert(t, t.TempDir())
 
-	errMutualTLS := errors.New("mutual TLS error")
-	errTLS := errors.New("TLS error")
 	errListen := errors.New("listen error")
+	errServe := errors.New("serve error")
+
+	// closeListener stops the TLS listener handed to the app, the way the app shutdown does
+	closeListener := func(args mock.Arguments) {
+		args.Get(0).(net.Listener).Close()
+	}
 
 	tests := []struct {
 		name           string
@@ -73,65 +115,46 @@ func TestListen(t *testing.T) {
 	}{
 		{
 			name:           "Listen with mutual TLS",
-			addr:           "localhost:8080",
-			certFile:       "cert.pem",
-			keyFile:        "key.pem",
-			clientCertFile: "clientCert.pem",
+			addr:           "127.0.0.1:0",
+			certFile:       certFile,
+			keyFile:        keyFile,
+			clientCertFile: certFile,
 			setupMock: func(m *MockListener) {
-				m.On("ListenMutualTLS", "localhost:8080", "cert.pem", "key.pem", "clientCert.pem").Return(nil)
+				m.On("Listener", mock.Anything).Run(closeListener).Return(nil)
 			},
 			expectedError: nil,
 		},
 		{
-			name:           "Listen with TLS",
-			addr:           "localhost:8080",
-			certFile:       "cert.pem",
-			keyFile:        "key.pem",
-			clientCertFile: "",
+			name:     "Listen with TLS",
+			addr:     "127.0.0.1:0",
+			certFile: certFile,
+			keyFile:  keyFile,
 			setupMock: func(m *MockListener) {
-				m.On("ListenTLS", "localhost:8080", "cert.pem", "key.pem").Return(nil)
+				m.On("Listener", mock.Anything).Run(closeListener).Return(nil)
 			},
 			expectedError: nil,
 		},
 		{
-			name:           "Listen without TLS",
-			addr:           "localhost:8080",
-			certFile:       "",
-			keyFile:        "",
-			clientCertFile: "",
+			name: "Listen without TLS",
+			addr: "localhost:8080",
 			setupMock: func(m *MockListener) {
 				m.On("Listen", "localhost:8080").Return(nil)
 			},
 			expectedError: nil,
 		},
 		{
-			name:           "Error in ListenMutualTLS",
-			addr:           "localhost:8080",
-			certFile:       "cert.pem",
-			keyFile:        "key.pem",
-			clientCertFile: "clientCert.pem",
-			setupMock: func(m *MockListener) {
-				m.On("ListenMutualTLS", "localhost:8080", "cert.pem", "key.pem", "clientCert.pem").Return(errMutualTLS)
-			},
-			expectedError: errMutualTLS,
-		},
-		{
-			name:           "Error in ListenTLS",
-			addr:           "localhost:8080",
-			certFile:       "cert.pem",
-			keyFile:        "key.pem",
-			clientCertFile: "",
+			name:     "Error in TLS serve",
+			addr:     "127.0.0.1:0",
+			certFile: certFile,
+			keyFile:  keyFile,
 			setupMock: func(m *MockListener) {
-				m.On("ListenTLS", "localhost:8080", "cert.pem", "key.pem").Return(errTLS)
+				m.On("Listener", mock.Anything).Run(closeListener).Return(errServe)
 			},
-			expectedError: errTLS,
+			expectedError: errServe,
 		},
 		{
-			name:           "Error in Listen",
-			addr:           "localhost:8080",
-			certFile:       "",
-			keyFile:        "",
-			clientCertFile: "",
+			name: "Error in Listen",
+			addr: "localhost:8080",
 			setupMock: func(m *MockListener) {
 				m.On("Listen", "localhost:8080").Return(errListen)
 			},
@@ -152,6 +175,104 @@ func TestListen(t *testing.T) {
 			mockListener.AssertExpectations(t)
 		})
 	}
+
+	t.Run("Invalid cert", func(t *testing.T) {
+		t.Parallel()
+
+		mockListener := new(MockListener)
+
+		err := Listen(mockListener, logger, "127.0.0.1:0", keyFile, keyFile, "")
+		require.ErrorContains(t, err, "failed to load certs")
+		mockListener.AssertNotCalled(t, "Listener", mock.Anything)
+	})
+}
+
+type MockSocketListener struct {
+	mock.Mock
+}
+
+func (m *MockSocketListener) Listener(ln net.Listener) error {
+	args := m.Called(ln)
+
+	return args.Error(0)
+}
+
+func TestListenSocket(t *testing.T) {
+	t.Parallel()
+
+	logger := zerolog.Nop()
+
+	t.Run("Serve", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "maf.sock")
+
+		mockListener := new(MockSocketListener)
+		mockListener.On("Listener", mock.Anything).Run(func(args mock.Arguments) {
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, fs.FileMode(0o600), info.Mode().Perm())
+
+			args.Get(0).(net.Listener).Close()
+		}).Return(nil)
+
+		require.NoError(t, ListenSocket(mockListener, logger, path, 0o600))
+		mockListener.AssertExpectations(t)
+	})
+
+	t.Run("StaleSocket", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "maf.sock")
+
+		ln, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		ln.(*net.UnixListener).SetUnlinkOnClose(false)
+		ln.Close()
+
+		mockListener := new(MockSocketListener)
+		mockListener.On("Listener", mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(0).(net.Listener).Close()
+		}).Return(nil)
+
+		require.NoError(t, ListenSocket(mockListener, logger, path, 0o660))
+	})
+
+	t.Run("SocketInUse", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "maf.sock")
+
+		ln, err := net.Listen("unix", path)
+		require.NoError(t, err)
+
+		defer ln.Close()
+
+		err = ListenSocket(new(MockSocketListener), logger, path, 0o600)
+		require.ErrorIs(t, err, ErrSocketInUse)
+	})
+
+	t.Run("NotASocket", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "maf.sock")
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+		err := ListenSocket(new(MockSocketListener), logger, path, 0o600)
+		require.ErrorIs(t, err, ErrSocketInUse)
+	})
+
+	t.Run("ServeError", func(t *testing.T) {
+		t.Parallel()
+
+		errServe := errors.New("serve error")
+
+		mockListener := new(MockSocketListener)
+		mockListener.On("Listener", mock.Anything).Return(errServe)
+
+		err := ListenSocket(mockListener, logger, filepath.Join(t.TempDir(), "maf.sock"), 0o600)
+		require.ErrorIs(t, err, errServe)
+	})
 }
 
 func TestAPIGroup(t *testing.T) {
@@ -188,6 +309,42 @@ func TestAPIVersionGroup(t *testing.T) {
 	assert.Equal(t, "v1", resp.Header.Get("X-Api-Version"))
 }
 
+func TestAPIVersionsHandler(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	APIGroup(app).Get("/versions", APIVersionsHandler("v1", "v1alpha"))
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/versions", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"versions":["v1","v1alpha"]}`, string(body))
+}
+
+func TestDeprecatedAPIVersion(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	versionGroup := APIVersionGroup(APIGroup(app), "v1alpha")
+	versionGroup.Use(DeprecatedAPIVersion("v1"))
+
+	versionGroup.Get("/test", func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1alpha/test", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("Deprecation"))
+	assert.Equal(t, `</api/v1>; rel="successor-version"`, resp.Header.Get("Link"))
+}
+
 func TestAttachGenericMiddlewares(t *testing.T) {
 	t.Parallel()
 
@@ -195,7 +352,7 @@ func TestAttachGenericMiddlewares(t *testing.T) {
 	logger := zerolog.Nop()
 	healthchecker := &mockHealthchecker{}
 
-	AttachGenericMiddlewares(app, logger, healthchecker)
+	AttachGenericMiddlewares(app, logger, healthchecker, nil)
 
 	app.Get("/test", func(c *fiber.Ctx) error {
 		return c.SendStatus(http.StatusOK)
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MetricsMiddleware counts the requests and measures their latency by route and status.
+// The route is the registered path, e.g. /api/v1alpha/raft/kv/:key, to keep the cardinality low.
+func MetricsMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		// Errors are handled here rather than by the app, so the status reflects the actual response
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		labels := fmt.Sprintf(
+			`{method=%q,route=%q,status="%s"}`,
+			c.Method(),
+			c.Route().Path,
+			strconv.Itoa(c.Response().StatusCode()),
+		)
+
+		metrics.GetOrCreateCounter("maf_http_requests_total" + labels).Inc()
+		metrics.GetOrCreateHistogram("maf_http_request_duration_seconds" + labels).UpdateDuration(start)
+
+		return nil
+	}
+}
+
+func rateLimitReached(c *fiber.Ctx) error {
+	metrics.GetOrCreateCounter("maf_http_rate_limited_total").Inc()
+
+	return c.SendStatus(fiber.StatusTooManyRequests)
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	app := fiber.New()
+	app.Use(MetricsMiddleware())
+	app.Get("/metrics-test/:key", func(c *fiber.Ctx) error {
+		if c.Params("key") == "missing" {
+			return fiber.ErrNotFound
+		}
+
+		return c.SendStatus(http.StatusOK)
+	})
+
+	for _, path := range []string{"/metrics-test/a", "/metrics-test/b", "/metrics-test/missing"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		_, err := app.Test(req)
+		require.NoError(t, err)
+	}
+
+	ok := metrics.GetOrCreateCounter(`maf_http_requests_total{method="GET",route="/metrics-test/:key",status="200"}`)
+	notFound := metrics.GetOrCreateCounter(
+		`maf_http_requests_total{method="GET",route="/metrics-test/:key",status="404"}`,
+	)
+
+	assert.Equal(t, uint64(2), ok.Get())
+	assert.Equal(t, uint64(1), notFound.Get())
+}
+
+func TestRateLimitReached(t *testing.T) {
+	t.Parallel()
+
+	counter := metrics.GetOrCreateCounter("maf_http_rate_limited_total")
+	before := counter.Get()
+
+	app := fiber.New()
+	app.Get("/", rateLimitReached)
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, before+1, counter.Get())
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+const (
+	DefaultRateLimit       = 100
+	DefaultRateLimitWindow = 30 * time.Second
+)
+
+// RateLimiter limits the number of requests per client IP within the window. The limits can be changed
+// at runtime, e.g. on the config reload, the counters start over then.
+type RateLimiter struct {
+	handler atomic.Pointer[fiber.Handler]
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	r := &RateLimiter{}
+	r.Set(limit, window)
+
+	return r
+}
+
+func (r *RateLimiter) Set(limit int, window time.Duration) {
+	handler := limiter.New(limiter.Config{
+		Max:          limit,
+		Expiration:   window,
+		LimitReached: rateLimitReached,
+	})
+
+	r.handler.Store(&handler)
+}
+
+func (r *RateLimiter) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return (*r.handler.Load())(c)
+	}
+}
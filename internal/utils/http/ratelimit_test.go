@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	rateLimiter := NewRateLimiter(1, time.Minute)
+
+	app := fiber.New()
+	app.Use(rateLimiter.Handler())
+	app.Get("/test", func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+
+	status := func() int {
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, status())
+	assert.Equal(t, http.StatusTooManyRequests, status())
+
+	// The new limits apply to the running app, with the counters started over
+	rateLimiter.Set(2, time.Minute)
+
+	assert.Equal(t, http.StatusOK, status())
+	assert.Equal(t, http.StatusOK, status())
+	assert.Equal(t, http.StatusTooManyRequests, status())
+}
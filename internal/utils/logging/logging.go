@@ -58,6 +58,11 @@ func Init(level string, pretty bool, sentry Sentry) error {
 	zerolog.FloatingPointPrecision = 3
 	zerolog.DisableSampling(true)
 
+	return SetLevel(level)
+}
+
+// SetLevel changes the level of the running loggers, e.g. on the config reload
+func SetLevel(level string) error {
 	zLevel, err := zerolog.ParseLevel(level)
 	if err != nil {
 		return fmt.Errorf("failed to parse log level: %w", err)
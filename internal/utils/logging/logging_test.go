@@ -61,3 +61,11 @@ func TestInit(t *testing.T) {
 		})
 	}
 }
+
+func TestSetLevel(t *testing.T) {
+	require.NoError(t, SetLevel("warn"))
+	assert.Equal(t, zerolog.WarnLevel, zerolog.GlobalLevel())
+
+	require.Error(t, SetLevel("invalid"))
+	assert.Equal(t, zerolog.WarnLevel, zerolog.GlobalLevel())
+}
@@ -8,7 +8,7 @@ import (
 )
 
 func ConfigureMetrics(app *fiber.App) {
-	metrics.NewGauge(fmt.Sprintf("maf_version{version=\"%s\"}", AppVersion()), func() float64 {
+	metrics.GetOrCreateGauge(fmt.Sprintf("maf_version{version=\"%s\"}", AppVersion()), func() float64 {
 		return 1
 	})
 